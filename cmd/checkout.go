@@ -1,17 +1,29 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/velvee-ai/ai-workflow/pkg/cache"
 	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/tracker"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+var (
+	checkoutCreate bool
+	checkoutFrom   string
 )
 
 // No in-memory cache needed - bbolt is fast enough for direct reads
@@ -25,14 +37,22 @@ Direct Usage (with autocomplete):
   work checkout <repo> <branch>
 
   This will create or switch to a worktree for the specified branch in the given repository.
-  The repo name should match a directory in your configured git folder.
+  The repo is a bare name, org/repo (e.g. acme-labs/api) when several orgs have a
+  repository of that name, or host/owner/repo for repositories on other hosts.
+
+  With --create the branch is created locally without contacting the remote, from
+  origin/<default> or the ref given with --from (a branch, tag or commit). It gets
+  its upstream on the first push.
+
+  work checkout api feature-x --create
+  work checkout api hotfix-1.2 --from v1.2.0
 
 Subcommands:
   work checkout root <url>     - Clone a new repository
   work checkout branch <name>  - Checkout branch in current repo`,
 	Args:              cobra.MaximumNArgs(2),
 	ValidArgsFunction: completeGitRepos,
-	Run:               runCheckoutDirect,
+	RunE:              runCheckoutDirect,
 }
 
 var checkoutRootCmd = &cobra.Command{
@@ -48,40 +68,54 @@ Example:
 
 This creates:
   repo/
-    └── main/  (cloned repository)`,
+    └── main/  (cloned repository)
+
+Repositories on hosts other than github.com are placed under <host>/<owner>/<repo>/.`,
 	Args: cobra.ExactArgs(1),
-	Run:  runCheckoutRoot,
+	RunE: runCheckoutRoot,
 }
 
 var checkoutBranchCmd = &cobra.Command{
-	Use:   "branch <branch-name-or-github-issue-url>",
+	Use:   "branch <branch-name-or-issue>",
 	Short: "Checkout branch using git worktree",
 	Long: `Create or switch to a git worktree for a branch.
 
 Supports:
 - Branch names: Creates/switches to worktree for the branch
-- GitHub issue URLs: Creates branch from issue and sets up worktree
+- Issues: an issue URL, owner/repo#42 or #42 (an issue of this repository).
+  The branch is named by issue_branch_template (default
+  {{.Number}}-{{.Title | slug | trunc 40}}); a branch made for the issue earlier
+  is reused
+- Tickets: a Jira or Linear key (PROJ-123) or URL, looked up in the configured
+  trackers. New branches move the ticket to In Progress
+
+New worktrees are added from origin after a fetch: an existing local branch is
+checked out, origin/<branch> is tracked when it exists, and other branches start
+from origin/<default>. The main/ working copy is never switched or pulled.
 
 Example:
   work checkout branch feature-123
+  work checkout branch feature-123 --from develop   # New local branch, offline
   work checkout branch https://github.com/user/repo/issues/42
+  work checkout branch acme-labs/api#42
+  work checkout branch PROJ-123
 
 This creates a worktree in the container folder:
   repo/
     ├── main/
     └── feature-123/  (worktree)`,
 	Args: cobra.ExactArgs(1),
-	Run:  runCheckoutBranch,
+	RunE: runCheckoutBranch,
 }
 
 var checkoutNewCmd = &cobra.Command{
 	Use:   "new <repo> <branch>",
-	Short: "Create a remote branch via GitHub and checkout locally",
-	Long: `Create a new branch on GitHub from the base branch (default: main) and then
-create a local worktree for it.
+	Short: "Create a remote branch via the forge API and checkout locally",
+	Long: `Create a new branch on the repository's forge (GitHub, GitLab or Gitea/Forgejo)
+from the base branch (default: main) and then create a local worktree for it.
 
 This command:
-1. Creates the branch remotely via GitHub CLI (gh)
+1. Creates the branch remotely via the forge API
 2. Uses the configured checkout_base_branch (default: main) as the base
 3. Creates/switches to a local worktree for the new branch
 4. Opens the worktree in your configured IDE
@@ -95,126 +129,59 @@ If the branch already exists remotely, the command will continue and just
 create the local worktree.`,
 	Args:              cobra.ExactArgs(2),
 	ValidArgsFunction: completeGitRepos,
-	Run:               runCheckoutNew,
+	RunE:              runCheckoutNew,
 }
 
-func runCheckoutDirect(cmd *cobra.Command, args []string) {
+// CheckoutResult is the structured result of the checkout commands.
+type CheckoutResult struct {
+	Repo        string `json:"repo"`
+	Branch      string `json:"branch"`
+	Path        string `json:"path"`
+	Created     bool   `json:"created"`                // A new worktree (or clone) was created
+	Cloned      bool   `json:"cloned"`                 // The repository was cloned first
+	PullRequest string `json:"pull_request,omitempty"` // Set by 'checkout pr'
+}
+
+func runCheckoutDirect(cmd *cobra.Command, args []string) error {
 	// If no args, show help
 	if len(args) == 0 {
-		cmd.Help()
-		return
+		return cmd.Help()
 	}
 
 	// If only 1 arg, it might be a subcommand (handled by cobra) or invalid
 	if len(args) == 1 {
-		fmt.Fprintf(os.Stderr, "Error: Please provide both repo and branch name\n")
-		fmt.Fprintf(os.Stderr, "Usage: work checkout <repo> <branch>\n")
-		os.Exit(1)
+		return errs.New(errs.Usage, "please provide both repo and branch name").
+			WithHint("Usage: work checkout <repo> <branch>")
 	}
 
 	repoName := args[0]
 	branchName := args[1]
 
-	checkoutRepoBranch(repoName, branchName)
+	return checkoutRepoBranch(repoName, branchName, checkoutNewBranchOptions())
 }
 
 // checkoutRepoBranch performs the actual checkout/worktree creation logic.
 // This is the shared implementation used by both direct checkout and new branch creation.
-func checkoutRepoBranch(repoName, branchName string) {
-	// Get git folder from config
-	gitFolder := config.GetString("default_git_folder")
-	if gitFolder == "" {
-		fmt.Fprintf(os.Stderr, "Error: default_git_folder not configured\n")
-		fmt.Fprintf(os.Stderr, "Run: work config set default_git_folder ~/git\n")
-		os.Exit(1)
-	}
-
-	// Expand home directory if needed
-	if strings.HasPrefix(gitFolder, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Could not get home directory: %v\n", err)
-			os.Exit(1)
-		}
-		gitFolder = filepath.Join(homeDir, gitFolder[2:])
-	}
-
-	// Build paths
-	containerRoot := filepath.Join(gitFolder, repoName)
-	gitRoot := filepath.Join(containerRoot, "main")
-
-	// Check if repo exists, if not, try to auto-clone
-	if _, err := os.Stat(gitRoot); os.IsNotExist(err) {
-		fmt.Printf("Repository '%s' not found locally, attempting to clone...\n", repoName)
-
-		// Get clone URL from GitHub
-		cloneURL := getRepoCloneURL(repoName)
-		if cloneURL == "" {
-			fmt.Fprintf(os.Stderr, "Error: Could not find repository '%s' in configured orgs\n", repoName)
-			fmt.Fprintf(os.Stderr, "Run: work checkout root <git-url> to clone manually\n")
-			os.Exit(1)
-		}
-
-		// Clone the repository
-		if err := cloneRepository(cloneURL, repoName, gitFolder); err != nil {
-			fmt.Fprintf(os.Stderr, "Error cloning repository: %v\n", err)
-			os.Exit(1)
-		}
-
-		fmt.Printf("Successfully cloned '%s'\n", repoName)
-	}
-
-	// Change to git root for operations
-	if err := os.Chdir(gitRoot); err != nil {
-		fmt.Fprintf(os.Stderr, "Error changing to git root: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Switch to default branch and pull latest
-	defaultBranch := getDefaultBranch(gitRoot)
-	if err := runGitCommand("switch", defaultBranch); err != nil {
-		fmt.Fprintf(os.Stderr, "Error switching to %s: %v\n", defaultBranch, err)
-		os.Exit(1)
+func checkoutRepoBranch(repoName, branchName string, opts newBranchOptions) error {
+	ws := worktreeManager()
+	containerRoot, cloned, err := ensureContainer(repoName)
+	if err != nil {
+		return err
 	}
-
-	if err := runGitCommand("pull", "--rebase"); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Could not pull latest changes: %v\n", err)
+	if err := refreshRepo(containerRoot, opts.Create); err != nil {
+		return err
 	}
 
-	// Create worktree path
-	worktreePath := filepath.Join(containerRoot, branchName)
-
-	// Check if worktree already exists
-	var worktreeExists bool
-	if info, err := os.Stat(worktreePath); err == nil && info.IsDir() {
-		if isGitWorktree(worktreePath) {
-			currentBranch := getCurrentBranch(worktreePath)
-			if currentBranch == branchName {
-				worktreeExists = true
-				fmt.Printf("Switching to existing worktree for branch '%s'\n", branchName)
-			} else {
-				fmt.Fprintf(os.Stderr, "Error: Folder '%s' exists but is on branch '%s', not '%s'\n",
-					worktreePath, currentBranch, branchName)
-				fmt.Fprintf(os.Stderr, "Please clean up with: git worktree remove '%s'\n", worktreePath)
-				os.Exit(1)
-			}
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: Folder '%s' exists but is not a git worktree\n", worktreePath)
-			os.Exit(1)
-		}
-	} else {
-		// Create new worktree
-		if err := runGitCommand("worktree", "add", worktreePath, branchName); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating worktree: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Printf("Created worktree for branch '%s'\n", branchName)
+	// Reuse the branch's worktree wherever it lives, otherwise create one
+	worktreePath, base, worktreeExists, err := prepareWorktree(containerRoot, branchName, opts)
+	if err != nil {
+		return err
 	}
+	recordWorktree(containerRoot, worktreePath, branchName, "", base, !worktreeExists)
 
 	// Change to the worktree directory
 	if err := os.Chdir(worktreePath); err != nil {
-		fmt.Fprintf(os.Stderr, "Error changing to worktree: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("changing to worktree: %w", err)
 	}
 
 	// If worktree already existed, try to sync it
@@ -224,140 +191,150 @@ func checkoutRepoBranch(repoName, branchName string) {
 		cmd.Dir = worktreePath
 		if err := cmd.Run(); err != nil {
 			// Silently ignore errors (uncommitted changes, etc.)
-			fmt.Printf("Note: Could not sync with remote (you may have uncommitted changes)\n")
+			printer.Printf("Note: Could not sync with remote (you may have uncommitted changes)\n")
 		} else {
-			fmt.Printf("Synced with remote\n")
+			printer.Printf("Synced with remote\n")
 		}
 	}
 
 	absPath, _ := filepath.Abs(worktreePath)
-	fmt.Printf("Path: %s\n", absPath)
+	printer.Printf("Path: %s\n", absPath)
 
 	// Run post-checkout actions (custom script or IDE fallback)
 	runPostCheckoutActions(worktreePath)
+
+	return render(CheckoutResult{
+		Repo:    ws.KeyForPath(containerRoot),
+		Branch:  branchName,
+		Path:    absPath,
+		Created: !worktreeExists,
+		Cloned:  cloned,
+	})
 }
 
-func runCheckoutRoot(cmd *cobra.Command, args []string) {
+// ensureContainer returns the container of repoName. Repositories that are not cloned
+// yet are resolved against the forges and cloned; cloned reports whether that happened.
+func ensureContainer(repoName string) (containerRoot string, cloned bool, err error) {
+	if _, err := getGitFolder(); err != nil {
+		return "", false, err
+	}
+	ws := worktreeManager()
+
+	if containerRoot, found := ws.Resolve(repoName); found {
+		return containerRoot, false, nil
+	}
+	printer.Printf("Repository '%s' not found locally, attempting to clone...\n", repoName)
+
+	client, repo := findRemoteRepoByKey(repoName)
+	if repo == nil {
+		return "", false, errRepoNotFound(repoName)
+	}
+
+	// Clone into the layout folder; same-named repos from other orgs or hosts never collide
+	containerRoot = ws.ContainerFor(client.Host(), repo.Owner, repo.Name)
+	if workspace.IsContainer(containerRoot) {
+		return containerRoot, false, nil
+	}
+	if _, err := cloneRepository(repo.CloneURL, containerRoot); err != nil {
+		return "", false, err
+	}
+	printer.Printf("Successfully cloned '%s'\n", repoName)
+	return containerRoot, true, nil
+}
+
+func runCheckoutRoot(cmd *cobra.Command, args []string) error {
 	gitURL := args[0]
 
 	// Extract repo name from URL
 	repoName := extractRepoName(gitURL)
 	if repoName == "" {
-		fmt.Fprintf(os.Stderr, "Error: Could not extract repository name from URL\n")
-		os.Exit(1)
+		return errs.New(errs.Usage, "could not extract repository name from URL")
 	}
 
-	// Get git folder from config
-	gitFolder := config.GetString("default_git_folder")
-	if gitFolder == "" {
-		fmt.Fprintf(os.Stderr, "Error: default_git_folder not configured\n")
-		fmt.Fprintf(os.Stderr, "Run: work config set default_git_folder ~/git\n")
-		os.Exit(1)
+	gitFolder, err := getGitFolder()
+	if err != nil {
+		return err
 	}
 
-	// Expand home directory if needed
-	if strings.HasPrefix(gitFolder, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Could not get home directory: %v\n", err)
-			os.Exit(1)
-		}
-		gitFolder = filepath.Join(homeDir, gitFolder[2:])
+	// Derive the folder from the URL: <repo> or <org>/<repo> on github.com, <host>/<owner>/<repo> elsewhere
+	ws := worktreeManager()
+	containerPath, err := ws.ContainerForURL(gitURL)
+	if err != nil {
+		containerPath = filepath.Join(gitFolder, repoName)
 	}
 
 	// Clone the repository
-	if err := cloneRepository(gitURL, repoName, gitFolder); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	worktreePath, err := cloneRepository(gitURL, containerPath)
+	if err != nil {
+		return err
 	}
 
-	mainPath := filepath.Join(gitFolder, repoName, "main")
-	absPath, _ := filepath.Abs(mainPath)
-	fmt.Printf("Repository cloned to %s\n", absPath)
+	absPath, _ := filepath.Abs(worktreePath)
+	printer.Printf("Repository cloned to %s\n", absPath)
+
+	return render(CheckoutResult{
+		Repo:    ws.KeyForPath(containerPath),
+		Branch:  getCurrentBranch(worktreePath),
+		Path:    absPath,
+		Created: true,
+		Cloned:  true,
+	})
 }
 
-func runCheckoutBranch(cmd *cobra.Command, args []string) {
+func runCheckoutBranch(cmd *cobra.Command, args []string) error {
 	arg := args[0]
 	var branchName string
-	var gitRoot, containerRoot string
 
-	// Determine if we're in a git repo or container folder
-	if isInsideGitRepo() {
-		// We're inside a git repo (branch folder)
-		var err error
-		gitRoot, err = getGitRoot()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-		containerRoot = filepath.Dir(gitRoot)
-	} else if _, err := os.Stat("main/.git"); err == nil {
-		// We're in container folder with main subfolder
-		containerRoot, _ = os.Getwd()
-		gitRoot = filepath.Join(containerRoot, "main")
-	} else {
-		fmt.Fprintf(os.Stderr, "Error: Not in a git repo or container folder with main subfolder\n")
-		os.Exit(1)
+	opts := checkoutNewBranchOptions()
+	issueRef, isIssue := tracker.ParseRef(arg)
+	if isIssue && issueRef.IsTicket() && issueRef.Host == "" {
+		// PROJ-123 is only a ticket when a tracker handles the project
+		_, isIssue = services.Get().Trackers.ForKey(issueRef.Key)
 	}
-
-	// Change to git root for operations
-	if err := os.Chdir(gitRoot); err != nil {
-		fmt.Fprintf(os.Stderr, "Error changing to git root: %v\n", err)
-		os.Exit(1)
+	if opts.Create && isIssue {
+		return errs.New(errs.Usage, "--create and --from take a branch name, not an issue")
 	}
 
-	// Switch to default branch and pull latest
-	defaultBranch := getDefaultBranch(gitRoot)
-	if err := runGitCommand("switch", defaultBranch); err != nil {
-		fmt.Fprintf(os.Stderr, "Error switching to %s: %v\n", defaultBranch, err)
-		os.Exit(1)
+	// Find the container from the container folder, main/ or any worktree below it
+	containerRoot, err := worktreeManager().FindContainerFromCwd()
+	if err != nil {
+		return err
 	}
-
-	if err := runGitCommand("pull", "--rebase"); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Could not pull latest changes: %v\n", err)
+	if err := refreshRepo(containerRoot, opts.Create); err != nil {
+		return err
 	}
 
-	// Handle GitHub issue URL vs regular branch name
-	if isGitHubIssueURL(arg) {
-		branchName = handleGitHubIssue(arg)
+	// Handle issue references vs regular branch names
+	var issue *tracker.Issue
+	if isIssue {
+		if branchName, issue, err = issueBranch(containerRoot, issueRef); err != nil {
+			return err
+		}
 	} else {
 		branchName = arg
 	}
 
-	// Create worktree path
-	worktreePath := filepath.Join(containerRoot, branchName)
-
-	// Check if worktree already exists
-	var worktreeExists bool
-	if info, err := os.Stat(worktreePath); err == nil && info.IsDir() {
-		if isGitWorktree(worktreePath) {
-			currentBranch := getCurrentBranch(worktreePath)
-			if currentBranch == branchName {
-				worktreeExists = true
-				fmt.Printf("Switching to existing worktree for branch '%s'\n", branchName)
-			} else {
-				fmt.Fprintf(os.Stderr, "Error: Folder '%s' exists but is on branch '%s', not '%s'\n",
-					worktreePath, currentBranch, branchName)
-				fmt.Fprintf(os.Stderr, "Please clean up with: git worktree remove '%s'\n", worktreePath)
-				os.Exit(1)
-			}
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: Folder '%s' exists but is not a git worktree\n", worktreePath)
-			os.Exit(1)
-		}
-	} else {
-		// Create new worktree
-		if err := runGitCommand("worktree", "add", worktreePath, branchName); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating worktree: %v\n", err)
-			os.Exit(1)
+	// Reuse the branch's worktree wherever it lives, otherwise create one
+	worktreePath, base, worktreeExists, err := prepareWorktree(containerRoot, branchName, opts)
+	if err != nil {
+		return err
+	}
+	var source string
+	if issue != nil {
+		source = issue.URL
+	}
+	recordWorktree(containerRoot, worktreePath, branchName, source, base, !worktreeExists)
+
+	// Remember the issue so 'work commit' can reference it in the pull request
+	if issue != nil {
+		if err := worktreeManager().SetIssue(context.Background(), containerRoot, branchName, issue.Key, issue.URL); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not record the issue: %v\n", err)
 		}
-		fmt.Printf("Created worktree for branch '%s'\n", branchName)
 	}
 
 	// Change to the worktree directory
 	if err := os.Chdir(worktreePath); err != nil {
-		fmt.Fprintf(os.Stderr, "Error changing to worktree: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("changing to worktree: %w", err)
 	}
 
 	// If worktree already existed, try to sync it
@@ -367,221 +344,383 @@ func runCheckoutBranch(cmd *cobra.Command, args []string) {
 		cmd.Dir = worktreePath
 		if err := cmd.Run(); err != nil {
 			// Silently ignore errors (uncommitted changes, etc.)
-			fmt.Printf("Note: Could not sync with remote (you may have uncommitted changes)\n")
+			printer.Printf("Note: Could not sync with remote (you may have uncommitted changes)\n")
 		} else {
-			fmt.Printf("Synced with remote\n")
+			printer.Printf("Synced with remote\n")
 		}
 	}
 
 	absPath, _ := filepath.Abs(worktreePath)
-	fmt.Printf("Path: %s\n", absPath)
+	printer.Printf("Path: %s\n", absPath)
 
 	// Run post-checkout actions (custom script or IDE fallback)
 	runPostCheckoutActions(worktreePath)
+
+	return render(CheckoutResult{
+		Repo:    repoDisplayName(containerRoot),
+		Branch:  branchName,
+		Path:    absPath,
+		Created: !worktreeExists,
+	})
 }
 
-func runCheckoutNew(cmd *cobra.Command, args []string) {
+func runCheckoutNew(cmd *cobra.Command, args []string) error {
 	repoName := args[0]
 	branchName := args[1]
 
-	// Step 1: Determine which org the repo belongs to by fetching clone URL
-	cloneURL := getRepoCloneURL(repoName)
-	if cloneURL == "" {
-		fmt.Fprintf(os.Stderr, "Error: Could not find repository '%s' in configured orgs\n", repoName)
-		fmt.Fprintf(os.Stderr, "Run: work checkout root <git-url> to clone manually\n")
-		os.Exit(1)
+	// Step 1: Determine which org (and forge) the repo belongs to
+	client, repo := findRemoteRepoByKey(repoName)
+	if repo == nil {
+		return errRepoNotFound(repoName)
 	}
 
-	// Step 2: Extract owner from clone URL
-	owner := extractOwnerFromCloneURL(cloneURL)
-	if owner == "" {
-		fmt.Fprintf(os.Stderr, "Error: Could not extract owner from clone URL: %s\n", cloneURL)
-		os.Exit(1)
-	}
+	// Step 2: Owner is the org, user or group namespace on the forge
+	owner := repo.Owner
 
-	// Step 3: Get base branch - query the specific remote repository
-	baseBranch := getRemoteDefaultBranch(owner, repoName)
+	// Step 3: Get base branch from the remote repository
+	baseBranch := getRemoteDefaultBranch(repo)
 
 	// Step 4: Get base branch SHA
-	fmt.Printf("Fetching base branch '%s' SHA...\n", baseBranch)
-	shaCmd := exec.Command("gh", "api", fmt.Sprintf("repos/%s/%s/git/ref/heads/%s", owner, repoName, baseBranch), "--jq", ".object.sha")
-	shaOutput, err := shaCmd.Output()
+	ctx := context.Background()
+	printer.Printf("Fetching base branch '%s' SHA...\n", baseBranch)
+	baseSHA, err := client.GetBranchSHA(ctx, owner, repo.Name, baseBranch)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Could not fetch base branch '%s' SHA: %v\n", baseBranch, err)
-		fmt.Fprintf(os.Stderr, "Make sure the base branch exists and you have access to the repository.\n")
-		os.Exit(1)
-	}
-	baseSHA := strings.TrimSpace(string(shaOutput))
-	if baseSHA == "" {
-		fmt.Fprintf(os.Stderr, "Error: Empty SHA returned for base branch '%s'\n", baseBranch)
-		os.Exit(1)
+		return errs.Wrap(errs.Failure, err, "could not fetch base branch '%s' SHA", baseBranch).
+			WithHint("Make sure the base branch exists and you have access to the repository.")
 	}
 
 	// Step 5: Create remote branch
-	fmt.Printf("Creating remote branch '%s' from '%s' (SHA: %s)...\n", branchName, baseBranch, baseSHA[:7])
-	createCmd := exec.Command("gh", "api", fmt.Sprintf("repos/%s/%s/git/refs", owner, repoName),
-		"--method", "POST",
-		"-f", fmt.Sprintf("ref=refs/heads/%s", branchName),
-		"-f", fmt.Sprintf("sha=%s", baseSHA))
-
-	createOutput, err := createCmd.CombinedOutput()
-	if err != nil {
-		// Check if branch already exists (422 status)
-		outputStr := string(createOutput)
-		if strings.Contains(outputStr, "Reference already exists") || strings.Contains(outputStr, "422") {
-			fmt.Printf("Branch '%s' already exists remotely; continuing with checkout\n", branchName)
+	printer.Printf("Creating remote branch '%s' from '%s' (SHA: %s)...\n", branchName, baseBranch, shortSHA(baseSHA))
+	if err := client.CreateRef(ctx, owner, repo.Name, branchName, baseSHA); err != nil {
+		if errors.Is(err, forge.ErrRefExists) {
+			printer.Printf("Branch '%s' already exists remotely; continuing with checkout\n", branchName)
+		} else if errors.Is(err, forge.ErrProtectedBranch) {
+			return errs.Wrap(errs.Conflict, err, "refusing to create remote branch").
+				WithHint("Pick a branch name that does not match a protected branch rule.")
 		} else {
-			fmt.Fprintf(os.Stderr, "Error: Could not create remote branch: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Output: %s\n", outputStr)
-			os.Exit(1)
+			return fmt.Errorf("could not create remote branch: %w", err)
 		}
 	} else {
-		fmt.Printf("Created remote branch '%s/%s:%s'\n", owner, repoName, branchName)
+		printer.Printf("Created remote branch '%s/%s:%s'\n", owner, repo.Name, branchName)
 	}
 
 	// Step 6: Perform local checkout using shared logic
-	fmt.Printf("Creating local worktree...\n")
-	checkoutRepoBranch(repoName, branchName)
+	printer.Printf("Creating local worktree...\n")
+	return checkoutRepoBranch(workspace.QualifiedKey(client.Host(), owner, repo.Name), branchName, newBranchOptions{})
 }
 
-// Helper functions
+// newBranchOptions are the --create and --from flags of checkout.
+type newBranchOptions struct {
+	Create bool   // branchName must be a new branch; nothing is fetched
+	From   string // Ref the new branch starts from (default: origin/<default>)
+}
 
-// getRepoCloneURL tries to find the clone URL for a repository from configured orgs
-func getRepoCloneURL(repoName string) string {
-	preferredOrgs := config.GetStringSlice("preferred_orgs")
+// checkoutNewBranchOptions returns the --create and --from flags; --from implies --create.
+func checkoutNewBranchOptions() newBranchOptions {
+	return newBranchOptions{Create: checkoutCreate || checkoutFrom != "", From: checkoutFrom}
+}
 
-	for _, org := range preferredOrgs {
-		if org == "" {
-			continue
+// prepareWorktree returns the worktree that has branchName checked out, or creates one
+// in the folder named by worktree_dir_template. existed reports whether it was already there;
+// base is the ref a new worktree was started from, empty for an existing local branch.
+// With opts.Create, branchName is always created as a new local branch.
+func prepareWorktree(containerRoot, branchName string, opts newBranchOptions) (path, base string, existed bool, err error) {
+	ws := worktreeManager()
+	ctx := context.Background()
+
+	if opts.Create {
+		if base, err = newBranchBase(ctx, containerRoot, branchName, opts.From); err != nil {
+			return "", "", false, err
 		}
+	} else if path, ok := ws.FindWorktree(ctx, containerRoot, branchName); ok {
+		printer.Printf("Switching to existing worktree for branch '%s'\n", branchName)
+		return path, "", true, nil
+	}
 
-		// Use gh api to get repo info
-		// gh api repos/OWNER/REPO --jq '.clone_url'
-		cmd := exec.Command("gh", "api", fmt.Sprintf("repos/%s/%s", org, repoName), "--jq", ".clone_url")
-		output, err := cmd.Output()
-		if err != nil {
-			// Try next org if this fails
-			continue
+	path, err = ws.NewWorktreePath(ctx, containerRoot, branchName)
+	if errors.Is(err, workspace.ErrDirInUse) {
+		return "", "", false, errs.Wrap(errs.Conflict, err, "").
+			WithHint("Clean up '%s' or set a different worktree_dir_template", path)
+	} else if err != nil {
+		return "", "", false, err
+	}
+
+	args, base, newBranch := worktreeAddArgs(ctx, containerRoot, branchName, path, base)
+	if err := runGitCommand(args...); err != nil {
+		return "", "", false, fmt.Errorf("creating worktree: %w", err)
+	}
+	printer.Printf("Created worktree for branch '%s'\n", branchName)
+	if newBranch {
+		setPushUpstream(ctx, containerRoot, branchName)
+	}
+	return path, base, false, nil
+}
+
+// worktreeAddArgs returns the git worktree add command for a new worktree of
+// branchName at path, the ref the worktree starts from, and whether it creates a new
+// branch without upstream. Other working copies, main/ included, are left alone. A
+// non-empty base creates branchName from it. Otherwise an existing local branch is
+// checked out as is, origin/<branch> is tracked when it exists, and a new branch
+// starts from origin/<default>.
+func worktreeAddArgs(ctx context.Context, containerRoot, branchName, path, base string) ([]string, string, bool) {
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(containerRoot)
+
+	if base == "" {
+		if runner.BranchExists(ctx, gitDir, branchName) {
+			return []string{"worktree", "add", path, branchName}, "", false
 		}
+		if exists, _ := runner.RemoteBranchExists(ctx, gitDir, branchName); exists {
+			return []string{"worktree", "add", "--track", "-b", branchName, path, "origin/" + branchName}, "origin/" + branchName, false
+		}
+		base = defaultBranchBase(ctx, gitDir)
+	}
+
+	// The new branch must not track its base, or a plain push would target it
+	printer.Printf("Creating branch '%s' from %s\n", branchName, base)
+	return []string{"worktree", "add", "--no-track", "-b", branchName, path, base}, base, true
+}
 
-		cloneURL := strings.TrimSpace(string(output))
-		if cloneURL != "" {
-			return cloneURL
+// setPushUpstream points a new branch at origin/<branch>, so that a plain git push
+// creates it there. Only the branch's own config is changed.
+func setPushUpstream(ctx context.Context, containerRoot, branchName string) {
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(containerRoot)
+	for _, kv := range [][2]string{
+		{"branch." + branchName + ".remote", "origin"},
+		{"branch." + branchName + ".merge", "refs/heads/" + branchName},
+	} {
+		if _, err := runner.Run(ctx, gitDir, "config", kv[0], kv[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not set origin/%s as the upstream of '%s': %v\n", branchName, branchName, err)
+			return
 		}
 	}
+	printer.Printf("Set origin/%s as the upstream of '%s'\n", branchName, branchName)
+}
+
+// recordWorktree updates the worktree metadata in the cache database: a new record
+// for a worktree that was just created, or the last-opened time of an existing one.
+// source is the issue, ticket or pull request the worktree was created for.
+func recordWorktree(containerRoot, path, branch, source, base string, created bool) {
+	path, _ = filepath.Abs(path)
+	containerRoot, _ = filepath.Abs(containerRoot)
+	var err error
+	if created {
+		now := time.Now()
+		err = cache.SaveWorktreeMeta(cache.WorktreeMeta{
+			Path:       path,
+			Container:  containerRoot,
+			Branch:     branch,
+			Source:     source,
+			BaseRef:    base,
+			Command:    strings.Join(append([]string{"work"}, os.Args[1:]...), " "),
+			CreatedAt:  now,
+			LastOpened: now,
+		})
+	} else {
+		err = cache.TouchWorktree(path, containerRoot, branch)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not record worktree metadata: %v\n", err)
+	}
+}
 
-	return ""
+// defaultBranchBase returns origin/<default>, or the local default branch when the
+// remote-tracking branch is missing.
+func defaultBranchBase(ctx context.Context, gitDir string) string {
+	defaultBranch := getDefaultBranch(gitDir)
+	if exists, _ := services.Get().GitRunner.RemoteBranchExists(ctx, gitDir, defaultBranch); exists {
+		return "origin/" + defaultBranch
+	}
+	return defaultBranch
 }
 
-// cloneRepository clones a git repository into the structured folder layout
-func cloneRepository(gitURL, repoName, gitFolder string) error {
-	// Ensure git folder exists
-	if err := os.MkdirAll(gitFolder, 0755); err != nil {
-		return fmt.Errorf("creating git folder: %w", err)
+// newBranchBase checks that branchName can be created by checkout --create and returns
+// the ref it starts from: from, origin/<from> when only the remote-tracking branch
+// exists, or origin/<default> when from is empty. Only local refs are consulted.
+func newBranchBase(ctx context.Context, containerRoot, branchName, from string) (string, error) {
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(containerRoot)
+
+	if _, err := runner.Run(ctx, gitDir, "check-ref-format", "--branch", branchName); err != nil {
+		return "", errs.New(errs.Usage, "'%s' is not a valid branch name", branchName)
+	}
+	if runner.BranchExists(ctx, gitDir, branchName) {
+		return "", errs.New(errs.Conflict, "branch '%s' already exists", branchName).
+			WithHint("Run without --create to check it out")
+	}
+	if exists, _ := runner.RemoteBranchExists(ctx, gitDir, branchName); exists {
+		return "", errs.New(errs.Conflict, "branch '%s' already exists on origin", branchName).
+			WithHint("Run without --create to track origin/%s", branchName)
+	}
+
+	if from == "" {
+		return defaultBranchBase(ctx, gitDir), nil
+	}
+	for _, ref := range []string{from, "origin/" + from} {
+		if _, err := runner.Run(ctx, gitDir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err == nil {
+			return ref, nil
+		}
 	}
+	return "", errs.New(errs.Usage, "unknown ref '%s'", from).
+		WithHint("Use a branch, tag or commit that exists locally, or fetch it first")
+}
+
+// errRepoNotFound is returned when a repository is neither cloned nor found in the configured orgs
+func errRepoNotFound(repoName string) error {
+	return errs.New(errs.RepoNotFound, "could not find repository '%s' in configured orgs", repoName).
+		WithHint("Run: work checkout root <git-url> to clone manually")
+}
 
-	// Create container folder in git folder
-	containerPath := filepath.Join(gitFolder, repoName)
+// Helper functions
+
+// cloneRepository clones a git repository into containerPath and returns the working
+// copy of its default branch. Main containers get a full clone in main/; with
+// container_layout bare the repository goes to .bare and the default branch gets a worktree.
+func cloneRepository(gitURL, containerPath string) (string, error) {
+	// Create container folder (and any host/org parents) in git folder
 	if err := os.MkdirAll(containerPath, 0755); err != nil {
-		return fmt.Errorf("creating folder '%s': %w", repoName, err)
+		return "", fmt.Errorf("creating folder '%s': %w", containerPath, err)
+	}
+
+	ws := worktreeManager()
+	target := workspace.MainPath(containerPath)
+	args := []string{"clone", gitURL, target}
+	if ws.Bare() {
+		target = filepath.Join(containerPath, workspace.BareDir)
+		args = []string{"clone", "--bare", "--single-branch", gitURL, target}
 	}
 
-	// Clone into main subfolder
-	mainPath := filepath.Join(containerPath, "main")
-	cloneCmd := exec.Command("git", "clone", gitURL, mainPath)
-	cloneCmd.Stdout = os.Stdout
+	cloneCmd := exec.Command("git", args...)
+	cloneCmd.Stdout = commandOutput()
 	cloneCmd.Stderr = os.Stderr
 
 	if err := cloneCmd.Run(); err != nil {
-		return fmt.Errorf("cloning repository: %w", err)
+		return "", fmt.Errorf("cloning repository: %w", err)
 	}
 
+	if !ws.Bare() {
+		return target, nil
+	}
+	worktreePath, err := ws.SetupBare(context.Background(), containerPath)
+	if err != nil {
+		return "", fmt.Errorf("setting up bare repository: %w", err)
+	}
+	return worktreePath, nil
+}
+
+// refreshRepo changes into the repository's git folder and, unless offline, fetches
+// origin so new worktrees start from the latest remote state. No working copy is
+// switched or pulled; whatever is checked out in main/ stays as it is.
+func refreshRepo(containerRoot string, offline bool) error {
+	gitRoot := workspace.GitDir(containerRoot)
+
+	// Change to git root for operations
+	if err := os.Chdir(gitRoot); err != nil {
+		return fmt.Errorf("changing to git root: %w", err)
+	}
+
+	if offline {
+		return nil
+	}
+	if err := runGitCommand("fetch", "origin"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not fetch latest changes: %v\n", err)
+	}
 	return nil
 }
 
-// listGitRepos returns a list of git repositories from local folder and persistent cache
+// listGitRepos returns a list of git repositories from local folder and persistent cache,
+// the ones with the most recently opened worktrees first.
+// Names are repo arguments accepted by checkout: a bare name, org/repo or host/owner/repo.
 func listGitRepos() []string {
 	repoMap := make(map[string]bool) // Use map to avoid duplicates
 	var repos []string
 
-	// 1. List local repositories from configured git folder
-	gitFolder := config.GetString("default_git_folder")
-	if gitFolder != "" {
-		// Expand home directory if needed
-		if strings.HasPrefix(gitFolder, "~/") {
-			homeDir, err := os.UserHomeDir()
-			if err == nil {
-				gitFolder = filepath.Join(homeDir, gitFolder[2:])
-			}
+	// Last time a worktree of each container was opened
+	containerOpened := make(map[string]time.Time)
+	for _, meta := range loadWorktreeMeta() {
+		if meta.LastOpened.After(containerOpened[meta.Container]) {
+			containerOpened[meta.Container] = meta.LastOpened
+		}
+	}
+	lastOpened := make(map[string]time.Time)
+
+	// Repositories from the persistent cache (populated by 'work reload'), shown as
+	// org/repo when several orgs have a repo of the same name
+	cachedRepos, _ := cache.LoadRepoCache()
+	cachedNames, ambiguous := repoCompletionNames(cachedRepos)
+
+	// 1. List local repository containers from configured git folder
+	ws := worktreeManager()
+	if containers, err := ws.Discover(); err == nil {
+		localCount := make(map[string]int)
+		for _, containerPath := range containers {
+			localCount[filepath.Base(containerPath)]++
 		}
 
-		// Check if directory exists
-		if info, err := os.Stat(gitFolder); err == nil && info.IsDir() {
-			// List all directories in the git folder
-			if entries, err := os.ReadDir(gitFolder); err == nil {
-				for _, entry := range entries {
-					if entry.IsDir() {
-						// Check if it has a main subfolder (our container structure)
-						mainPath := filepath.Join(gitFolder, entry.Name(), "main")
-						if _, err := os.Stat(mainPath); err == nil {
-							repoName := entry.Name()
-							if !repoMap[repoName] {
-								repoMap[repoName] = true
-								repos = append(repos, repoName)
-							}
-						}
-					}
+		for _, containerPath := range containers {
+			repoName := ws.KeyForPath(containerPath)
+			// A bare folder name shared with another clone or cached org is shown as org/repo
+			if !strings.Contains(repoName, "/") && (ambiguous[repoName] || localCount[repoName] > 1) {
+				origin, err := ws.Origin(containerPath)
+				if err != nil {
+					continue
 				}
+				repoName = workspace.QualifiedKey(origin.Host, origin.Owner(), origin.Name())
 			}
-		}
-	}
-
-	// 2. Load repositories from persistent cache (populated by 'work reload')
-	cachedRepos, err := cache.LoadRepoCache()
-	if err == nil && len(cachedRepos) > 0 {
-		for _, repoName := range cachedRepos {
 			if !repoMap[repoName] {
 				repoMap[repoName] = true
 				repos = append(repos, repoName)
+				lastOpened[repoName] = containerOpened[containerPath]
 			}
 		}
 	}
 
-	return repos
+	// 2. Add cached repositories
+	for _, repoName := range cachedNames {
+		if !repoMap[repoName] {
+			repoMap[repoName] = true
+			repos = append(repos, repoName)
+		}
+	}
+
+	return cache.RankRecent(repos, lastOpened)
+}
+
+// rankBranchesForRepo puts the branches of repoName's recently opened worktrees first.
+func rankBranchesForRepo(repoName string, branches []string) []string {
+	containerPath, ok := worktreeManager().Resolve(repoName)
+	if !ok {
+		return branches
+	}
+	containerPath, _ = filepath.Abs(containerPath)
+
+	lastOpened := make(map[string]time.Time)
+	for _, meta := range loadWorktreeMeta() {
+		if meta.Container == containerPath {
+			lastOpened[meta.Branch] = meta.LastOpened
+		}
+	}
+	return cache.RankRecent(branches, lastOpened)
 }
 
 // listBranchesForRepo returns a list of branches for a given repository
 func listBranchesForRepo(repoName string) []string {
 	branches := []string{}
 
-	// Fetch from GitHub API (always fresh data)
-	ghBranches := listBranchesFromGitHub(repoName)
-	if len(ghBranches) > 0 {
-		return ghBranches
-	}
-
-	// Fall back to local git repo
-	gitFolder := config.GetString("default_git_folder")
-	if gitFolder == "" {
-		return []string{}
-	}
+	containerPath, _ := worktreeManager().Resolve(repoName)
 
-	// Expand home directory if needed
-	if strings.HasPrefix(gitFolder, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return []string{}
-		}
-		gitFolder = filepath.Join(homeDir, gitFolder[2:])
+	// Fetch from the forge API (always fresh data)
+	remoteBranches := listBranchesFromForges(repoName, containerPath)
+	if len(remoteBranches) > 0 {
+		return remoteBranches
 	}
 
-	// Build path to repo's main folder
-	gitRoot := filepath.Join(gitFolder, repoName, "main")
-
-	// Check if repo exists
-	if _, err := os.Stat(gitRoot); os.IsNotExist(err) {
+	// Fall back to local git repo
+	if containerPath == "" {
 		return []string{}
 	}
+	gitRoot := workspace.GitDir(containerPath)
 
 	// Prune stale remote-tracking branches first
 	pruneCmd := exec.Command("git", "-C", gitRoot, "remote", "prune", "origin")
@@ -612,47 +751,61 @@ func listBranchesForRepo(repoName string) []string {
 	return branches
 }
 
-// listBranchesFromGitHub fetches branches from GitHub using gh CLI, sorted by date
-func listBranchesFromGitHub(repoName string) []string {
-	preferredOrgs := config.GetStringSlice("preferred_orgs")
+// listBranchesFromForges fetches branches for a repo argument. A local clone is queried
+// on its origin; owner- or host-qualified names go straight to that repository; bare
+// names are looked up in every configured org concurrently.
+func listBranchesFromForges(repoName, containerPath string) []string {
+	type branchSource struct {
+		client forge.Forge
+		owner  string
+		name   string
+	}
+
+	var sources []branchSource
+	if containerPath != "" {
+		if client, origin, err := getOriginForge(workspace.GitDir(containerPath)); err == nil {
+			sources = append(sources, branchSource{client, origin.Owner(), origin.Name()})
+		}
+	}
+	if len(sources) == 0 {
+		if host, owner, name := workspace.ParseRepoArg(repoName); owner != "" {
+			if client, err := forgeForHost(host); err == nil {
+				sources = append(sources, branchSource{client, owner, name})
+			}
+		} else {
+			for _, target := range configuredOrgs() {
+				if client, err := forgeForHost(target.Host); err == nil {
+					sources = append(sources, branchSource{client, target.Owner, repoName})
+				}
+			}
+		}
+	}
+
+	ctx := context.Background()
 
 	// Use a channel to collect results from concurrent queries
-	results := make(chan []string, len(preferredOrgs))
+	results := make(chan []string, len(sources))
 	var wg sync.WaitGroup
 
-	for _, org := range preferredOrgs {
-		if org == "" {
-			continue
-		}
-
+	for _, source := range sources {
 		wg.Add(1)
-		go func(organization string) {
+		go func(source branchSource) {
 			defer wg.Done()
 
-			// Use gh api to list branches sorted by last updated date
-			// Fetch all, sort by commit date, limit to 100 most recent, extract names
-			cmd := exec.Command("gh", "api", fmt.Sprintf("repos/%s/%s/branches", organization, repoName),
-				"--paginate",
-				"--jq", "sort_by(.commit.commit.committer.date) | reverse | .[0:100] | .[].name")
-			output, err := cmd.Output()
+			remoteBranches, err := source.client.ListBranches(ctx, source.owner, source.name)
 			if err != nil {
 				// Send empty result if this org fails
 				results <- []string{}
 				return
 			}
 
-			// Parse the output (one branch per line, already sorted by date)
-			branches := []string{}
-			lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-			for _, line := range lines {
-				branch := strings.TrimSpace(line)
-				if branch != "" {
-					branches = append(branches, branch)
-				}
+			branches := make([]string, 0, len(remoteBranches))
+			for _, b := range remoteBranches {
+				branches = append(branches, b.Name)
 			}
 
 			results <- branches
-		}(org)
+		}(source)
 	}
 
 	// Close results channel when all goroutines complete
@@ -678,17 +831,17 @@ func listBranchesFromGitHub(repoName string) []string {
 
 // completeGitRepos is a completion function for git repositories and branches
 func completeGitRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	// First argument: complete repo names
+	// First argument: complete repo names, recently used first
 	if len(args) == 0 {
 		repos := listGitRepos()
-		return repos, cobra.ShellCompDirectiveNoFileComp
+		return repos, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
 	}
 
 	// Second argument: complete branch names for the specified repo
 	if len(args) == 1 {
 		repoName := args[0]
-		branches := listBranchesForRepo(repoName)
-		return branches, cobra.ShellCompDirectiveNoFileComp
+		branches := rankBranchesForRepo(repoName, listBranchesForRepo(repoName))
+		return branches, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
 	}
 
 	// No completion for additional arguments
@@ -705,35 +858,6 @@ func extractRepoName(gitURL string) string {
 	return strings.TrimSuffix(base, ".git")
 }
 
-// extractOwnerFromCloneURL extracts the owner/org from a GitHub clone URL.
-// Example: "https://github.com/velvee-ai/repo.git" -> "velvee-ai"
-func extractOwnerFromCloneURL(cloneURL string) string {
-	// Remove .git suffix
-	url := strings.TrimSuffix(cloneURL, ".git")
-
-	// Handle HTTPS format: https://github.com/owner/repo
-	if strings.Contains(url, "://") {
-		parts := strings.Split(url, "/")
-		if len(parts) >= 2 {
-			return parts[len(parts)-2]
-		}
-	}
-
-	// Handle SSH format: git@github.com:owner/repo
-	if strings.Contains(url, ":") {
-		parts := strings.Split(url, ":")
-		if len(parts) == 2 {
-			ownerRepo := parts[1]
-			ownerParts := strings.Split(ownerRepo, "/")
-			if len(ownerParts) >= 1 {
-				return ownerParts[0]
-			}
-		}
-	}
-
-	return ""
-}
-
 func isInsideGitRepo() bool {
 	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
 	err := cmd.Run()
@@ -749,76 +873,97 @@ func getGitRoot() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-func isGitHubIssueURL(url string) bool {
-	// Match: https://github.com/user/repo/issues/123
-	pattern := `^https://github\.com/.+/.+/issues/\d+$`
-	matched, _ := regexp.MatchString(pattern, url)
-	return matched
-}
-
-func handleGitHubIssue(issueURL string) string {
-	// Extract issue number
-	parts := strings.Split(issueURL, "/")
-	issueNumber := parts[len(parts)-1]
+// issueBranch returns the branch for the issue ref: a branch created for it earlier,
+// or a new name rendered from issue_branch_template. Forge references without a host
+// or repository refer to the repository's origin; ticket keys go to the configured
+// trackers. New branches are assigned or moved to in progress on the tracker.
+func issueBranch(containerRoot string, ref tracker.Ref) (string, *tracker.Issue, error) {
+	ctx := context.Background()
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(containerRoot)
 
-	// Check for existing branch related to this issue
-	cmd := exec.Command("git", "branch", "-a")
-	output, err := cmd.Output()
-	if err == nil {
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, issueNumber+"-") {
-				// Extract branch name
-				branch := strings.TrimSpace(line)
-				branch = strings.TrimPrefix(branch, "* ")
-				branch = strings.TrimPrefix(branch, "remotes/origin/")
-				if branch != "" {
-					fmt.Printf("Found existing branch: %s\n", branch)
-
-					// Fetch the branch if it doesn't exist locally
-					if !branchExistsLocally(branch) {
-						exec.Command("git", "fetch", "origin", fmt.Sprintf("%s:%s", branch, branch)).Run()
-					}
-					return branch
-				}
-			}
-		}
+	t, ref, err := issueTracker(gitDir, ref)
+	if err != nil {
+		return "", nil, err
 	}
 
-	// Create branch from GitHub issue using gh CLI
-	fmt.Printf("Creating branch from GitHub issue #%s...\n", issueNumber)
-	defaultBranch := getDefaultBranch(".")
-	createCmd := exec.Command("gh", "issue", "develop", issueURL, "--checkout", "--base", defaultBranch)
-	createCmd.Stdout = os.Stdout
-	createCmd.Stderr = os.Stderr
+	printer.Printf("Looking up %s...\n", ref)
+	issue, err := t.GetIssue(ctx, ref)
+	if errors.Is(err, tracker.ErrNotFound) {
+		return "", nil, errs.New(errs.Usage, "issue %s not found", ref)
+	} else if err != nil {
+		return "", nil, fmt.Errorf("looking up %s: %w", ref, err)
+	}
 
-	if err := createCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating branch from issue: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Make sure 'gh' CLI is installed and authenticated\n")
-		os.Exit(1)
+	name, err := tracker.BranchName(config.GetString("issue_branch_template"), *issue)
+	if err != nil {
+		return "", nil, errs.Wrap(errs.Usage, err, "").
+			WithHint("Fix it with: work config set issue_branch_template '<template>'")
+	}
+	if _, err := runner.Run(ctx, gitDir, "check-ref-format", "--branch", name); err != nil {
+		return "", nil, errs.New(errs.Usage, "issue_branch_template renders '%s', which is not a valid branch name", name)
 	}
 
-	// Assign the issue to yourself
-	assignCmd := exec.Command("gh", "issue", "edit", issueURL, "--add-assignee", "@me")
-	assignCmd.Run() // Don't fail if this doesn't work
+	// Reuse a branch made for this issue earlier, even if the title changed since
+	var branches []string
+	for _, refs := range [][]string{{"--format=%(refname:lstrip=2)", "refs/heads"}, {"--format=%(refname:lstrip=3)", "refs/remotes/origin"}} {
+		output := runner.RunIgnoreError(ctx, gitDir, append([]string{"for-each-ref"}, refs...)...)
+		for _, line := range strings.Split(output, "\n") {
+			if line != "" && line != "HEAD" {
+				branches = append(branches, line)
+			}
+		}
+	}
+	if existing, ok := tracker.MatchBranch(branches, name, issue.Key); ok {
+		printer.Printf("Found existing branch: %s\n", existing)
+		return existing, issue, nil
+	}
 
-	// Get the current branch name
-	branchName := getCurrentBranch(".")
+	printer.Printf("Branch for %s (%s): %s\n", ref, issue.Title, name)
 
-	// Switch back to default branch for worktree creation
-	runGitCommand("switch", defaultBranch)
+	if starter, ok := t.(tracker.Starter); ok {
+		// Move the ticket to in progress
+		if err := starter.Start(ctx, ref); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not move %s to in progress: %v\n", issue.Key, err)
+		}
+	} else if client, err := forgeForHost(ref.Host); err == nil && client.Name() == "github" {
+		// Assign the issue to yourself
+		assignCmd := exec.Command("gh", "issue", "edit", issue.URL, "--add-assignee", "@me")
+		assignCmd.Run() // Don't fail if this doesn't work
+	}
 
-	return branchName
+	return name, issue, nil
 }
 
-func branchExistsLocally(branch string) bool {
-	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
-	return cmd.Run() == nil
-}
+// issueTracker returns the tracker that resolves ref, with the host and repository
+// of forge issue references filled in from origin.
+func issueTracker(gitDir string, ref tracker.Ref) (tracker.Tracker, tracker.Ref, error) {
+	if ref.IsTicket() {
+		t, ok := services.Get().Trackers.ForKey(ref.Key)
+		if !ok {
+			return nil, ref, errs.New(errs.NotConfigured, "no issue tracker configured for %s", ref.Key).
+				WithHint("Add a jira or linear entry under 'trackers' in ~/.work/config.yaml")
+		}
+		return t, ref, nil
+	}
 
-func isGitWorktree(path string) bool {
-	cmd := exec.Command("git", "-C", path, "rev-parse", "--is-inside-work-tree")
-	return cmd.Run() == nil
+	if ref.Host == "" || ref.Owner == "" {
+		origin, err := getOriginRepo(gitDir)
+		if err != nil {
+			return nil, ref, err
+		}
+		if ref.Host == "" {
+			ref.Host = origin.Host
+		}
+		if ref.Owner == "" {
+			ref.Owner, ref.Repo = origin.Owner(), origin.Name()
+		}
+	}
+	client, err := forgeForHost(ref.Host)
+	if err != nil {
+		return nil, ref, err
+	}
+	return tracker.NewForge(client), ref, nil
 }
 
 func getCurrentBranch(path string) string {
@@ -865,7 +1010,7 @@ func runPostCheckoutActions(worktreePath string) {
 	info, err := os.Stat(scriptPath)
 	if err == nil && !info.IsDir() {
 		// Script exists, run it using the user's default shell
-		fmt.Printf("Running .work/post_checkout.sh…\n")
+		printer.Printf("Running .work/post_checkout.sh…\n")
 
 		// Get the user's shell from SHELL environment variable, default to sh if not set
 		shell := os.Getenv("SHELL")
@@ -875,7 +1020,7 @@ func runPostCheckoutActions(worktreePath string) {
 
 		cmd := exec.Command(shell, scriptPath)
 		cmd.Dir = worktreePath
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = commandOutput()
 		cmd.Stderr = os.Stderr
 
 		if err := cmd.Run(); err != nil {
@@ -895,6 +1040,11 @@ func init() {
 	checkoutCmd.AddCommand(checkoutBranchCmd)
 	checkoutCmd.AddCommand(checkoutNewCmd)
 
+	for _, cmd := range []*cobra.Command{checkoutCmd, checkoutBranchCmd} {
+		cmd.Flags().BoolVar(&checkoutCreate, "create", false, "Create a new local branch without contacting the remote")
+		cmd.Flags().StringVar(&checkoutFrom, "from", "", "Base ref of the new branch: branch, tag or commit (implies --create)")
+	}
+
 	// Register checkout command with root
 	rootCmd.AddCommand(checkoutCmd)
 }
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+var checkoutPRCmd = &cobra.Command{
+	Use:   "pr [repo] <number|url>",
+	Short: "Checkout a pull request into a worktree",
+	Long: `Fetch the head of a pull request (merge request on GitLab) and check it out
+in a pr-<number> worktree. Pull requests from forks work too: the head is fetched
+from the ref the forge publishes in the repository itself (refs/pull/<n>/head).
+
+The pull request URL is recorded with the branch, and 'work cleanup' offers to
+remove the worktree once the pull request is closed or merged. Running the command
+again updates an existing worktree when it can be fast-forwarded.
+
+Example:
+  work checkout pr api 42
+  work checkout pr https://github.com/acme-labs/api/pull/42
+  work checkout pr https://gitlab.example.com/group/api/-/merge_requests/7`,
+	Args: cobra.RangeArgs(1, 2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return listGitRepos(), cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runCheckoutPR,
+}
+
+func runCheckoutPR(cmd *cobra.Command, args []string) error {
+	repoName, number, err := parsePRArgs(args)
+	if err != nil {
+		return err
+	}
+	containerRoot, cloned, err := ensureContainer(repoName)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(containerRoot)
+	client, origin, err := getOriginForge(gitDir)
+	if err != nil {
+		return err
+	}
+	pr, err := client.GetPR(ctx, origin.Owner(), origin.Name(), number)
+	if errors.Is(err, forge.ErrNotFound) {
+		return errs.New(errs.Usage, "pull request #%d not found in %s", number, repoDisplayName(containerRoot))
+	} else if err != nil {
+		return fmt.Errorf("looking up pull request #%d: %w", number, err)
+	}
+	printer.Printf("Pull request #%d: %s (%s)\n", pr.Number, pr.Title, pr.State)
+
+	// Fetch the head into FETCH_HEAD; remote-tracking refs are left alone so the next
+	// fetch --prune has nothing to remove
+	ref := client.PullRef(number)
+	printer.Printf("Fetching %s...\n", ref)
+	if _, err := runner.Run(ctx, gitDir, "fetch", "origin", ref); err != nil {
+		return fmt.Errorf("fetching pull request #%d: %w", number, err)
+	}
+	head, err := runner.RunSimple(ctx, gitDir, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return err
+	}
+
+	ws := worktreeManager()
+	branchName := fmt.Sprintf("pr-%d", number)
+	worktreePath, worktreeExists := ws.FindWorktree(ctx, containerRoot, branchName)
+	if worktreeExists {
+		printer.Printf("Switching to existing worktree for branch '%s'\n", branchName)
+		if _, err := runner.Run(ctx, worktreePath, "merge", "--ff-only", head); err != nil {
+			printer.Printf("Note: Could not fast-forward to the pull request head (local commits or changes)\n")
+		} else {
+			printer.Printf("Updated to %s\n", shortSHA(head))
+		}
+	} else {
+		if worktreePath, err = ws.NewWorktreePath(ctx, containerRoot, branchName); errors.Is(err, workspace.ErrDirInUse) {
+			return errs.Wrap(errs.Conflict, err, "").
+				WithHint("Clean up '%s' or set a different worktree_dir_template", worktreePath)
+		} else if err != nil {
+			return err
+		}
+
+		// A pr-N branch left from an earlier checkout keeps its commits
+		args := []string{"worktree", "add", worktreePath, branchName}
+		if !runner.BranchExists(ctx, gitDir, branchName) {
+			args = []string{"worktree", "add", "--no-track", "-b", branchName, worktreePath, head}
+		}
+		if err := os.Chdir(gitDir); err != nil {
+			return fmt.Errorf("changing to git root: %w", err)
+		}
+		if err := runGitCommand(args...); err != nil {
+			return fmt.Errorf("creating worktree: %w", err)
+		}
+		printer.Printf("Created worktree for branch '%s'\n", branchName)
+	}
+
+	if err := ws.SetPullRequest(ctx, containerRoot, branchName, pr.URL); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not record the pull request URL: %v\n", err)
+	}
+	if err := ws.SetPullRequestHead(ctx, containerRoot, number, head); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not record the pull request head: %v\n", err)
+	}
+	recordWorktree(containerRoot, worktreePath, branchName, pr.URL, ref, !worktreeExists)
+
+	if err := os.Chdir(worktreePath); err != nil {
+		return fmt.Errorf("changing to worktree: %w", err)
+	}
+	absPath, _ := filepath.Abs(worktreePath)
+	printer.Printf("Path: %s\n", absPath)
+
+	runPostCheckoutActions(worktreePath)
+
+	return render(CheckoutResult{
+		Repo:        ws.KeyForPath(containerRoot),
+		Branch:      branchName,
+		Path:        absPath,
+		Created:     !worktreeExists,
+		Cloned:      cloned,
+		PullRequest: pr.URL,
+	})
+}
+
+// parsePRArgs returns the repository and pull request number named by the
+// arguments of 'checkout pr': a URL on its own, or a repo followed by a number or URL.
+func parsePRArgs(args []string) (repoName string, number int, err error) {
+	if len(args) == 1 {
+		pull, err := forge.ParsePullURL(args[0])
+		if err != nil {
+			return "", 0, errs.New(errs.Usage, "%v", err).
+				WithHint("Usage: work checkout pr <repo> <number> or work checkout pr <url>")
+		}
+		return workspace.QualifiedKey(pull.Host, pull.Owner, pull.Repo), pull.Number, nil
+	}
+
+	if n, err := strconv.Atoi(strings.TrimPrefix(args[1], "#")); err == nil && n > 0 {
+		return args[0], n, nil
+	}
+	pull, err := forge.ParsePullURL(args[1])
+	if err != nil {
+		return "", 0, errs.New(errs.Usage, "'%s' is neither a pull request number nor a URL", args[1])
+	}
+	return args[0], pull.Number, nil
+}
+
+func init() {
+	checkoutCmd.AddCommand(checkoutPRCmd)
+}
@@ -1,17 +1,27 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/archive"
+	"github.com/velvee-ai/ai-workflow/pkg/cache"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/parallel"
+	"github.com/velvee-ai/ai-workflow/pkg/policy"
 	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
 )
 
 var cleanupCmd = &cobra.Command{
@@ -20,13 +30,25 @@ var cleanupCmd = &cobra.Command{
 	Long: `Clean up stale git worktrees that have been merged or deleted.
 
 This command helps keep your git folders clean by removing worktrees for branches
-that have been merged to the default branch or deleted from the remote.
+that have been merged to the default branch or deleted from the remote. Squash and
+rebase merges are recognized by their changes, and by the state of the branch's
+pull request on the forge unless --no-pr is given.
 
 Safety:
   - Only removes worktrees with no uncommitted changes (git status is clean)
   - Verifies branch is either merged OR deleted remotely before removal
+  - Refuses worktrees with commits that are on no remote, stashes made on their
+    branch, a rebase, merge or cherry-pick in progress, or a lock, and folders
+    that are not worktrees but hold files (unless --force-unsafe)
   - Provides dry-run and interactive modes for safety
 
+With --policy, scan and run also select worktrees by the policies under cleanup
+in ~/.work/config.yaml:
+  inactive_for: 30d           Not opened with 'work checkout' or committed to for 30 days
+  pr_closed: true             The branch's pull request is closed or merged
+  max_worktrees_per_repo: 10  Keep the 10 most recently used worktrees of a repository
+  min_free_disk: 20GB         Remove least recently used worktrees until 20GB are free
+
 Subcommands:
   work cleanup list   - List all worktrees and their status
   work cleanup scan   - Show what would be cleaned (dry-run)
@@ -41,22 +63,37 @@ var cleanupListCmd = &cobra.Command{
 Status indicators:
   [active]   - Branch is active and up-to-date
   [merged]   - Branch has been merged to default branch
+  [squash-merged] - Branch was squash- or rebase-merged, or its pull request was merged
   [deleted]  - Remote branch has been deleted
-  [changes]  - Has uncommitted changes (cannot be cleaned)`,
-	Run: runCleanupList,
+  [closed]   - Pull request checked out with 'work checkout pr' is closed or merged
+  [unpublished] - Branch created by 'work checkout' that was never pushed (kept)
+  [policy]   - Selected by a cleanup policy (with --policy)
+  [changes]  - Has uncommitted changes (cannot be cleaned)
+  [missing]  - Registered worktree whose folder was deleted
+  [orphaned] - Folder in the repository that is not a registered worktree`,
+	RunE: runCleanupList,
 }
 
 var cleanupScanCmd = &cobra.Command{
 	Use:   "scan [repo]",
 	Short: "Show what would be cleaned (dry-run)",
 	Long: `Scan for stale worktrees and show what would be removed without actually deleting anything.
+Each worktree is listed with the rule that selected it.
 
-This is a safe way to preview what the cleanup would do before running it.`,
-	Run: runCleanupScan,
+This is a safe way to preview what the cleanup would do before running it.
+--policy includes the worktrees selected by the configured cleanup policies.
+--orphans, --delete-branches, --delete-remote, --archive and --force-unsafe show
+what 'work cleanup run' with the same flags would do.`,
+	RunE: runCleanupScan,
 }
 
 var (
-	cleanupForce bool
+	cleanupForce       bool
+	cleanupPolicy      bool
+	cleanupNoPR        bool
+	cleanupArchive     bool
+	cleanupForceUnsafe bool
+	cleanupOrphans     bool
 )
 
 var cleanupRunCmd = &cobra.Command{
@@ -66,335 +103,449 @@ var cleanupRunCmd = &cobra.Command{
 
 The command will:
   1. Scan all repositories for stale worktrees
-  2. Identify worktrees that are merged or have deleted remote branches
+  2. Identify worktrees that are merged or have deleted remote branches, and
+     with --policy the ones selected by the configured cleanup policies
   3. Skip worktrees with uncommitted changes
   4. Ask for confirmation before removing each worktree (unless --force is used)
-  5. Clean up git metadata with 'git worktree prune'`,
-	Run: runCleanupRun,
+  5. Clean up git metadata with 'git worktree prune'
+
+With --delete-branches, the local branch is deleted after its worktree is removed;
+with --delete-remote, the branch on origin is deleted too when the forge reports its
+pull request as merged. The default branch and branches matching
+cleanup.protected_branches (default release/*) are never deleted. The plan is
+printed before anything is removed; 'work cleanup scan' with the same flags shows
+it without removing anything.
+
+With --archive, each worktree is archived before it is removed: the branch head is
+kept under refs/work/archive/<branch>/<timestamp>, so unpushed commits survive, and
+untracked or ignored files matching cleanup.archive_files (default .env and .env.*)
+are saved in a tarball under ~/.work/archive. 'work cleanup restore <id>' recreates
+the worktree.
+
+Folders in a repository that are not registered worktrees, e.g. left behind by
+'git worktree prune', are only cleared away with --orphans: empty ones are deleted
+and the others moved to ~/.work/archive/folders, from where they can be moved back.
+--force-unsafe does not apply to them.`,
+	RunE: runCleanupRun,
 }
 
 // WorktreeInfo holds information about a worktree and its status
 type WorktreeInfo struct {
-	RepoName      string
-	RepoPath      string
-	Path          string
-	Branch        string
-	IsMerged      bool
-	IsDeleted     bool
-	HasChanges    bool
-	Reason        string
-	LastModified  time.Time
-	SizeBytes     int64
-	DefaultBranch string
+	RepoName      string    `json:"repo"`
+	RepoPath      string    `json:"repo_path"`
+	Path          string    `json:"path"`
+	Branch        string    `json:"branch"`
+	IsMerged      bool      `json:"merged"`
+	IsSquashed    bool      `json:"squash_merged"`
+	IsDeleted     bool      `json:"remote_deleted"`
+	PRClosed      bool      `json:"pr_closed"`
+	PullRequest   string    `json:"pull_request,omitempty"` // Recorded by 'checkout pr'
+	Archive       string    `json:"archive,omitempty"`      // ID of the archive made by 'run --archive'
+	HasChanges    bool      `json:"has_changes"`
+	Unpushed      int       `json:"unpushed"`              // Commits on no remote and not merged to the default branch
+	Stashes       int       `json:"stashes"`               // Stash entries made on the branch
+	InProgress    string    `json:"in_progress,omitempty"` // "rebase", "merge", "cherry-pick", "revert" or "bisect"
+	Locked        bool      `json:"locked"`
+	LockReason    string    `json:"lock_reason,omitempty"`
+	Unpublished   bool      `json:"unpublished,omitempty"`  // Branch created by work and never pushed
+	Missing       bool      `json:"missing"`                // Registered, but the folder is gone
+	Orphaned      bool      `json:"orphaned"`               // A folder in the container that is not a worktree
+	OrphanFiles   int       `json:"orphan_files,omitempty"` // Files in an orphaned folder
+	MovedTo       string    `json:"moved_to,omitempty"`     // Where run --orphans moved an orphaned folder
+	Reason        string    `json:"reason,omitempty"`
+	LastModified  time.Time `json:"last_modified"`
+	LastCommit    time.Time `json:"last_commit"`
+	SizeBytes     int64     `json:"size_bytes"`
+	DefaultBranch string    `json:"default_branch"`
+	MergedHead    string    `json:"merged_head,omitempty"` // Commit found merged by content or by its pull request
+
+	// base is the ref merges were checked against, origin/<default> when it exists
+	base string
+
+	// Metadata is the record written when the worktree was checked out, if any
+	Metadata *cache.WorktreeMeta `json:"metadata,omitempty"`
+	// Policy is the cleanup policy rule that selected the worktree (with --policy)
+	Policy *policy.Match `json:"policy,omitempty"`
+	// BranchPlan is what happens to the branch (with --delete-branches or --delete-remote)
+	BranchPlan *BranchPlan `json:"branch_plan,omitempty"`
+}
+
+// MarshalJSON adds the derived status, stale, rule and unsafe fields.
+func (w WorktreeInfo) MarshalJSON() ([]byte, error) {
+	type plain WorktreeInfo
+	return json.Marshal(struct {
+		plain
+		Status string   `json:"status"`
+		Stale  bool     `json:"stale"`
+		Rule   string   `json:"rule,omitempty"`
+		Unsafe []string `json:"unsafe,omitempty"`
+	}{
+		plain:  plain(w),
+		Status: strings.Trim(w.StatusString(), "[]"),
+		Stale:  w.IsStale(),
+		Rule:   w.Rule(),
+		Unsafe: w.Unsafe(),
+	})
+}
+
+// RepoError records a repository that could not be scanned.
+type RepoError struct {
+	Repo  string `json:"repo"`
+	Error string `json:"error"`
+}
+
+// CleanupListReport is the structured result of 'work cleanup list'.
+type CleanupListReport struct {
+	Worktrees []WorktreeInfo `json:"worktrees"`
+	Total     int            `json:"total"`
+	Stale     int            `json:"stale"`
+	Errors    []RepoError    `json:"errors,omitempty"`
+}
+
+// CleanupScanReport is the structured result of 'work cleanup scan'.
+type CleanupScanReport struct {
+	Stale          []WorktreeInfo `json:"stale"`
+	Total          int            `json:"total"`
+	TotalSizeBytes int64          `json:"total_size_bytes"`
+	Errors         []RepoError    `json:"errors,omitempty"`
+}
+
+// CleanupFailure records a worktree that could not be removed.
+type CleanupFailure struct {
+	Worktree WorktreeInfo `json:"worktree"`
+	Error    string       `json:"error"`
+}
+
+// CleanupRunReport is the structured result of 'work cleanup run'.
+type CleanupRunReport struct {
+	Removed    []WorktreeInfo   `json:"removed"`
+	Skipped    []WorktreeInfo   `json:"skipped"`
+	Failed     []CleanupFailure `json:"failed"`
+	FreedBytes int64            `json:"freed_bytes"`
+	Errors     []RepoError      `json:"errors,omitempty"`
+}
+
+// Name identifies the worktree within its repository: the branch checked out in it
+// as reported by git worktree list, else its folder relative to the container.
+func (w *WorktreeInfo) Name() string {
+	if w.Branch != "" {
+		return w.Branch
+	}
+	return workspace.WorktreeName(w.RepoPath, w.Path)
+}
+
+// Label identifies the worktree in messages, e.g. "feature/login in acme/api".
+func (w *WorktreeInfo) Label() string {
+	return w.Name() + " in " + w.RepoName
 }
 
 // IsStale returns true if the worktree can be cleaned up
 func (w *WorktreeInfo) IsStale() bool {
-	return !w.HasChanges && (w.IsMerged || w.IsDeleted)
+	if w.Missing || w.Orphaned {
+		return true
+	}
+	return !w.HasChanges && (w.IsMerged || w.IsSquashed || w.IsDeleted || w.PRClosed || w.Policy != nil)
+}
+
+// Unsafe returns what would be lost by removing the worktree besides uncommitted
+// changes: unpushed commits, stashes, an operation in progress or a lock. Removal of
+// an unsafe worktree needs --force-unsafe.
+func (w *WorktreeInfo) Unsafe() []string {
+	var reasons []string
+	if w.Locked {
+		if w.LockReason != "" {
+			reasons = append(reasons, "locked: "+w.LockReason)
+		} else {
+			reasons = append(reasons, "locked")
+		}
+	}
+	if w.Unpushed > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d unpushed commits", w.Unpushed))
+	}
+	if w.Stashes > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d stashes", w.Stashes))
+	}
+	if w.InProgress != "" {
+		reasons = append(reasons, w.InProgress+" in progress")
+	}
+	return reasons
+}
+
+// Rule returns the name of the rule that makes the worktree stale, or "".
+func (w *WorktreeInfo) Rule() string {
+	switch {
+	case w.Missing:
+		return "missing_folder"
+	case w.Orphaned:
+		return "orphaned_folder"
+	case w.HasChanges:
+		return ""
+	case w.IsMerged:
+		return "merged"
+	case w.IsSquashed:
+		return "squash_merged"
+	case w.IsDeleted:
+		return "remote_deleted"
+	case w.PRClosed:
+		return policy.RulePRClosed
+	case w.Policy != nil:
+		return w.Policy.Rule
+	}
+	return ""
 }
 
 // StatusString returns a colored status string for display
 func (w *WorktreeInfo) StatusString() string {
+	if w.Missing {
+		return "[missing]"
+	}
+	if w.Orphaned {
+		return "[orphaned]"
+	}
 	if w.HasChanges {
 		return "[changes]"
 	}
 	if w.IsMerged {
 		return "[merged]"
 	}
+	if w.IsSquashed {
+		return "[squash-merged]"
+	}
 	if w.IsDeleted {
 		return "[deleted]"
 	}
+	if w.PRClosed {
+		return "[closed]"
+	}
+	if w.Policy != nil {
+		return "[policy]"
+	}
+	if w.Unpublished {
+		return "[unpublished]"
+	}
 	return "[active]"
 }
 
-func runCleanupList(cmd *cobra.Command, args []string) {
+func runCleanupList(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	repoFilter := ""
 	if len(args) > 0 {
 		repoFilter = args[0]
 	}
 
-	repos := discoverRepos()
-	if repos == nil {
-		return
+	repos, err := discoverRepos()
+	if err != nil {
+		return err
 	}
 	if len(repos) == 0 {
-		fmt.Println("No repositories found in git folder")
-		return
-	}
-
-	// Process repositories concurrently
-	type repoResult struct {
-		repoName  string
-		worktrees []WorktreeInfo
-		err       error
+		printer.Println("No repositories found in git folder")
+		return render(CleanupListReport{Worktrees: []WorktreeInfo{}})
 	}
 
-	var wg sync.WaitGroup
-	results := make(chan repoResult, len(repos))
+	worktrees, repoErrs := scanRepos(ctx, repos, repoFilter)
+	sortWorktrees(worktrees)
 
-	for _, repoPath := range repos {
-		repoName := filepath.Base(repoPath)
-		if repoFilter != "" && repoName != repoFilter {
-			continue
+	// Display results grouped by repository
+	report := CleanupListReport{Worktrees: []WorktreeInfo{}, Errors: repoErrs}
+	currentRepo := ""
+	for _, wt := range worktrees {
+		report.Worktrees = append(report.Worktrees, wt)
+		report.Total++
+		if wt.IsStale() {
+			report.Stale++
 		}
 
-		wg.Add(1)
-		go func(rPath, rName string) {
-			defer wg.Done()
-
-			worktrees, err := scanWorktrees(ctx, rPath, rName)
-			results <- repoResult{
-				repoName:  rName,
-				worktrees: worktrees,
-				err:       err,
-			}
-		}(repoPath, repoName)
-	}
-
-	// Close results channel after all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect and display results
-	totalWorktrees := 0
-	staleWorktrees := 0
-	hasResults := false
-
-	for result := range results {
-		if result.err != nil {
-			fmt.Fprintf(os.Stderr, "Error scanning %s: %v\n", result.repoName, result.err)
-			continue
+		if currentRepo != wt.RepoName {
+			currentRepo = wt.RepoName
+			printer.Printf("\nRepository: %s\n", wt.RepoName)
 		}
 
-		if len(result.worktrees) == 0 {
-			continue
+		printer.Printf("  %-30s %s", wt.Name(), wt.StatusString())
+		if wt.Reason != "" {
+			printer.Printf(" - %s", wt.Reason)
 		}
-
-		hasResults = true
-		fmt.Printf("\nRepository: %s\n", result.repoName)
-		for _, wt := range result.worktrees {
-			totalWorktrees++
-			if wt.IsStale() {
-				staleWorktrees++
-			}
-
-			branchDisplay := filepath.Base(wt.Path)
-			fmt.Printf("  %-30s %s", branchDisplay+"/", wt.StatusString())
-			if wt.Reason != "" {
-				fmt.Printf(" - %s", wt.Reason)
-			}
-			fmt.Println()
+		if unsafe := wt.Unsafe(); len(unsafe) > 0 {
+			printer.Printf(" (%s)", strings.Join(unsafe, ", "))
 		}
+		printer.Println()
 	}
 
-	if !hasResults || totalWorktrees == 0 {
+	if !printer.IsText() {
+		return render(report)
+	}
+
+	if report.Total == 0 {
 		fmt.Println("\nNo worktrees found")
-		return
+		return nil
 	}
 
-	fmt.Printf("\nTotal: %d worktrees", totalWorktrees)
-	if staleWorktrees > 0 {
-		fmt.Printf(" (%d can be cleaned up)\n", staleWorktrees)
+	fmt.Printf("\nTotal: %d worktrees", report.Total)
+	if report.Stale > 0 {
+		fmt.Printf(" (%d can be cleaned up)\n", report.Stale)
 		fmt.Println("Run 'work cleanup scan' to see details")
 	} else {
 		fmt.Println(" (all up-to-date)")
 	}
+	return nil
 }
 
-func runCleanupScan(cmd *cobra.Command, args []string) {
+func runCleanupScan(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	repoFilter := ""
 	if len(args) > 0 {
 		repoFilter = args[0]
 	}
 
-	repos := discoverRepos()
-	if repos == nil {
-		return
+	repos, err := discoverRepos()
+	if err != nil {
+		return err
 	}
 	if len(repos) == 0 {
-		fmt.Println("No repositories found in git folder")
-		return
+		printer.Println("No repositories found in git folder")
+		return render(CleanupScanReport{Stale: []WorktreeInfo{}})
 	}
 
-	fmt.Println("Scanning for stale worktrees...")
-
-	// Process repositories concurrently
-	type repoResult struct {
-		repoName  string
-		worktrees []WorktreeInfo
-		err       error
-	}
+	printer.Println("Scanning for stale worktrees...")
 
-	var wg sync.WaitGroup
-	results := make(chan repoResult, len(repos))
+	all, repoErrs := scanRepos(ctx, repos, repoFilter)
 
-	for _, repoPath := range repos {
-		repoName := filepath.Base(repoPath)
-		if repoFilter != "" && repoName != repoFilter {
-			continue
+	// Collect stale worktrees
+	report := CleanupScanReport{Stale: []WorktreeInfo{}, Errors: repoErrs}
+	if cleanupPolicy {
+		if err := applyCleanupPolicy(ctx, all); err != nil {
+			return err
 		}
-
-		wg.Add(1)
-		go func(rPath, rName string) {
-			defer wg.Done()
-
-			worktrees, err := scanWorktrees(ctx, rPath, rName)
-			results <- repoResult{
-				repoName:  rName,
-				worktrees: worktrees,
-				err:       err,
-			}
-		}(repoPath, repoName)
 	}
 
-	// Close results channel after all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect stale worktrees
-	var allStale []WorktreeInfo
-	totalSize := int64(0)
-
-	for result := range results {
-		if result.err != nil {
-			fmt.Fprintf(os.Stderr, "Error scanning %s: %v\n", result.repoName, result.err)
-			continue
-		}
+	var orphans int
+	report.Stale, orphans = selectStale(all)
+	for _, wt := range report.Stale {
+		report.TotalSizeBytes += wt.SizeBytes
+	}
+	sortWorktrees(report.Stale)
+	report.Total = len(report.Stale)
+	planBranchDeletions(ctx, report.Stale)
 
-		for _, wt := range result.worktrees {
-			if wt.IsStale() {
-				allStale = append(allStale, wt)
-				totalSize += wt.SizeBytes
-			}
-		}
+	if !printer.IsText() {
+		return render(report)
 	}
 
-	if len(allStale) == 0 {
+	if len(report.Stale) == 0 {
 		fmt.Println("\nNo stale worktrees found. Everything is clean!")
-		return
+		printOrphansSkipped(orphans)
+		return nil
 	}
 
 	fmt.Println()
 	currentRepo := ""
-	for _, wt := range allStale {
+	for _, wt := range report.Stale {
 		if currentRepo != wt.RepoName {
 			currentRepo = wt.RepoName
 			fmt.Printf("%s:\n", wt.RepoName)
 		}
 
-		branchDisplay := filepath.Base(wt.Path)
-		fmt.Printf("  %s/\n", branchDisplay)
+		fmt.Printf("  %s\n", wt.Name())
 		fmt.Printf("    Reason: %s\n", wt.Reason)
-		fmt.Printf("    Last modified: %s\n", wt.LastModified.Format("2006-01-02 15:04"))
+		fmt.Printf("    Rule: %s\n", wt.Rule())
+		if !wt.LastModified.IsZero() {
+			fmt.Printf("    Last modified: %s\n", wt.LastModified.Format("2006-01-02 15:04"))
+		}
+		if meta := wt.Metadata; meta != nil {
+			if !meta.CreatedAt.IsZero() {
+				fmt.Printf("    Created: %s by '%s'\n", meta.CreatedAt.Format("2006-01-02 15:04"), meta.Command)
+			}
+			if meta.Source != "" {
+				fmt.Printf("    Source: %s\n", meta.Source)
+			}
+			fmt.Printf("    Last opened: %s\n", formatAge(meta.LastOpened))
+		}
 		if wt.SizeBytes > 0 {
 			fmt.Printf("    Size: %s\n", formatBytes(wt.SizeBytes))
 		}
-		fmt.Printf("    Safe to remove: ✓\n")
+		if unsafe := wt.Unsafe(); len(unsafe) > 0 {
+			fmt.Printf("    Safe to remove: ✗ %s (needs --force-unsafe)\n", strings.Join(unsafe, ", "))
+		} else {
+			fmt.Printf("    Safe to remove: ✓\n")
+		}
+		if wt.BranchPlan != nil {
+			if desc := wt.BranchPlan.String(wt.Branch); desc != "" {
+				fmt.Printf("    Branch: %s\n", desc)
+			}
+		}
 		fmt.Println()
 	}
 
-	fmt.Printf("Total: %d worktrees", len(allStale))
-	if totalSize > 0 {
-		fmt.Printf(" (%s)", formatBytes(totalSize))
+	fmt.Printf("Total: %d worktrees", len(report.Stale))
+	if report.TotalSizeBytes > 0 {
+		fmt.Printf(" (%s)", formatBytes(report.TotalSizeBytes))
 	}
 	fmt.Println()
-	fmt.Println("Run 'work cleanup run' to remove them")
+	if cleanupPolicy {
+		fmt.Println("Run 'work cleanup run --policy' to remove them")
+	} else {
+		fmt.Println("Run 'work cleanup run' to remove them")
+	}
+	printOrphansSkipped(orphans)
+	return nil
 }
 
-func runCleanupRun(cmd *cobra.Command, args []string) {
+func runCleanupRun(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
+
+	// Structured output has no room for interactive prompts
+	if !printer.IsText() && !cleanupForce {
+		return errs.New(errs.Usage, "--output %s requires --force", printer.Format())
+	}
+
 	repoFilter := ""
 	if len(args) > 0 {
 		repoFilter = args[0]
 	}
 
-	repos := discoverRepos()
-	if repos == nil {
-		return
+	repos, err := discoverRepos()
+	if err != nil {
+		return err
 	}
 	if len(repos) == 0 {
-		fmt.Println("No repositories found in git folder")
-		return
-	}
-
-	fmt.Println("Scanning for stale worktrees...")
-
-	// Process repositories concurrently
-	type repoResult struct {
-		repoName  string
-		worktrees []WorktreeInfo
-		err       error
+		printer.Println("No repositories found in git folder")
+		return render(CleanupRunReport{Removed: []WorktreeInfo{}, Skipped: []WorktreeInfo{}, Failed: []CleanupFailure{}})
 	}
 
-	var wg sync.WaitGroup
-	results := make(chan repoResult, len(repos))
-
-	for _, repoPath := range repos {
-		repoName := filepath.Base(repoPath)
-		if repoFilter != "" && repoName != repoFilter {
-			continue
-		}
-
-		wg.Add(1)
-		go func(rPath, rName string) {
-			defer wg.Done()
-
-			worktrees, err := scanWorktrees(ctx, rPath, rName)
-			results <- repoResult{
-				repoName:  rName,
-				worktrees: worktrees,
-				err:       err,
-			}
-		}(repoPath, repoName)
-	}
+	printer.Println("Scanning for stale worktrees...")
 
-	// Close results channel after all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	all, repoErrs := scanRepos(ctx, repos, repoFilter)
 
 	// Collect stale worktrees
-	var allStale []WorktreeInfo
-	totalSize := int64(0)
-
-	for result := range results {
-		if result.err != nil {
-			fmt.Fprintf(os.Stderr, "Error scanning %s: %v\n", result.repoName, result.err)
-			continue
-		}
-
-		for _, wt := range result.worktrees {
-			if wt.IsStale() {
-				allStale = append(allStale, wt)
-				totalSize += wt.SizeBytes
-			}
+	report := CleanupRunReport{Removed: []WorktreeInfo{}, Skipped: []WorktreeInfo{}, Failed: []CleanupFailure{}, Errors: repoErrs}
+	if cleanupPolicy {
+		if err := applyCleanupPolicy(ctx, all); err != nil {
+			return err
 		}
 	}
 
+	allStale, orphans := selectStale(all)
+	sortWorktrees(allStale)
+
 	if len(allStale) == 0 {
-		fmt.Println("\nNo stale worktrees found. Everything is clean!")
-		return
+		printer.Println("\nNo stale worktrees found. Everything is clean!")
+		printOrphansSkipped(orphans)
+		return render(report)
 	}
 
-	fmt.Printf("\nFound %d stale worktrees to clean up\n\n", len(allStale))
-
-	removed := 0
-	skipped := 0
-	freedSpace := int64(0)
+	printer.Printf("\nFound %d stale worktrees to clean up\n\n", len(allStale))
+	planBranchDeletions(ctx, allStale)
+	printBranchPlan(allStale)
 
 	for _, wt := range allStale {
-		branchDisplay := filepath.Base(wt.Path)
+		if unsafe := wt.Unsafe(); len(unsafe) > 0 && !cleanupForceUnsafe {
+			printer.Printf("Skipped %s: %s (use --force-unsafe to remove)\n", wt.Label(), strings.Join(unsafe, ", "))
+			report.Skipped = append(report.Skipped, wt)
+			continue
+		}
 
 		shouldRemove := cleanupForce
 		if !cleanupForce {
-			fmt.Printf("Remove worktree '%s/%s' (%s)? [y/N] ", wt.RepoName, branchDisplay, wt.Reason)
+			fmt.Printf("Remove worktree '%s' (%s)? [y/N] ", wt.Label(), wt.Reason)
 
 			var response string
 			fmt.Scanln(&response)
@@ -403,127 +554,227 @@ func runCleanupRun(cmd *cobra.Command, args []string) {
 		}
 
 		if shouldRemove {
-			if err := removeWorktreeSafely(ctx, wt); err != nil {
+			var a *archive.Archive
+			if cleanupArchive && !wt.Missing && !wt.Orphaned {
+				if a, err = archiveWorktree(ctx, wt); err != nil {
+					fmt.Fprintf(os.Stderr, "  ✗ Error archiving worktree: %v\n", err)
+					report.Failed = append(report.Failed, CleanupFailure{Worktree: wt, Error: err.Error()})
+					continue
+				}
+				wt.Archive = a.ID
+			}
+			if wt.Orphaned {
+				wt.MovedTo, err = removeOrphanedFolder(ctx, wt)
+			} else {
+				err = removeWorktreeSafely(ctx, wt, cleanupForceUnsafe)
+			}
+			if err != nil {
 				fmt.Fprintf(os.Stderr, "  ✗ Error removing worktree: %v\n", err)
-				skipped++
+				report.Failed = append(report.Failed, CleanupFailure{Worktree: wt, Error: err.Error()})
+				if a != nil {
+					archive.Delete(ctx, services.Get().GitRunner, a)
+				}
 			} else {
-				fmt.Printf("  ✓ Removed %s/%s/\n", wt.RepoName, branchDisplay)
-				removed++
-				freedSpace += wt.SizeBytes
+				printer.Printf("  ✓ Removed %s\n", wt.Label())
+				if a != nil {
+					printer.Printf("    Archived as %s\n", a.ID)
+				}
+				if wt.MovedTo != "" {
+					printer.Printf("    Moved to %s\n", wt.MovedTo)
+				}
+				deleteBranches(ctx, &wt)
+				report.Removed = append(report.Removed, wt)
+				report.FreedBytes += wt.SizeBytes
 			}
 		} else {
-			fmt.Println("  Skipped")
-			skipped++
+			printer.Println("  Skipped")
+			report.Skipped = append(report.Skipped, wt)
 		}
 		if !cleanupForce {
-			fmt.Println()
+			printer.Println()
 		}
 	}
 
-	fmt.Printf("Cleanup complete!\n")
-	fmt.Printf("  Removed: %d worktrees", removed)
-	if freedSpace > 0 {
-		fmt.Printf(" (%s freed)", formatBytes(freedSpace))
+	removed := len(report.Removed)
+	skipped := len(report.Skipped) + len(report.Failed)
+
+	printer.Printf("Cleanup complete!\n")
+	printer.Printf("  Removed: %d worktrees", removed)
+	if report.FreedBytes > 0 {
+		printer.Printf(" (%s freed)", formatBytes(report.FreedBytes))
 	}
-	fmt.Println()
+	printer.Println()
 	if skipped > 0 {
-		fmt.Printf("  Skipped: %d worktrees\n", skipped)
+		printer.Printf("  Skipped: %d worktrees\n", skipped)
 	}
 
 	// Prune worktree metadata for each repo
 	if removed > 0 {
-		fmt.Println("\nCleaning up git metadata...")
+		paths := make([]string, 0, removed)
+		for _, wt := range report.Removed {
+			paths = append(paths, wt.Path)
+		}
+		if err := cache.DeleteWorktreeMeta(paths...); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not delete worktree records: %v\n", err)
+		}
+
+		printer.Println("\nCleaning up git metadata...")
 		processedRepos := make(map[string]bool)
-		for _, wt := range allStale {
+		for _, wt := range report.Removed {
 			if !processedRepos[wt.RepoPath] {
 				processedRepos[wt.RepoPath] = true
 				runner := services.Get().GitRunner
-				mainPath := filepath.Join(wt.RepoPath, "main")
-				if err := runner.PruneWorktrees(ctx, mainPath); err != nil {
+				if err := runner.PruneWorktrees(ctx, workspace.GitDir(wt.RepoPath)); err != nil {
 					fmt.Fprintf(os.Stderr, "  Warning: Could not prune %s: %v\n", wt.RepoName, err)
 				}
 			}
 		}
-		fmt.Println("  ✓ Metadata cleaned")
+		printer.Println("  ✓ Metadata cleaned")
 	}
-}
 
-// discoverRepos finds all git repositories in the default git folder
-func discoverRepos() []string {
-	gitFolder := config.GetString("default_git_folder")
-	if gitFolder == "" {
-		fmt.Fprintf(os.Stderr, "Error: default_git_folder not configured\n")
-		fmt.Fprintf(os.Stderr, "Run: work config set default_git_folder ~/git\n")
-		return nil
+	if err := render(report); err != nil {
+		return err
 	}
+	if len(report.Failed) > 0 {
+		return errs.Reported(errs.Failure, "%d worktrees could not be removed", len(report.Failed))
+	}
+	return nil
+}
 
-	// Expand home directory
-	if strings.HasPrefix(gitFolder, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Could not get home directory: %v\n", err)
-			return nil
+// sortWorktrees orders worktrees by repository and path so output is stable
+func sortWorktrees(worktrees []WorktreeInfo) {
+	sort.Slice(worktrees, func(i, j int) bool {
+		if worktrees[i].RepoName != worktrees[j].RepoName {
+			return worktrees[i].RepoName < worktrees[j].RepoName
 		}
-		gitFolder = filepath.Join(homeDir, gitFolder[2:])
+		return worktrees[i].Path < worktrees[j].Path
+	})
+}
+
+// discoverRepos finds all repo containers in the default git folder
+func discoverRepos() ([]string, error) {
+	gitFolder, err := getGitFolder()
+	if err != nil {
+		return nil, err
 	}
 
-	entries, err := os.ReadDir(gitFolder)
+	// Containers may be nested under host/org folders for non-github.com repos
+	repos, err := worktreeManager().Discover()
 	if err != nil {
 		if os.IsNotExist(err) {
-			fmt.Fprintf(os.Stderr, "Error: Git folder does not exist: %s\n", gitFolder)
-			fmt.Fprintf(os.Stderr, "Run: work config set default_git_folder <path>\n")
-		} else {
-			fmt.Fprintf(os.Stderr, "Error reading git folder: %v\n", err)
+			return nil, errs.New(errs.NotConfigured, "git folder does not exist: %s", gitFolder).
+				WithHint("Run: work config set default_git_folder <path>")
 		}
-		return nil
+		return nil, fmt.Errorf("reading git folder: %w", err)
 	}
 
-	var repos []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	return repos, nil
+}
+
+// scanRepos scans the repositories matching repoFilter on the worker pool. Warnings
+// are printed to stderr after the scan, grouped by repository; repositories that
+// could not be scanned are printed, without their warnings, and returned as errors.
+func scanRepos(ctx context.Context, repos []string, repoFilter string) ([]WorktreeInfo, []RepoError) {
+	var selected []string
+	for _, repoPath := range repos {
+		if repoFilter == "" || repoDisplayName(repoPath) == repoFilter {
+			selected = append(selected, repoPath)
 		}
+	}
 
-		// Check if this is a repo container (has a "main" subdirectory with .git)
-		mainPath := filepath.Join(gitFolder, entry.Name(), "main")
-		gitPath := filepath.Join(mainPath, ".git")
+	type repoScan struct {
+		worktrees []WorktreeInfo
+		warnings  bytes.Buffer
+	}
+	metas := loadWorktreeMeta()
+	scans, failures := forEachRepo(ctx, "Scanning", selected, func(ctx context.Context, repoPath string) (*repoScan, error) {
+		scan := &repoScan{}
+		worktrees, err := scanWorktrees(ctx, repoPath, repoDisplayName(repoPath), metas, &scan.warnings)
+		if err == nil {
+			err = ctx.Err() // A scan cut short by the timeout is incomplete
+		}
+		scan.worktrees = worktrees
+		return scan, err
+	})
 
-		if _, err := os.Stat(gitPath); err == nil {
-			repos = append(repos, filepath.Join(gitFolder, entry.Name()))
+	var all []WorktreeInfo
+	var repoErrs []RepoError
+	for i, scan := range scans {
+		repoName := repoDisplayName(selected[i])
+		if err := failures[i]; err != nil {
+			fmt.Fprintf(os.Stderr, "Error scanning %s: %v\n", repoName, err)
+			repoErrs = append(repoErrs, RepoError{Repo: repoName, Error: err.Error()})
+			continue
 		}
+		os.Stderr.Write(scan.warnings.Bytes())
+		all = append(all, scan.worktrees...)
 	}
+	return all, repoErrs
+}
 
-	return repos
+// loadWorktreeMeta returns the worktree records keyed by path. The records are
+// informational, so an unreadable database yields none.
+func loadWorktreeMeta() map[string]cache.WorktreeMeta {
+	metas, err := cache.LoadWorktreeMeta()
+	if err != nil {
+		return map[string]cache.WorktreeMeta{}
+	}
+	return metas
 }
 
-// scanWorktrees scans a repository for all worktrees and their status
-func scanWorktrees(ctx context.Context, repoPath, repoName string) ([]WorktreeInfo, error) {
+// scanWorktrees scans a repository for all worktrees and their status; metas are
+// the worktree records from loadWorktreeMeta.
+// Non-fatal problems (such as a failed fetch) are reported to warn.
+func scanWorktrees(ctx context.Context, repoPath, repoName string, metas map[string]cache.WorktreeMeta, warn io.Writer) ([]WorktreeInfo, error) {
 	runner := services.Get().GitRunner
-	mainPath := filepath.Join(repoPath, "main")
+	gitDir := workspace.GitDir(repoPath)
 
 	// Get default branch
-	defaultBranch, err := runner.GetDefaultBranch(ctx, mainPath)
+	defaultBranch, err := runner.GetDefaultBranch(ctx, gitDir)
 	if err != nil {
 		// Fallback to "main" if we can't determine
 		defaultBranch = "main"
 	}
 
 	// Fetch and prune to get latest remote state
-	if err := runner.FetchPrune(ctx, mainPath); err != nil {
+	if err := runner.FetchPrune(ctx, gitDir); err != nil {
 		// Non-fatal, continue without fetch
-		fmt.Fprintf(os.Stderr, "  Warning: Could not fetch from remote for %s: %v\n", repoName, err)
+		fmt.Fprintf(warn, "  Warning: Could not fetch from remote for %s: %v\n", repoName, err)
+	}
+
+	// Compare with origin/<default>, since checkout never pulls the local default branch
+	base := defaultBranch
+	if exists, _ := runner.RemoteBranchExists(ctx, gitDir, defaultBranch); exists {
+		base = "origin/" + defaultBranch
+	}
+
+	// Pull requests of ordinary branches are looked up on the forge hosting origin
+	var client forge.Forge
+	var owner, name string
+	if !cleanupNoPR {
+		if c, origin, err := getOriginForge(gitDir); err == nil {
+			client, owner, name = c, origin.Owner(), origin.Name()
+		}
+	}
+
+	// Stashes are shared by all worktrees of a repository
+	stashes, err := runner.StashBranches(ctx, gitDir)
+	if err != nil {
+		fmt.Fprintf(warn, "  Warning: Could not list stashes for %s: %v\n", repoName, err)
 	}
 
 	// List all worktrees
-	worktrees, err := runner.ListWorktrees(ctx, mainPath)
+	worktrees, err := runner.ListWorktrees(ctx, gitDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
 	var result []WorktreeInfo
-	for _, wt := range worktrees {
-		// Skip the main worktree
-		if filepath.Base(wt.Path) == "main" {
+	for i, wt := range worktrees {
+		// Skip the main worktree (or bare repository), which git always lists first, and
+		// the default branch worktree of a bare container. Worktrees are matched by the
+		// branch git reports, never by folder name.
+		if i == 0 || wt.Branch == defaultBranch {
 			continue
 		}
 
@@ -533,12 +784,41 @@ func scanWorktrees(ctx context.Context, repoPath, repoName string) ([]WorktreeIn
 			Path:          wt.Path,
 			Branch:        wt.Branch,
 			DefaultBranch: defaultBranch,
+			base:          base,
+			PullRequest:   worktreeManager().PullRequest(ctx, repoPath, wt.Branch),
+			Locked:        wt.Locked,
+			LockReason:    wt.LockReason,
+		}
+		if meta, ok := metas[wt.Path]; ok {
+			info.Metadata = &meta
+		}
+
+		// A folder deleted by hand leaves its registration behind. git reports it as
+		// prunable, unless the worktree is locked.
+		if _, err := os.Stat(wt.Path); wt.Prunable || os.IsNotExist(err) {
+			info.Missing = true
+			info.Reason = "Folder is missing"
+			result = append(result, info)
+			continue
+		}
+
+		// Seeing origin/<branch> once tells later scans that a branch created by work was pushed
+		if meta := info.Metadata; meta != nil && meta.Branch == wt.Branch && meta.Unpublished() {
+			if exists, err := runner.RemoteBranchExists(ctx, wt.Path, wt.Branch); err == nil && exists {
+				meta.Published = true
+				if err := cache.MarkWorktreePublished(wt.Path); err != nil {
+					fmt.Fprintf(warn, "  Warning: %s: %v\n", wt.Path, err)
+				}
+			}
 		}
 
 		// Get last modified time
 		if stat, err := os.Stat(filepath.Join(wt.Path, ".git")); err == nil {
 			info.LastModified = stat.ModTime()
 		}
+		if when, err := runner.LastCommitTime(ctx, wt.Path, "HEAD"); err == nil {
+			info.LastCommit = when
+		}
 
 		// Calculate directory size (approximate)
 		if size, err := getDirSize(wt.Path); err == nil {
@@ -555,54 +835,337 @@ func scanWorktrees(ctx context.Context, repoPath, repoName string) ([]WorktreeIn
 			info.Reason = "Has uncommitted changes"
 		}
 
-		// Only check merge/delete status if no changes
-		if !info.HasChanges {
+		// A detached HEAD has no branch to look up; it is merged once its commit is
+		if !info.HasChanges && wt.Detached {
+			if _, err := runner.Run(ctx, gitDir, "merge-base", "--is-ancestor", wt.Commit, base); err == nil {
+				info.IsMerged = true
+				info.Reason = fmt.Sprintf("Detached HEAD is on %s", defaultBranch)
+			}
+		} else if !info.HasChanges {
+			// Only check merge/delete status if no changes
 			// Check if merged
-			isMerged, err := runner.IsBranchMerged(ctx, mainPath, wt.Branch, defaultBranch)
+			isMerged, err := runner.IsBranchMerged(ctx, gitDir, wt.Branch, base)
 			if err == nil && isMerged {
 				info.IsMerged = true
 				info.Reason = fmt.Sprintf("Merged to %s", defaultBranch)
+			} else if squashed, err := runner.IsSquashMerged(ctx, gitDir, wt.Branch, base); err == nil && squashed {
+				info.IsSquashed = true
+				info.MergedHead = wt.Commit
+				info.Reason = fmt.Sprintf("Squash- or rebase-merged to %s", defaultBranch)
+			} else if client != nil && info.PullRequest == "" {
+				if pr := mergedPR(ctx, client, owner, name, gitDir, wt.Branch); pr != nil {
+					info.IsSquashed = true
+					info.Reason = fmt.Sprintf("Pull request #%d merged", pr.Number)
+					// Commits up to the merged head are merged; later ones are not
+					if _, err := runner.Run(ctx, gitDir, "cat-file", "-e", pr.HeadSHA+"^{commit}"); pr.HeadSHA != "" && err == nil {
+						info.MergedHead = pr.HeadSHA
+					}
+				}
 			}
-
-			// Check if remote branch exists
-			if !info.IsMerged {
+			merged := info.IsMerged || info.IsSquashed
+
+			// Pull request worktrees have no remote branch; the pull request state decides
+			if info.PullRequest != "" {
+				if !merged {
+					if state := pullRequestState(ctx, info.PullRequest); state == "closed" || state == "merged" {
+						info.PRClosed = true
+						info.Reason = fmt.Sprintf("Pull request %s", state)
+					}
+				}
+			} else if !merged {
+				// Check if remote branch exists
+				// A new branch that was never pushed has no remote branch to lose
 				exists, err := runner.RemoteBranchExists(ctx, wt.Path, wt.Branch)
-				if err == nil && !exists {
+				meta := info.Metadata
+				switch {
+				case err != nil || exists:
+				case meta != nil && meta.Branch == wt.Branch && meta.Unpublished():
+					info.Unpublished = true
+					info.Reason = "Branch not pushed yet"
+				default:
 					info.IsDeleted = true
 					info.Reason = "Remote branch deleted"
 				}
 			}
 		}
 
+		if err := checkWorktreeSafety(ctx, &info, stashes); err != nil {
+			fmt.Fprintf(warn, "  Warning: %s: %v\n", info.Path, err)
+		}
+
+		result = append(result, info)
+	}
+
+	// Folders git does not know about, e.g. worktrees whose metadata was pruned
+	orphans, err := worktreeManager().OrphanedFolders(ctx, repoPath)
+	if err != nil {
+		fmt.Fprintf(warn, "  Warning: Could not look for orphaned folders in %s: %v\n", repoName, err)
+	}
+	for _, path := range orphans {
+		info := WorktreeInfo{
+			RepoName:      repoName,
+			RepoPath:      repoPath,
+			Path:          path,
+			DefaultBranch: defaultBranch,
+			Orphaned:      true,
+			Reason:        "Not a registered worktree",
+		}
+		if stat, err := os.Stat(path); err == nil {
+			info.LastModified = stat.ModTime()
+		}
+		if size, err := getDirSize(path); err == nil {
+			info.SizeBytes = size
+		}
+		if info.OrphanFiles = countFiles(path); info.OrphanFiles > 0 {
+			info.Reason += fmt.Sprintf(" (%d files)", info.OrphanFiles)
+		}
 		result = append(result, info)
 	}
 
 	return result, nil
 }
 
-// removeWorktreeSafely removes a worktree after safety checks
-func removeWorktreeSafely(ctx context.Context, info WorktreeInfo) error {
+// applyCleanupPolicy marks the worktrees selected by the cleanup policies in the
+// config. Worktrees with changes are never selected.
+func applyCleanupPolicy(ctx context.Context, worktrees []WorktreeInfo) error {
+	p, err := policy.New(services.Get().Config.Cleanup)
+	if err != nil {
+		return errs.Wrap(errs.Usage, err, "invalid cleanup policy").
+			WithHint("Fix the cleanup section of ~/.work/config.yaml")
+	}
+	if p.Empty() {
+		return errs.New(errs.NotConfigured, "no cleanup policies configured").
+			WithHint("Set one, e.g.: work config set cleanup.inactive_for 30d")
+	}
+
+	var free int64
+	if p.MinFreeBytes > 0 {
+		gitFolder, _ := getGitFolder()
+		if free, err = policy.FreeBytes(gitFolder); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not read free disk space, skipping %s: %v\n", policy.RuleMinFreeDisk, err)
+			p.MinFreeBytes = 0
+		}
+	}
+
+	candidates := make([]policy.Worktree, len(worktrees))
+	var lookups []int
+	for i := range worktrees {
+		wt := &worktrees[i]
+		lastActive := wt.LastCommit
+		if wt.Metadata != nil && wt.Metadata.LastOpened.After(lastActive) {
+			lastActive = wt.Metadata.LastOpened
+		}
+		candidates[i] = policy.Worktree{
+			Key:        wt.Path,
+			Repo:       wt.RepoName,
+			LastActive: lastActive,
+			SizeBytes:  wt.SizeBytes,
+			Keep:       wt.HasChanges,
+			Stale:      wt.IsStale(),
+		}
+
+		// Pull requests of ordinary branches are looked up by head branch
+		if p.PRClosed && !wt.HasChanges && !wt.IsStale() && wt.PullRequest == "" {
+			lookups = append(lookups, i)
+		}
+	}
+	parallel.Map(ctx, lookups, parallel.Options{Limit: poolOptions().Limit}, func(ctx context.Context, i int) (struct{}, error) {
+		wt := &worktrees[i]
+		client, origin, err := getOriginForge(workspace.GitDir(wt.RepoPath))
+		if err != nil {
+			return struct{}{}, err
+		}
+		if pr, err := client.FindPR(ctx, origin.Owner(), origin.Name(), wt.Branch); err == nil && pr != nil {
+			candidates[i].PRClosed = pr.State == "closed" || pr.State == "merged"
+		}
+		return struct{}{}, nil
+	})
+
+	matches := p.Evaluate(candidates, free, time.Now())
+	for i := range worktrees {
+		if m, ok := matches[worktrees[i].Path]; ok {
+			worktrees[i].Policy = &m
+			worktrees[i].Reason = m.Reason
+		}
+	}
+	return nil
+}
+
+// mergedPR returns the merged pull request of branch, or nil. Branches with commits
+// that were not pushed to origin/<branch> since are not considered merged.
+func mergedPR(ctx context.Context, client forge.Forge, owner, name, gitDir, branch string) *forge.PullRequest {
+	pr, err := client.FindPR(ctx, owner, name, branch)
+	if err != nil || pr == nil || pr.State != "merged" {
+		return nil
+	}
 	runner := services.Get().GitRunner
-	mainPath := filepath.Join(info.RepoPath, "main")
+	if exists, _ := runner.RemoteBranchExists(ctx, gitDir, branch); exists {
+		if ahead, _, err := runner.AheadBehind(ctx, gitDir, "origin/"+branch, branch); err != nil || ahead > 0 {
+			return nil
+		}
+	}
+	return pr
+}
 
-	// Double-check git status before removal
-	status, err := runner.GetGitStatus(ctx, info.Path)
+// pullRequestState returns the state of the pull request at url ("open", "closed" or
+// "merged"), or "" when it cannot be looked up.
+func pullRequestState(ctx context.Context, url string) string {
+	pull, err := forge.ParsePullURL(url)
+	if err != nil {
+		return ""
+	}
+	client, err := forgeForHost(pull.Host)
 	if err != nil {
-		return fmt.Errorf("failed to check status: %w", err)
+		return ""
+	}
+	pr, err := client.GetPR(ctx, pull.Owner, pull.Repo, pull.Number)
+	if err != nil {
+		return ""
+	}
+	return pr.State
+}
+
+// archiveWorktree archives info before it is removed by 'run --archive'.
+func archiveWorktree(ctx context.Context, info WorktreeInfo) (*archive.Archive, error) {
+	cfg := services.Get().Config
+	return archive.Create(ctx, services.Get().GitRunner, archive.Options{
+		Repo:      info.RepoName,
+		Container: info.RepoPath,
+		Path:      info.Path,
+		Branch:    info.Branch,
+		Files:     cfg.Cleanup.ArchiveFiles,
+		Metadata:  info.Metadata,
+	}, time.Now())
+}
+
+// removeWorktreeSafely removes a worktree after safety checks. Unpushed commits,
+// stashes, operations in progress and locks are only accepted with allowUnsafe. Of a
+// missing worktree only the registration is removed.
+func removeWorktreeSafely(ctx context.Context, info WorktreeInfo, allowUnsafe bool) error {
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(info.RepoPath)
+
+	if !info.Missing {
+		// Double-check git status before removal
+		status, err := runner.GetGitStatus(ctx, info.Path)
+		if err != nil {
+			return fmt.Errorf("failed to check status: %w", err)
+		}
+
+		if len(status) > 0 {
+			return fmt.Errorf("worktree has uncommitted changes, refusing to remove")
+		}
+
+		if !allowUnsafe {
+			stashes, err := runner.StashBranches(ctx, gitDir)
+			if err != nil {
+				return fmt.Errorf("failed to list stashes: %w", err)
+			}
+			if err := checkWorktreeSafety(ctx, &info, stashes); err != nil {
+				return err
+			}
+			if unsafe := info.Unsafe(); len(unsafe) > 0 {
+				return fmt.Errorf("worktree is unsafe to remove (%s)", strings.Join(unsafe, ", "))
+			}
+		}
 	}
 
-	if len(status) > 0 {
-		return fmt.Errorf("worktree has uncommitted changes, refusing to remove")
+	if info.Locked {
+		if !allowUnsafe {
+			return fmt.Errorf("worktree is locked, refusing to remove")
+		}
+		if _, err := runner.Run(ctx, gitDir, "worktree", "unlock", info.Path); err != nil {
+			return fmt.Errorf("git worktree unlock failed: %w", err)
+		}
 	}
 
 	// Remove the worktree
-	if err := runner.RemoveWorktree(ctx, mainPath, info.Path); err != nil {
+	if err := runner.RemoveWorktree(ctx, gitDir, info.Path); err != nil {
 		return fmt.Errorf("git worktree remove failed: %w", err)
 	}
+	// e.g. feature/ once feature/login is gone
+	workspace.RemoveEmptyParents(info.RepoPath, info.Path)
 
 	return nil
 }
 
+// selectStale returns the stale worktrees to clean up and how many orphaned folders
+// were left out because --orphans is not set.
+func selectStale(all []WorktreeInfo) ([]WorktreeInfo, int) {
+	var stale []WorktreeInfo
+	orphans := 0
+	for _, wt := range all {
+		switch {
+		case !wt.IsStale():
+		case wt.Orphaned && !cleanupOrphans:
+			orphans++
+		default:
+			stale = append(stale, wt)
+		}
+	}
+	return stale, orphans
+}
+
+// printOrphansSkipped points at --orphans when orphaned folders were left out.
+func printOrphansSkipped(orphans int) {
+	if orphans > 0 {
+		printer.Printf("%d orphaned folders not included; add --orphans to move them to ~/.work/archive/folders\n", orphans)
+	}
+}
+
+// removeOrphanedFolder clears away a folder that is not a registered worktree and
+// returns where it was moved. Empty folders are deleted; folders with files in them
+// are moved to the archive directory, since nothing else records what they hold.
+func removeOrphanedFolder(ctx context.Context, info WorktreeInfo) (string, error) {
+	// A worktree may have been added there since the scan
+	orphans, err := worktreeManager().OrphanedFolders(ctx, info.RepoPath)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(orphans, info.Path) {
+		return "", fmt.Errorf("folder is no longer orphaned, refusing to remove")
+	}
+	var dest string
+	if countFiles(info.Path) == 0 {
+		err = os.RemoveAll(info.Path)
+	} else {
+		name := workspace.WorktreeName(info.RepoPath, info.Path)
+		dest, err = archive.MoveFolder(info.RepoName, name, info.Path, time.Now())
+	}
+	if err != nil {
+		return "", err
+	}
+	workspace.RemoveEmptyParents(info.RepoPath, info.Path)
+	return dest, nil
+}
+
+// checkWorktreeSafety fills in the unpushed commits, stashes and operation in
+// progress of info. stashes are the repository's stash counts by branch. Commits that
+// are on no remote count as unpushed unless they are on the default branch, have a
+// patch-equivalent commit there, or are part of info.MergedHead; commits made after
+// a squash merge always count.
+func checkWorktreeSafety(ctx context.Context, info *WorktreeInfo, stashes map[string]int) error {
+	runner := services.Get().GitRunner
+	var merged []string
+	if info.MergedHead != "" {
+		merged = append(merged, info.MergedHead)
+	}
+	n, err := runner.UnmergedCommits(ctx, info.Path, "HEAD", info.base, merged, workspace.PullHeadRefs+"*")
+	if err != nil {
+		return fmt.Errorf("failed to count unpushed commits: %w", err)
+	}
+	info.Unpushed = n
+	if info.Branch != "" {
+		info.Stashes = stashes[info.Branch]
+	}
+	op, err := runner.InProgress(ctx, info.Path)
+	if err != nil {
+		return fmt.Errorf("failed to check for operations in progress: %w", err)
+	}
+	info.InProgress = op
+	return nil
+}
+
 // getDirSize calculates the approximate size of a directory
 func getDirSize(path string) (int64, error) {
 	var size int64
@@ -618,6 +1181,18 @@ func getDirSize(path string) (int64, error) {
 	return size, err
 }
 
+// countFiles returns the number of files below path
+func countFiles(path string) int {
+	n := 0
+	filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
 // formatBytes formats bytes as human-readable string
 func formatBytes(bytes int64) string {
 	const unit = 1024
@@ -640,6 +1215,19 @@ func init() {
 
 	// Add flags to run command
 	cleanupRunCmd.Flags().BoolVarP(&cleanupForce, "force", "f", false, "Skip confirmation prompts and remove all stale worktrees")
+	cleanupRunCmd.Flags().BoolVar(&cleanupPolicy, "policy", false, "Also remove worktrees selected by the cleanup policies in the config")
+	cleanupRunCmd.Flags().BoolVar(&cleanupForceUnsafe, "force-unsafe", false, "Also remove worktrees with unpushed commits, stashes or an operation in progress")
+	cleanupRunCmd.Flags().BoolVar(&cleanupOrphans, "orphans", false, "Also clear away folders that are not registered worktrees: empty ones are deleted, others moved to ~/.work/archive/folders")
+	cleanupRunCmd.Flags().BoolVar(&cleanupArchive, "archive", false, "Archive unpushed commits and files like .env.local so 'cleanup restore' can recreate the worktree")
+	cleanupScanCmd.Flags().BoolVar(&cleanupPolicy, "policy", false, "Also show worktrees selected by the cleanup policies in the config")
+	cleanupScanCmd.Flags().BoolVar(&cleanupOrphans, "orphans", false, "Also show folders that are not registered worktrees")
+	cleanupScanCmd.Flags().BoolVar(&cleanupArchive, "archive", false, "Plan branch deletions as 'run --archive' would: branches with unpushed commits are archived, then deleted")
+	cleanupScanCmd.Flags().BoolVar(&cleanupForceUnsafe, "force-unsafe", false, "Plan branch deletions as 'run --force-unsafe' would, including those of unsafe worktrees")
+	for _, c := range []*cobra.Command{cleanupScanCmd, cleanupRunCmd} {
+		c.Flags().BoolVar(&cleanupDeleteBranches, "delete-branches", false, "Delete the local branch of each removed worktree, except protected branches")
+		c.Flags().BoolVar(&cleanupDeleteRemote, "delete-remote", false, "Delete the branch on origin when the forge reports its pull request as merged")
+	}
+	cleanupCmd.PersistentFlags().BoolVar(&cleanupNoPR, "no-pr", false, "Skip pull request lookups on the forge")
 
 	// Register cleanup command with root
 	rootCmd.AddCommand(cleanupCmd)
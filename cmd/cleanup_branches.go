@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/velvee-ai/ai-workflow/pkg/archive"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/policy"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+var (
+	cleanupDeleteBranches bool
+	cleanupDeleteRemote   bool
+)
+
+// BranchPlan is what cleanup does with the branch of a worktree it removes, with
+// --delete-branches (Local) and --delete-remote (Remote).
+type BranchPlan struct {
+	Local       bool   `json:"delete_local"`
+	Remote      bool   `json:"delete_remote"`
+	PullRequest int    `json:"merged_pull_request,omitempty"` // Confirms Remote
+	KeepLocal   string `json:"keep_local,omitempty"`          // Why the local branch is kept
+	KeepRemote  string `json:"keep_remote,omitempty"`         // Why the remote branch is kept
+	Error       string `json:"error,omitempty"`               // Set when a deletion failed
+}
+
+// String describes the plan, e.g. "delete branch, delete origin/x (pull request #3 merged)".
+func (p *BranchPlan) String(branch string) string {
+	var local, remote string
+	switch {
+	case p.Local:
+		local = "delete branch"
+	case p.KeepLocal != "":
+		local = "keep branch (" + p.KeepLocal + ")"
+	}
+	switch {
+	case p.Remote:
+		remote = fmt.Sprintf("delete origin/%s (pull request #%d merged)", branch, p.PullRequest)
+	case p.KeepRemote != "":
+		remote = "keep origin/" + branch + " (" + p.KeepRemote + ")"
+	}
+	if local != "" && remote != "" {
+		return local + ", " + remote
+	}
+	return local + remote
+}
+
+// planBranchDeletions fills in the branch plan of each worktree for the deletion flags.
+// Local branches are only deleted when none of their commits would be lost or they are
+// archived. Remote branches are only deleted when the forge reports the branch's pull request as
+// merged and origin has nothing the local branch lacks.
+func planBranchDeletions(ctx context.Context, worktrees []WorktreeInfo) {
+	if !cleanupDeleteBranches && !cleanupDeleteRemote {
+		return
+	}
+	runner := services.Get().GitRunner
+	cleanupCfg := services.Get().Config.Cleanup
+
+	type originForge struct {
+		client      forge.Forge
+		owner, name string
+	}
+	forges := make(map[string]*originForge)
+
+	for i := range worktrees {
+		wt := &worktrees[i]
+		if len(wt.Unsafe()) > 0 && !cleanupForceUnsafe {
+			continue // 'cleanup run' skips it
+		}
+		if wt.Orphaned {
+			continue // No branch
+		}
+		plan := &BranchPlan{}
+		wt.BranchPlan = plan
+		gitDir := workspace.GitDir(wt.RepoPath)
+
+		var protected string
+		if wt.Branch == "" {
+			protected = "detached HEAD"
+		} else if wt.Branch == wt.DefaultBranch {
+			protected = "default branch"
+		} else if pattern, ok := policy.Protected(cleanupCfg.ProtectedBranchesFor(wt.RepoName), wt.Branch); ok {
+			protected = "protected by " + pattern
+		}
+
+		if cleanupDeleteBranches {
+			// The branch of a missing worktree is not archived
+			archived := cleanupArchive && !wt.Missing
+			var unmerged int
+			var err error
+			if protected == "" {
+				unmerged, err = unmergedBranchCommits(ctx, wt)
+			}
+			switch {
+			case protected != "":
+				plan.KeepLocal = protected
+			case err != nil:
+				plan.KeepLocal = "could not count unpushed commits"
+			case unmerged > 0 && !archived:
+				plan.KeepLocal = "unpushed commits"
+			default:
+				plan.Local = true
+			}
+		}
+
+		// Pull request worktrees have no branch of their own on origin
+		if !cleanupDeleteRemote || wt.Branch == "" || wt.PullRequest != "" {
+			continue
+		}
+		if exists, _ := runner.RemoteBranchExists(ctx, gitDir, wt.Branch); !exists {
+			continue
+		}
+		if protected != "" {
+			plan.KeepRemote = protected
+			continue
+		}
+
+		of, ok := forges[wt.RepoPath]
+		if !ok {
+			of = &originForge{}
+			if client, origin, err := getOriginForge(gitDir); err == nil {
+				of = &originForge{client: client, owner: origin.Owner(), name: origin.Name()}
+			}
+			forges[wt.RepoPath] = of
+		}
+		if of.client == nil {
+			plan.KeepRemote = "no forge to confirm the merge"
+			continue
+		}
+		pr, err := of.client.FindPR(ctx, of.owner, of.name, wt.Branch)
+		switch {
+		case err != nil:
+			plan.KeepRemote = "pull request lookup failed"
+			continue
+		case pr == nil:
+			plan.KeepRemote = "no pull request"
+			continue
+		case pr.State != "merged":
+			plan.KeepRemote = fmt.Sprintf("pull request #%d %s", pr.Number, pr.State)
+			continue
+		}
+		if ahead, _, err := runner.AheadBehind(ctx, gitDir, wt.Branch, "origin/"+wt.Branch); err != nil || ahead > 0 {
+			plan.KeepRemote = "origin has commits the local branch lacks"
+			continue
+		}
+		plan.Remote = true
+		plan.PullRequest = pr.Number
+	}
+}
+
+// unmergedBranchCommits counts the commits of the worktree's branch that are on no
+// remote, not in its default branch, not patch-equivalent to a commit there and not
+// part of its merged head. Commits under the published ref globs count as kept.
+func unmergedBranchCommits(ctx context.Context, wt *WorktreeInfo, published ...string) (int, error) {
+	var merged []string
+	if wt.MergedHead != "" {
+		merged = append(merged, wt.MergedHead)
+	}
+	runner := services.Get().GitRunner
+	published = append(published, workspace.PullHeadRefs+"*")
+	return runner.UnmergedCommits(ctx, workspace.GitDir(wt.RepoPath), "refs/heads/"+wt.Branch, wt.base, merged, published...)
+}
+
+// deleteLocalBranch deletes the branch of a worktree with 'git branch -d'. When git
+// refuses because it cannot see the branch as merged, e.g. after a squash merge, the
+// commits are counted again and the branch is only deleted with -D if each of them is
+// on a remote, patch-equivalent to the default branch or archived.
+func deleteLocalBranch(ctx context.Context, wt *WorktreeInfo) error {
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(wt.RepoPath)
+	_, err := runner.Run(ctx, gitDir, "branch", "-d", wt.Branch)
+	if err == nil {
+		return nil
+	}
+	if n, cerr := unmergedBranchCommits(ctx, wt, archive.RefPrefix+"*"); cerr != nil || n > 0 {
+		return err
+	}
+	_, err = runner.Run(ctx, gitDir, "branch", "-D", wt.Branch)
+	return err
+}
+
+// printBranchPlan prints the branch deletions cleanup would make.
+func printBranchPlan(worktrees []WorktreeInfo) {
+	if !cleanupDeleteBranches && !cleanupDeleteRemote {
+		return
+	}
+	printer.Println("Branch plan:")
+	for _, wt := range worktrees {
+		if wt.BranchPlan == nil {
+			continue
+		}
+		if desc := wt.BranchPlan.String(wt.Branch); desc != "" {
+			printer.Printf("  %s: %s\n", wt.Label(), desc)
+		}
+	}
+	printer.Println()
+}
+
+// deleteBranches carries out the branch plan of a worktree that was just removed.
+func deleteBranches(ctx context.Context, wt *WorktreeInfo) {
+	plan := wt.BranchPlan
+	if plan == nil {
+		return
+	}
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(wt.RepoPath)
+
+	if plan.Local {
+		if err := deleteLocalBranch(ctx, wt); err != nil {
+			fmt.Fprintf(os.Stderr, "    ✗ Could not delete branch %s: %v\n", wt.Branch, err)
+			plan.Local = false
+			plan.Error = err.Error()
+		} else {
+			printer.Printf("    ✓ Deleted branch %s\n", wt.Branch)
+			// The fetched head of a 'checkout pr' branch is no longer needed either
+			if pull, err := forge.ParsePullURL(wt.PullRequest); err == nil {
+				runner.Run(ctx, gitDir, "update-ref", "-d", workspace.PullHeadRefs+strconv.Itoa(pull.Number))
+			}
+		}
+	}
+	if plan.Remote {
+		if _, err := runner.Run(ctx, gitDir, "push", "origin", "--delete", wt.Branch); err != nil {
+			fmt.Fprintf(os.Stderr, "    ✗ Could not delete origin/%s: %v\n", wt.Branch, err)
+			plan.Remote = false
+			plan.Error = err.Error()
+		} else {
+			printer.Printf("    ✓ Deleted origin/%s\n", wt.Branch)
+		}
+	}
+}
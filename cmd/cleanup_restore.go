@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/velvee-ai/ai-workflow/pkg/archive"
+	"github.com/velvee-ai/ai-workflow/pkg/cache"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+var cleanupRestoreCmd = &cobra.Command{
+	Use:   "restore [id]",
+	Short: "Recreate a worktree removed with 'cleanup run --archive'",
+	Long: `Recreate a worktree from an archive made by 'work cleanup run --archive'.
+
+The branch is recreated at the archived commit (or fast-forwarded to it if it still
+exists), a worktree is added for it, and the archived files are put back. The
+archive is deleted once the worktree is restored. Without an ID, the available
+archives are listed, newest first.
+
+Example:
+  work cleanup restore
+  work cleanup restore api-feature-login-20260301-093000`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		archives, _ := archive.List()
+		ids := make([]string, 0, len(archives))
+		for _, a := range archives {
+			ids = append(ids, a.ID+"\t"+a.Repo+" "+a.Branch)
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+	},
+	RunE: runCleanupRestore,
+}
+
+// ArchiveListReport is the structured result of 'work cleanup restore' without an ID.
+type ArchiveListReport struct {
+	Archives []archive.Archive `json:"archives"`
+}
+
+// RestoreResult is the structured result of 'work cleanup restore <id>'.
+type RestoreResult struct {
+	Archive string   `json:"archive"`
+	Repo    string   `json:"repo"`
+	Branch  string   `json:"branch"`
+	Path    string   `json:"path"`
+	Commit  string   `json:"commit"`
+	Files   []string `json:"files,omitempty"`
+}
+
+func runCleanupRestore(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return listArchives()
+	}
+
+	a, err := archive.Load(args[0])
+	if errors.Is(err, archive.ErrNotFound) {
+		return errs.Wrap(errs.Usage, err, "").
+			WithHint("Run 'work cleanup restore' to list the archives")
+	} else if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	runner := services.Get().GitRunner
+	ws := worktreeManager()
+	if !workspace.IsContainer(a.Container) {
+		return errs.New(errs.RepoNotFound, "repository '%s' no longer exists at %s", a.Repo, a.Container).
+			WithHint("Clone it again with 'work checkout %s', then retry", a.Repo)
+	}
+	gitDir := workspace.GitDir(a.Container)
+	if _, err := runner.Run(ctx, gitDir, "rev-parse", "--verify", "--quiet", a.Ref+"^{commit}"); err != nil {
+		return errs.New(errs.Failure, "%s is missing from %s", a.Ref, a.Repo)
+	}
+	if path, ok := ws.FindWorktree(ctx, a.Container, a.Branch); ok {
+		return errs.New(errs.Conflict, "branch '%s' is already checked out at %s", a.Branch, path)
+	}
+
+	worktreePath, err := ws.NewWorktreePath(ctx, a.Container, a.Branch)
+	if errors.Is(err, workspace.ErrDirInUse) {
+		return errs.Wrap(errs.Conflict, err, "").
+			WithHint("Clean up '%s' or set a different worktree_dir_template", worktreePath)
+	} else if err != nil {
+		return err
+	}
+
+	// The branch survives 'git worktree remove' and may have moved since. It is kept
+	// when it contains the archived commit and fast-forwarded when it is behind.
+	args = []string{"worktree", "add", "-b", a.Branch, worktreePath, a.Commit}
+	fastForward := false
+	if runner.BranchExists(ctx, gitDir, a.Branch) {
+		head, _ := runner.RunSimple(ctx, gitDir, "rev-parse", "refs/heads/"+a.Branch)
+		if _, err := runner.Run(ctx, gitDir, "merge-base", "--is-ancestor", a.Commit, head); err != nil {
+			if _, err := runner.Run(ctx, gitDir, "merge-base", "--is-ancestor", head, a.Commit); err != nil {
+				return errs.New(errs.Conflict, "branch '%s' has diverged from the archive", a.Branch).
+					WithHint("Rename or delete the branch, or check out the archived commit with: git worktree add <path> %s", a.Ref)
+			}
+			fastForward = true
+		}
+		args = []string{"worktree", "add", worktreePath, a.Branch}
+	}
+	if err := os.Chdir(gitDir); err != nil {
+		return fmt.Errorf("changing to git root: %w", err)
+	}
+	if err := runGitCommand(args...); err != nil {
+		return fmt.Errorf("creating worktree: %w", err)
+	}
+	if fastForward {
+		if _, err := runner.Run(ctx, worktreePath, "merge", "--ff-only", a.Commit); err != nil {
+			return fmt.Errorf("fast-forwarding '%s' to the archived commit: %w", a.Branch, err)
+		}
+	}
+	printer.Printf("Restored worktree for branch '%s' at %s\n", a.Branch, shortSHA(a.Commit))
+
+	if err := a.Extract(worktreePath); err != nil {
+		return fmt.Errorf("restoring files: %w", err)
+	}
+	for _, f := range a.Files {
+		printer.Printf("  Restored %s\n", f)
+	}
+
+	absPath, _ := filepath.Abs(worktreePath)
+	restoreWorktreeMeta(a, absPath)
+	if err := archive.Delete(ctx, runner, a); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not delete archive %s: %v\n", a.ID, err)
+	}
+
+	if err := os.Chdir(worktreePath); err != nil {
+		return fmt.Errorf("changing to worktree: %w", err)
+	}
+	printer.Printf("Path: %s\n", absPath)
+
+	runPostCheckoutActions(worktreePath)
+
+	return render(RestoreResult{
+		Archive: a.ID,
+		Repo:    ws.KeyForPath(a.Container),
+		Branch:  a.Branch,
+		Path:    absPath,
+		Commit:  a.Commit,
+		Files:   a.Files,
+	})
+}
+
+// restoreWorktreeMeta puts back the archived worktree record at its new path, or
+// records the worktree as new when it had none.
+func restoreWorktreeMeta(a *archive.Archive, path string) {
+	if a.Metadata == nil {
+		recordWorktree(a.Container, path, a.Branch, "", a.Ref, true)
+		return
+	}
+	meta := *a.Metadata
+	meta.Path = path
+	meta.LastOpened = time.Now()
+	if err := cache.SaveWorktreeMeta(meta); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not record worktree metadata: %v\n", err)
+	}
+}
+
+// listArchives prints the archives 'cleanup restore' can restore.
+func listArchives() error {
+	archives, err := archive.List()
+	if err != nil {
+		return fmt.Errorf("listing archives: %w", err)
+	}
+	if !printer.IsText() {
+		if archives == nil {
+			archives = []archive.Archive{}
+		}
+		return render(ArchiveListReport{Archives: archives})
+	}
+
+	if len(archives) == 0 {
+		fmt.Println("No archived worktrees. 'work cleanup run --archive' creates them.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tREPO\tBRANCH\tUNPUSHED\tFILES\tARCHIVED")
+	for _, a := range archives {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\n", a.ID, a.Repo, a.Branch, a.Unpushed, len(a.Files), formatAge(a.CreatedAt))
+	}
+	return tw.Flush()
+}
+
+func init() {
+	cleanupCmd.AddCommand(cleanupRestoreCmd)
+}
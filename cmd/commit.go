@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/tracker"
 )
 
 var commitCmd = &cobra.Command{
@@ -21,29 +25,27 @@ This command performs the following steps:
 2. git commit -m "<message>"
 3. git pull --rebase
 4. git push (with -u if needed)
-5. Create a GitHub pull request using gh CLI
+5. Create a pull request (GitHub, Gitea/Forgejo) or merge request (GitLab) via the forge API
 
 Examples:
   work commit "Add new feature"
   work commit "Fix bug in authentication"`,
 	Args: cobra.ExactArgs(1),
-	Run:  runCommit,
+	RunE: runCommit,
 }
 
-func runCommit(cmd *cobra.Command, args []string) {
+func runCommit(cmd *cobra.Command, args []string) error {
 	commitMessage := args[0]
 
 	// Check if we're in a git repository
 	if !isInsideGitRepo() {
-		fmt.Fprintf(os.Stderr, "Error: Not in a git repository\n")
-		os.Exit(1)
+		return errs.New(errs.RepoNotFound, "not in a git repository")
 	}
 
 	// Get current branch name
 	currentBranch := getCurrentBranch(".")
 	if currentBranch == "" {
-		fmt.Fprintf(os.Stderr, "Error: Could not determine current branch\n")
-		os.Exit(1)
+		return errs.New(errs.Failure, "could not determine current branch")
 	}
 
 	// Step 1: git add .
@@ -52,8 +54,7 @@ func runCommit(cmd *cobra.Command, args []string) {
 	addCmd.Stdout = os.Stdout
 	addCmd.Stderr = os.Stderr
 	if err := addCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: git add failed: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("git add failed: %w", err)
 	}
 
 	// Step 2: git commit
@@ -62,8 +63,7 @@ func runCommit(cmd *cobra.Command, args []string) {
 	commitCmd.Stdout = os.Stdout
 	commitCmd.Stderr = os.Stderr
 	if err := commitCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: git commit failed: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("git commit failed: %w", err)
 	}
 
 	// Step 3: git pull --rebase
@@ -72,28 +72,28 @@ func runCommit(cmd *cobra.Command, args []string) {
 	pullCmd.Stdout = os.Stdout
 	pullCmd.Stderr = os.Stderr
 	if err := pullCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: git pull --rebase failed: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Please resolve conflicts and push manually\n")
-		os.Exit(1)
+		return errs.Wrap(errs.Conflict, err, "git pull --rebase failed").
+			WithHint("Please resolve conflicts and push manually")
 	}
 
 	// Step 4: git push (with retry logic)
 	fmt.Println("Pushing to remote...")
 	if err := pushWithRetry(currentBranch); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: git push failed: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("git push failed: %w", err)
 	}
 
-	// Step 5: Create pull request using gh CLI
+	// Step 5: Create pull request
 	fmt.Println("\nCreating pull request...")
 	if err := createPullRequest(currentBranch, commitMessage); err != nil {
 		fmt.Fprintf(os.Stderr, "\nWarning: Could not create PR: %v\n", err)
-		if strings.Contains(err.Error(), "executable file not found") || strings.Contains(err.Error(), "command not found") {
-			fmt.Fprintf(os.Stderr, "The 'gh' CLI is not installed. Install it from: https://cli.github.com/\n")
+		if errors.Is(err, forge.ErrUnauthorized) {
+			fmt.Fprintf(os.Stderr, "The forge rejected the credentials. Set GH_TOKEN (GitHub), GITLAB_TOKEN (GitLab) or GITEA_TOKEN (Gitea/Forgejo), or run: gh auth login\n")
+		}
+		if url := manualPullURL(currentBranch); url != "" {
+			fmt.Fprintf(os.Stderr, "You can create the PR manually at: %s\n", url)
 		}
-		fmt.Fprintf(os.Stderr, "You can create the PR manually at: https://github.com/compare/%s\n", currentBranch)
-		return
 	}
+	return nil
 }
 
 // pushWithRetry attempts to push with exponential backoff retry logic
@@ -138,7 +138,7 @@ func pushWithRetry(branch string) error {
 	return fmt.Errorf("push failed after retries")
 }
 
-// createPullRequest creates a pull request using gh CLI
+// createPullRequest creates a pull request (merge request on GitLab) using the forge API
 func createPullRequest(branch string, commitMessage string) error {
 	// Get default branch for comparison
 	defaultBranch := getDefaultBranch(".")
@@ -161,28 +161,60 @@ func createPullRequest(branch string, commitMessage string) error {
 
 	// Create PR body with summary of commits
 	prBody := fmt.Sprintf("## Summary\n\n%s\n\n## Commits\n```\n%s\n```", commitMessage, commits)
-
-	// Write PR body to a temporary file (cross-platform approach)
-	tempDir := os.TempDir()
-	bodyFile := filepath.Join(tempDir, fmt.Sprintf("pr-body-%d.txt", os.Getpid()))
-	if err := os.WriteFile(bodyFile, []byte(prBody), 0644); err != nil {
-		return fmt.Errorf("failed to write PR body file: %w", err)
+	if issue := issueReference(branch); issue != "" {
+		prBody += "\n\n## Issue\n\n" + issue
 	}
-	defer os.Remove(bodyFile)
 
-	// Create the PR using gh CLI with body from file
-	prCmd := exec.Command("gh", "pr", "create", "--title", prTitle, "--body-file", bodyFile)
-	prCmd.Stdout = os.Stdout
-	prCmd.Stderr = os.Stderr
-	prCmd.Stdin = os.Stdin
+	client, origin, err := getOriginForge(".")
+	if err != nil {
+		return err
+	}
 
-	if err := prCmd.Run(); err != nil {
-		return fmt.Errorf("gh pr create failed: %w", err)
+	// Create the PR (or GitLab merge request) through the forge API
+	pr, err := client.CreatePR(context.Background(), origin.Owner(), origin.Name(), forge.NewPullRequest{
+		Title: prTitle,
+		Body:  prBody,
+		Head:  branch,
+		Base:  defaultBranch,
+	})
+	if err != nil {
+		return fmt.Errorf("creating pull request failed: %w", err)
 	}
 
+	fmt.Printf("Created pull request #%d: %s\n", pr.Number, pr.URL)
 	return nil
 }
 
+// manualPullURL returns the origin forge page for opening a pull request of branch
+// by hand, or "" if the forge of origin is not known.
+func manualPullURL(branch string) string {
+	client, origin, err := getOriginForge(".")
+	if err != nil {
+		return ""
+	}
+	return forge.NewPullURL(client.Name(), origin.Host, origin.Owner(), origin.Name(), branch, getDefaultBranch("."))
+}
+
+// issueReference returns the PR body line for the issue or ticket the branch was
+// checked out for, or "" if there is none.
+func issueReference(branch string) string {
+	ws := worktreeManager()
+	containerRoot, err := ws.FindContainerFromCwd()
+	if err != nil {
+		return ""
+	}
+	key, url := ws.Issue(context.Background(), containerRoot, branch)
+	switch {
+	case key == "" || url == "":
+		return ""
+	case tracker.Ref{Key: key}.IsTicket():
+		return fmt.Sprintf("[%s](%s)", key, url)
+	default:
+		// Forge issues close when the pull request merges
+		return "Closes " + url
+	}
+}
+
 func init() {
 	// Register commit command with root
 	rootCmd.AddCommand(commitCmd)
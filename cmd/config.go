@@ -3,11 +3,16 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/policy"
+	"github.com/velvee-ai/ai-workflow/pkg/tracker"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
 )
 
 var configCmd = &cobra.Command{
@@ -20,11 +25,14 @@ var configListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configuration settings",
 	Long:  `Display all current configuration settings and their values.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Get()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("reading config: %w", err)
+		}
+
+		if !printer.IsText() {
+			return render(cfg)
 		}
 
 		fmt.Println("Current configuration:")
@@ -34,6 +42,7 @@ var configListCmd = &cobra.Command{
 		} else {
 			fmt.Printf("  preferred_orgs: []\n")
 		}
+		return nil
 	},
 }
 
@@ -42,16 +51,16 @@ var configGetCmd = &cobra.Command{
 	Short: "Get a configuration value",
 	Long:  `Get the value of a specific configuration setting.`,
 	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
 		value := config.GetString(key)
 
 		if value == "" {
-			fmt.Fprintf(os.Stderr, "Configuration key '%s' not found or is empty\n", key)
-			os.Exit(1)
+			return errs.New(errs.NotConfigured, "configuration key '%s' not found or is empty", key)
 		}
 
 		fmt.Printf("%s: %s\n", key, value)
+		return nil
 	},
 }
 
@@ -61,9 +70,9 @@ var configSetCmd = &cobra.Command{
 	Long: `Set the value of a specific configuration setting.
 
 For array values, use JSON format:
-  work config set preferred_orgs '["org1","org2"]'`,
+  work config set preferred_orgs '["org1","gitlab.example.com/group"]'`,
 	Args: cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
 		value := args[1]
 
@@ -72,18 +81,65 @@ For array values, use JSON format:
 		if strings.HasPrefix(strings.TrimSpace(value), "[") {
 			var arr []string
 			if err := json.Unmarshal([]byte(value), &arr); err != nil {
-				fmt.Fprintf(os.Stderr, "Error parsing JSON array: %v\n", err)
-				os.Exit(1)
+				return errs.Wrap(errs.Usage, err, "parsing JSON array")
 			}
 			configValue = arr
 		}
 
+		switch key {
+		case "worktree_dir_template":
+			if err := workspace.ValidateDirTemplate(value); err != nil {
+				return errs.Wrap(errs.Usage, err, "").
+					WithHint(`Use text/template syntax, e.g. '{{.Branch | slug}}'`)
+			}
+		case "issue_branch_template":
+			if err := tracker.ValidateBranchTemplate(value); err != nil {
+				return errs.Wrap(errs.Usage, err, "").
+					WithHint(`Use text/template syntax, e.g. '{{.Number}}-{{.Title | slug | lower}}'`)
+			}
+		case "cleanup.inactive_for":
+			if _, err := policy.ParseDuration(value); err != nil {
+				return errs.Wrap(errs.Usage, err, "")
+			}
+		case "cleanup.min_free_disk":
+			if _, err := policy.ParseSize(value); err != nil {
+				return errs.Wrap(errs.Usage, err, "")
+			}
+		case "cleanup.max_worktrees_per_repo":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return errs.New(errs.Usage, "cleanup.max_worktrees_per_repo must be a number, 0 to disable")
+			}
+			configValue = n
+		case "cleanup.pr_closed":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return errs.New(errs.Usage, "cleanup.pr_closed must be true or false")
+			}
+			configValue = enabled
+		case "max_parallel":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return errs.New(errs.Usage, "max_parallel must be a number of at least 1")
+			}
+			configValue = n
+		case "repo_timeout":
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return errs.New(errs.Usage, "repo_timeout must be a duration like 2m, 0 for no limit")
+			}
+		case "container_layout":
+			if value != config.ContainerLayoutMain && value != config.ContainerLayoutBare {
+				return errs.New(errs.Usage, "unknown container_layout '%s'", value).
+					WithHint("Use main or bare; convert existing repositories with: work migrate-layout")
+			}
+		}
+
 		if err := config.Set(key, configValue); err != nil {
-			fmt.Fprintf(os.Stderr, "Error setting config: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("setting config: %w", err)
 		}
 
 		fmt.Printf("Successfully set %s = %v\n", key, configValue)
+		return nil
 	},
 }
 
@@ -91,14 +147,14 @@ var configPathCmd = &cobra.Command{
 	Use:   "path",
 	Short: "Show configuration file path",
 	Long:  `Display the path to the configuration file.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		path, err := config.GetConfigFilePath()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting config path: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("getting config path: %w", err)
 		}
 
 		fmt.Printf("Configuration file: %s\n", path)
+		return nil
 	},
 }
 
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/giturl"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+)
+
+// configuredOrgs returns all orgs to search, in order: preferred_orgs entries
+// (bare names refer to github.com) followed by the orgs listed under each configured forge.
+func configuredOrgs() []forge.OrgRef {
+	var targets []forge.OrgRef
+	seen := make(map[forge.OrgRef]bool)
+	add := func(ref forge.OrgRef) {
+		if !seen[ref] {
+			seen[ref] = true
+			targets = append(targets, ref)
+		}
+	}
+
+	for _, entry := range config.GetStringSlice("preferred_orgs") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		ref, err := forge.ParseOrgRef(entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Ignoring preferred_orgs entry: %v\n", err)
+			continue
+		}
+		add(ref)
+	}
+
+	if cfg, err := config.Get(); err == nil {
+		for _, fc := range cfg.Forges {
+			for _, org := range fc.Orgs {
+				if org != "" {
+					add(forge.OrgRef{Host: fc.Host, Owner: org})
+				}
+			}
+		}
+	}
+
+	return targets
+}
+
+// forgeForHost returns the forge client configured for host.
+func forgeForHost(host string) (forge.Forge, error) {
+	return services.Get().Forges.ForHost(host)
+}
+
+// getOriginForge returns the forge client and parsed origin URL for the repository in workDir.
+func getOriginForge(workDir string) (forge.Forge, *giturl.ParsedURL, error) {
+	origin, err := getOriginRepo(workDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := forgeForHost(origin.Host)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, origin, nil
+}
+
+// findRemoteRepo searches the configured orgs for a repository by name and returns
+// the first match along with the forge client that hosts it.
+func findRemoteRepo(repoName string) (forge.Forge, *forge.Repo) {
+	ctx := context.Background()
+
+	for _, target := range configuredOrgs() {
+		client, err := forgeForHost(target.Host)
+		if err != nil {
+			continue
+		}
+
+		repo, err := client.GetRepo(ctx, target.Owner, repoName)
+		if err != nil {
+			// Try next org if this fails
+			continue
+		}
+
+		if repo.Owner == "" {
+			repo.Owner = target.Owner
+		}
+		return client, repo
+	}
+
+	return nil, nil
+}
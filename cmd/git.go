@@ -9,7 +9,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
 	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+	"github.com/velvee-ai/ai-workflow/pkg/giturl"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
 )
 
 var gitCmd = &cobra.Command{
@@ -22,11 +25,11 @@ var gitStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show git status",
 	Long:  `Execute git status and display repository status.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := runGitCommand("status"); err != nil {
-			fmt.Fprintf(os.Stderr, "Error running git status: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("running git status: %w", err)
 		}
+		return nil
 	},
 }
 
@@ -34,27 +37,36 @@ var gitBranchCmd = &cobra.Command{
 	Use:   "branch",
 	Short: "List git branches",
 	Long:  `List all git branches in the repository.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := runGitCommand("branch", "-a"); err != nil {
-			fmt.Fprintf(os.Stderr, "Error listing branches: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("listing branches: %w", err)
 		}
+		return nil
 	},
 }
 
 // runGitCommand executes a git command with the given arguments
 func runGitCommand(args ...string) error {
 	gitCmd := exec.Command("git", args...)
-	gitCmd.Stdout = os.Stdout
+	gitCmd.Stdout = commandOutput()
 	gitCmd.Stderr = os.Stderr
 	gitCmd.Stdin = os.Stdin
 	return gitCmd.Run()
 }
 
+// shortSHA abbreviates a commit SHA for display. SHAs shorter than seven
+// characters, e.g. from a hand-edited archive manifest, are returned as is.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 // getDefaultBranch returns the repository's default branch name.
-// It attempts to detect it using gh CLI, falling back to config, then "main".
+// It attempts to detect it from the origin remote, falling back to config, then "main".
 func getDefaultBranch(workDir string) string {
-	// Try to detect using gitexec package with gh CLI
+	// Try to detect using gitexec package from origin/HEAD
 	runner := gitexec.New(5 * time.Second)
 	ctx := context.Background()
 
@@ -71,19 +83,11 @@ func getDefaultBranch(workDir string) string {
 	return "main"
 }
 
-// getRemoteDefaultBranch queries a specific remote repository's default branch using GitHub API.
+// getRemoteDefaultBranch returns the default branch reported by the forge for a remote repository.
 // This is useful when you need to get the default branch before cloning the repository.
-func getRemoteDefaultBranch(owner, repo string) string {
-	// Try to query the specific repository using GitHub API
-	cmd := exec.Command("gh", "api", fmt.Sprintf("repos/%s/%s", owner, repo), "--jq", ".default_branch")
-	output, err := cmd.Output()
-	if err == nil {
-		branch := string(output)
-		// Trim whitespace and newlines
-		branch = trimSpace(branch)
-		if branch != "" {
-			return branch
-		}
+func getRemoteDefaultBranch(repo *forge.Repo) string {
+	if repo != nil && repo.DefaultBranch != "" {
+		return repo.DefaultBranch
 	}
 
 	// Fall back to configured checkout_base_branch
@@ -95,16 +99,14 @@ func getRemoteDefaultBranch(owner, repo string) string {
 	return "main"
 }
 
-// trimSpace removes leading and trailing whitespace and newlines
-func trimSpace(s string) string {
-	// Remove common whitespace characters
-	result := ""
-	for _, c := range s {
-		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
-			result += string(c)
-		}
+// getOriginRepo parses the origin remote URL of the repository in workDir.
+func getOriginRepo(workDir string) (*giturl.ParsedURL, error) {
+	runner := services.Get().GitRunner
+	originURL, err := runner.RunSimple(context.Background(), workDir, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("could not read origin remote: %w", err)
 	}
-	return result
+	return giturl.Parse(originURL)
 }
 
 func init() {
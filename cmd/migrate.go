@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+var migrateLayoutCmd = &cobra.Command{
+	Use:   "migrate-layout [repo...]",
+	Short: "Convert repositories between the main and bare container layouts",
+	Long: `Convert repository containers to the configured container_layout (or --to).
+
+main: the repository is a full clone in <repo>/main and branches are worktrees next to it.
+bare: the repository lives in <repo>/.bare and every branch, the default one included,
+      is a worktree.
+
+Converting to bare keeps main/ in place as the worktree of the branch it has checked out.
+Converting to main moves the default branch worktree to main/. Worktrees of other branches
+stay where they are. The working copy that is moved must have no uncommitted changes to
+tracked files; untracked and ignored files are kept.
+
+Examples:
+  work config set container_layout bare
+  work migrate-layout                 # Convert every repository
+  work migrate-layout api --dry-run   # Show what would change
+  work migrate-layout api --to main   # Convert back`,
+	ValidArgsFunction: completeReposForSync,
+	RunE:              runMigrateLayout,
+}
+
+var (
+	migrateTo     string
+	migrateDryRun bool
+)
+
+func init() {
+	rootCmd.AddCommand(migrateLayoutCmd)
+	migrateLayoutCmd.Flags().StringVar(&migrateTo, "to", "", "Target layout: main or bare (default: container_layout)")
+	migrateLayoutCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show what would be converted without changing anything")
+}
+
+// MigrateResult is the outcome of converting one repository.
+type MigrateResult struct {
+	Repo   string `json:"repo"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Status string `json:"status"` // "converted", "unchanged", "pending" (dry run) or "failed"
+	Error  string `json:"error,omitempty"`
+
+	err error
+}
+
+// MigrateReport is the structured result of 'work migrate-layout'.
+type MigrateReport struct {
+	Repositories []MigrateResult `json:"repositories"`
+	Converted    int             `json:"converted"`
+	Failed       int             `json:"failed"`
+}
+
+func runMigrateLayout(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	target := migrateTo
+	if target == "" {
+		target = services.Get().Config.ContainerLayout
+	}
+	if target == "" {
+		target = config.ContainerLayoutMain
+	}
+	if target != config.ContainerLayoutMain && target != config.ContainerLayoutBare {
+		return errs.New(errs.Usage, "unknown layout '%s'", target).
+			WithHint("Use --to main or --to bare")
+	}
+
+	repos, err := migrateTargets(args)
+	if err != nil {
+		return err
+	}
+
+	report := MigrateReport{Repositories: []MigrateResult{}}
+	for _, repoPath := range repos {
+		result := migrateRepository(ctx, repoPath, target)
+		report.Repositories = append(report.Repositories, result)
+
+		switch result.Status {
+		case "converted":
+			report.Converted++
+			printer.Printf("✓ %s: %s → %s\n", result.Repo, result.From, result.To)
+		case "pending":
+			printer.Printf("• %s: would convert %s → %s\n", result.Repo, result.From, result.To)
+		case "unchanged":
+			printer.Printf("  %s: already %s\n", result.Repo, result.To)
+		case "failed":
+			report.Failed++
+			if printer.IsText() {
+				fmt.Fprintf(os.Stderr, "✗ %s: %s\n", result.Repo, result.Error)
+			}
+		}
+	}
+
+	if err := render(report); err != nil {
+		return err
+	}
+
+	var failed []MigrateResult
+	for _, r := range report.Repositories {
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return errs.Reported(errs.KindOf(failed[0].err), "%s could not be converted", failed[0].Repo)
+	default:
+		return errs.Reported(errs.Failure, "%d repositories could not be converted", len(failed))
+	}
+}
+
+// migrateTargets returns the containers named by args, or all of them.
+func migrateTargets(args []string) ([]string, error) {
+	if len(args) == 0 {
+		repos, err := discoverRepos()
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(repos)
+		return repos, nil
+	}
+
+	if _, err := getGitFolder(); err != nil {
+		return nil, err
+	}
+	var repos []string
+	for _, arg := range args {
+		container, ok := worktreeManager().Resolve(arg)
+		if !ok {
+			return nil, errs.New(errs.RepoNotFound, "repository '%s' not found", arg)
+		}
+		repos = append(repos, container)
+	}
+	return repos, nil
+}
+
+// migrateRepository converts one container to the target layout.
+func migrateRepository(ctx context.Context, repoPath, target string) MigrateResult {
+	result := MigrateResult{
+		Repo: repoDisplayName(repoPath),
+		From: config.ContainerLayoutMain,
+		To:   target,
+	}
+	if workspace.IsBare(repoPath) {
+		result.From = config.ContainerLayoutBare
+	}
+
+	switch {
+	case result.From == target:
+		result.Status = "unchanged"
+		return result
+	case migrateDryRun:
+		result.Status = "pending"
+		return result
+	}
+
+	ws := worktreeManager()
+	if target == config.ContainerLayoutBare {
+		result.err = ws.ConvertToBare(ctx, repoPath)
+	} else {
+		result.err = ws.ConvertToMain(ctx, repoPath)
+	}
+	if result.err != nil {
+		result.Status = "failed"
+		result.Error = result.err.Error()
+		return result
+	}
+	result.Status = "converted"
+	return result
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/velvee-ai/ai-workflow/pkg/parallel"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+)
+
+// progressMaxFailures is how many failed repositories the progress view lists
+const progressMaxFailures = 5
+
+// poolOptions returns the worker pool settings in the config: max_parallel
+// repositories at once (parallel.DefaultLimit when unset), each limited to
+// repo_timeout.
+func poolOptions() parallel.Options {
+	cfg := services.Get().Config
+	opts := parallel.Options{Limit: cfg.MaxParallel}
+	if opts.Limit <= 0 {
+		opts.Limit = parallel.DefaultLimit
+	}
+	if d, err := time.ParseDuration(cfg.RepoTimeout); err == nil {
+		opts.Timeout = d
+	}
+	return opts
+}
+
+// forEachRepo calls fn for each repository container on the worker pool and returns
+// the results in the order of repos. With text output and stderr on a terminal, a
+// live view titled e.g. "Syncing" shows which repositories are in flight, done or
+// failed; an empty title turns it off. Interrupting the view cancels the repositories
+// not yet done.
+func forEachRepo[R any](ctx context.Context, title string, repos []string, fn func(context.Context, string) (R, error)) ([]R, []error) {
+	opts := poolOptions()
+	if title == "" || !progressEnabled() {
+		return parallel.Map(ctx, repos, opts, fn)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	names := make([]string, len(repos))
+	for i, repoPath := range repos {
+		names[i] = repoDisplayName(repoPath)
+	}
+	p := tea.NewProgram(newProgressModel(title, names), tea.WithOutput(os.Stderr), tea.WithInput(nil))
+	opts.Progress = func(e parallel.Event) { p.Send(progressEventMsg(e)) }
+
+	var results []R
+	var errs []error
+	done := make(chan struct{})
+	go func() {
+		results, errs = parallel.Map(ctx, repos, opts, fn)
+		close(done)
+		p.Send(progressDoneMsg{})
+	}()
+
+	p.Run()
+	select {
+	case <-done:
+	default:
+		cancel() // Interrupted or terminated
+		<-done
+	}
+	return results, errs
+}
+
+// progressEnabled reports whether the progress view can be shown: text output
+// with stderr on a terminal.
+func progressEnabled() bool {
+	if !printer.IsText() {
+		return false
+	}
+	stat, err := os.Stderr.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// progressEventMsg reports a repository starting or finishing
+type progressEventMsg parallel.Event
+
+// progressDoneMsg is sent when all repositories are done
+type progressDoneMsg struct{}
+
+type progressModel struct {
+	title    string
+	names    []string
+	states   []parallel.State
+	started  []time.Time
+	failures []string // Failed repositories with their errors, in order of failure
+	finished int
+	done     bool
+	spinner  spinner.Model
+}
+
+func newProgressModel(title string, names []string) progressModel {
+	sp := spinner.New()
+	sp.Spinner = spinner.MiniDot
+	return progressModel{
+		title:   title,
+		names:   names,
+		states:  make([]parallel.State, len(names)),
+		started: make([]time.Time, len(names)),
+		spinner: sp,
+	}
+}
+
+func (m progressModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case progressEventMsg:
+		m.states[msg.Index] = msg.State
+		switch msg.State {
+		case parallel.Running:
+			m.started[msg.Index] = time.Now()
+		case parallel.Failed:
+			line, _, _ := strings.Cut(msg.Err.Error(), "\n")
+			m.failures = append(m.failures, m.names[msg.Index]+": "+line)
+			m.finished++
+		case parallel.Done:
+			m.finished++
+		}
+		return m, nil
+
+	case progressDoneMsg:
+		m.done = true
+		return m, tea.Quit
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// View lists the repositories in flight and the failures so far. It is empty once
+// done, so the command's own output replaces it.
+func (m progressModel) View() string {
+	if m.done {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d/%d repositories", m.title, m.finished, len(m.names))
+	if len(m.failures) > 0 {
+		fmt.Fprintf(&b, ", %s", uiErrorStyle.Render(fmt.Sprintf("%d failed", len(m.failures))))
+	}
+	b.WriteString("\n")
+
+	for i, state := range m.states {
+		if state == parallel.Running {
+			elapsed := time.Since(m.started[i]).Truncate(time.Second)
+			fmt.Fprintf(&b, "  %s %s %s\n", m.spinner.View(), m.names[i], uiMutedStyle.Render(elapsed.String()))
+		}
+	}
+
+	failures := m.failures
+	if len(failures) > progressMaxFailures {
+		failures = failures[len(failures)-progressMaxFailures:]
+	}
+	for _, f := range failures {
+		b.WriteString(uiErrorStyle.Render("  ✗ "+f) + "\n")
+	}
+	return b.String()
+}
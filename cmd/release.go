@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,8 +12,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
 	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
 )
 
 var releaseCmd = &cobra.Command{
@@ -36,7 +36,7 @@ Examples:
 `,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: completeGitRepos,
-	Run:               runRelease,
+	RunE:              runRelease,
 }
 
 var (
@@ -50,7 +50,16 @@ func init() {
 	releaseCmd.Flags().BoolVar(&minorRelease, "minor", false, "Increment minor version")
 }
 
-func runRelease(cmd *cobra.Command, args []string) {
+// ReleaseResult is the structured result of 'work release'.
+type ReleaseResult struct {
+	Repo            string `json:"repo"`
+	DefaultBranch   string `json:"default_branch"`
+	PreviousVersion string `json:"previous_version"` // Empty for the first release
+	Version         string `json:"version"`
+	Pushed          bool   `json:"pushed"`
+}
+
+func runRelease(cmd *cobra.Command, args []string) error {
 	repoName := args[0]
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
@@ -59,139 +68,146 @@ func runRelease(cmd *cobra.Command, args []string) {
 	// Get the repository directory
 	workDir, err := getRepoWorkDir(repoName)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
 	// Ensure we're in a git repository
 	gitRunner := services.Get().GitRunner
 	if !gitRunner.IsInsideWorkTree(ctx, workDir) {
-		fmt.Fprintf(os.Stderr, "Error: %s is not a git repository\n", workDir)
-		os.Exit(1)
+		return errs.New(errs.RepoNotFound, "%s is not a git repository", workDir)
 	}
 
-	fmt.Printf("📦 Preparing release for %s\n\n", repoName)
+	printer.Printf("📦 Preparing release for %s\n\n", repoName)
 
 	// Step 1: Get the default branch
-	fmt.Println("1️⃣  Getting default branch...")
+	printer.Println("1️⃣  Getting default branch...")
 	defaultBranch, err := gitRunner.GetDefaultBranch(ctx, workDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting default branch: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("getting default branch: %w", err)
 	}
-	fmt.Printf("   Default branch: %s\n\n", defaultBranch)
+	printer.Printf("   Default branch: %s\n\n", defaultBranch)
 
 	// Step 2: Switch to default branch if not already on it
 	currentBranch, err := gitRunner.GetCurrentBranch(ctx, workDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting current branch: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("getting current branch: %w", err)
 	}
 
 	if currentBranch != defaultBranch {
-		fmt.Printf("2️⃣  Switching to %s branch...\n", defaultBranch)
+		printer.Printf("2️⃣  Switching to %s branch...\n", defaultBranch)
 		checkoutCmd := exec.CommandContext(ctx, "git", "checkout", defaultBranch)
 		checkoutCmd.Dir = workDir
-		checkoutCmd.Stdout = os.Stdout
+		checkoutCmd.Stdout = commandOutput()
 		checkoutCmd.Stderr = os.Stderr
 		if err := checkoutCmd.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error checking out %s: %v\n", defaultBranch, err)
-			os.Exit(1)
+			return errs.Wrap(errs.Conflict, err, "checking out %s", defaultBranch)
 		}
-		fmt.Println()
+		printer.Println()
 	} else {
-		fmt.Printf("2️⃣  Already on %s branch\n\n", defaultBranch)
+		printer.Printf("2️⃣  Already on %s branch\n\n", defaultBranch)
 	}
 
 	// Step 3: Pull latest changes
-	fmt.Println("3️⃣  Pulling latest changes...")
+	printer.Println("3️⃣  Pulling latest changes...")
 	pullCmd := exec.CommandContext(ctx, "git", "pull", "--rebase")
 	pullCmd.Dir = workDir
-	pullCmd.Stdout = os.Stdout
+	pullCmd.Stdout = commandOutput()
 	pullCmd.Stderr = os.Stderr
 	if err := pullCmd.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Could not pull latest changes: %v\n", err)
 	}
-	fmt.Println()
+	printer.Println()
 
 	// Step 4: Get the latest release
-	fmt.Println("4️⃣  Finding latest release...")
+	printer.Println("4️⃣  Finding latest release...")
 	latestVersion, err := getLatestRelease(ctx, workDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting latest release: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("getting latest release: %w", err)
 	}
 
+	previousVersion := latestVersion
 	if latestVersion == "" {
 		latestVersion = "v0.0.0"
-		fmt.Println("   No previous releases found, starting from v0.0.0")
+		printer.Println("   No previous releases found, starting from v0.0.0")
 	} else {
-		fmt.Printf("   Latest release: %s\n", latestVersion)
+		printer.Printf("   Latest release: %s\n", latestVersion)
 	}
-	fmt.Println()
+	printer.Println()
 
 	// Step 5: Increment version
-	fmt.Println("5️⃣  Incrementing version...")
+	printer.Println("5️⃣  Incrementing version...")
 	newVersion, err := incrementVersion(latestVersion, majorRelease, minorRelease)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error incrementing version: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("incrementing version: %w", err)
 	}
-	fmt.Printf("   New version: %s\n\n", newVersion)
+	printer.Printf("   New version: %s\n\n", newVersion)
 
 	// Step 6: Create and push tag
-	fmt.Printf("6️⃣  Creating and pushing tag %s...\n", newVersion)
+	printer.Printf("6️⃣  Creating and pushing tag %s...\n", newVersion)
 
 	// Create the tag
 	tagCmd := exec.CommandContext(ctx, "git", "tag", "-a", newVersion, "-m", fmt.Sprintf("Release %s", newVersion))
 	tagCmd.Dir = workDir
-	tagCmd.Stdout = os.Stdout
+	tagCmd.Stdout = commandOutput()
 	tagCmd.Stderr = os.Stderr
 	if err := tagCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating tag: %v\n", err)
-		os.Exit(1)
+		return errs.Wrap(errs.Conflict, err, "creating tag %s", newVersion)
 	}
-	fmt.Printf("   ✓ Tag %s created\n", newVersion)
+	printer.Printf("   ✓ Tag %s created\n", newVersion)
 
 	// Push the tag
 	pushCmd := exec.CommandContext(ctx, "git", "push", "origin", newVersion)
 	pushCmd.Dir = workDir
-	pushCmd.Stdout = os.Stdout
+	pushCmd.Stdout = commandOutput()
 	pushCmd.Stderr = os.Stderr
 	if err := pushCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error pushing tag: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Tag created locally but not pushed. You can push it manually with:\n")
-		fmt.Fprintf(os.Stderr, "  git push origin %s\n", newVersion)
-		os.Exit(1)
+		return errs.Wrap(errs.Failure, err, "pushing tag").
+			WithHint("Tag created locally but not pushed. You can push it manually with:\n  git push origin %s", newVersion)
 	}
-	fmt.Printf("   ✓ Tag %s pushed to remote\n\n", newVersion)
-
-	fmt.Printf("✅ Release %s created successfully!\n", newVersion)
-	fmt.Println("The release workflow should now be triggered automatically.")
+	printer.Printf("   ✓ Tag %s pushed to remote\n\n", newVersion)
+
+	printer.Printf("✅ Release %s created successfully!\n", newVersion)
+	printer.Println("The release workflow should now be triggered automatically.")
+
+	return render(ReleaseResult{
+		Repo:            repoName,
+		DefaultBranch:   defaultBranch,
+		PreviousVersion: previousVersion,
+		Version:         newVersion,
+		Pushed:          true,
+	})
 }
 
 // getRepoWorkDir returns the working directory for a repository
 func getRepoWorkDir(repoName string) (string, error) {
-	// Get git folder from config
-	gitFolder := config.GetString("default_git_folder")
-	if gitFolder == "" {
-		return "", fmt.Errorf("default_git_folder not configured. Run: work config set default_git_folder ~/git")
+	gitFolder, err := getGitFolder()
+	if err != nil {
+		return "", err
+	}
+
+	// Build paths
+	containerRoot := filepath.Join(gitFolder, repoName)
+	if located, ok := worktreeManager().Resolve(repoName); ok {
+		containerRoot = located
 	}
 
-	// Expand home directory if needed
-	if strings.HasPrefix(gitFolder, "~/") {
-		homeDir, err := os.UserHomeDir()
+	// Bare containers release from the worktree of the default branch
+	if workspace.IsBare(containerRoot) {
+		ctx := context.Background()
+		defaultBranch, err := services.Get().GitRunner.GetDefaultBranch(ctx, containerRoot)
+		if err != nil {
+			return "", fmt.Errorf("getting default branch: %w", err)
+		}
+		workDir, err := worktreeManager().DefaultWorktree(ctx, containerRoot, defaultBranch)
 		if err != nil {
-			return "", fmt.Errorf("could not get home directory: %w", err)
+			return "", errs.Wrap(errs.RepoNotFound, err, "").
+				WithHint("Run: work checkout %s %s", repoName, defaultBranch)
 		}
-		gitFolder = filepath.Join(homeDir, gitFolder[2:])
+		return workDir, nil
 	}
 
-	// Build paths
-	containerRoot := filepath.Join(gitFolder, repoName)
-
 	// First, try to find the main worktree directory (for worktree-based repos)
-	mainDir := filepath.Join(containerRoot, "main")
+	mainDir := workspace.MainPath(containerRoot)
 	if _, err := os.Stat(mainDir); err == nil {
 		return mainDir, nil
 	}
@@ -201,42 +217,27 @@ func getRepoWorkDir(repoName string) (string, error) {
 		return containerRoot, nil
 	}
 
-	return "", fmt.Errorf("repository directory not found for %s (tried: %s, %s)",
+	return "", errs.New(errs.RepoNotFound, "repository directory not found for %s (tried: %s, %s)",
 		repoName, mainDir, containerRoot)
 }
 
-// getLatestRelease queries GitHub for the latest release
+// getLatestRelease queries the forge hosting the origin remote for the latest release
 func getLatestRelease(ctx context.Context, workDir string) (string, error) {
-	cmd := exec.CommandContext(ctx, "gh", "repo", "view", "--json", "latestRelease")
-	cmd.Dir = workDir
-
-	output, err := cmd.Output()
+	client, origin, err := getOriginForge(workDir)
 	if err != nil {
-		// If gh command fails, it might mean no releases exist
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			stderr := string(exitErr.Stderr)
-			if strings.Contains(stderr, "no releases") || strings.Contains(stderr, "not found") {
-				return "", nil
-			}
-		}
-		return "", fmt.Errorf("failed to get latest release: %w", err)
-	}
-
-	var result struct {
-		LatestRelease *struct {
-			TagName string `json:"tagName"`
-		} `json:"latestRelease"`
+		return "", err
 	}
 
-	if err := json.Unmarshal(output, &result); err != nil {
-		return "", fmt.Errorf("failed to parse release data: %w", err)
+	release, err := client.GetLatestRelease(ctx, origin.Owner(), origin.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest release: %w", err)
 	}
 
-	if result.LatestRelease == nil {
+	if release == nil {
 		return "", nil
 	}
 
-	return result.LatestRelease.TagName, nil
+	return release.TagName, nil
 }
 
 // incrementVersion increments a semantic version string
@@ -1,24 +1,24 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
-	"strings"
 	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/velvee-ai/ai-workflow/pkg/cache"
-	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
 )
 
 var reloadCmd = &cobra.Command{
 	Use:   "reload",
-	Short: "Reload repository list from GitHub into cache",
-	Long: `Fetch repository names from GitHub and store them in the local cache.
+	Short: "Reload repository list from GitHub and other forges into cache",
+	Long: `Fetch repository names from GitHub (and configured GitLab/Gitea hosts) and store them in the local cache.
 
 This command:
-1. Fetches all repositories from your configured GitHub organizations
+1. Fetches all repositories from your configured GitHub organizations, GitLab groups and Gitea orgs
 2. Stores repository names in a local database for fast autocomplete
 
 Branches are fetched on-demand during tab completion from GitHub API.
@@ -29,29 +29,32 @@ Run this command:
 
 Example:
   work reload`,
-	Run: runReload,
+	RunE: runReload,
 }
 
 func init() {
 	rootCmd.AddCommand(reloadCmd)
 }
 
-func runReload(cmd *cobra.Command, args []string) {
+func runReload(cmd *cobra.Command, args []string) error {
+	if len(configuredOrgs()) == 0 {
+		return errs.New(errs.NotConfigured, "no preferred_orgs configured").
+			WithHint(`Run: work config set preferred_orgs '["org1","gitlab.example.com/group"]'`)
+	}
+
 	fmt.Println("Reloading repository cache from GitHub...")
 
 	// Fetch repositories
 	fmt.Println("\nFetching repositories...")
-	repos := fetchRepositoriesFromGitHub()
+	repos := fetchRepositoriesFromForges()
 	if len(repos) == 0 {
-		fmt.Fprintf(os.Stderr, "Warning: No repositories found\n")
-		fmt.Fprintf(os.Stderr, "Make sure your preferred_orgs are configured: work config set preferred_orgs '[\"org1\",\"org2\"]'\n")
-		os.Exit(1)
+		return errs.New(errs.RepoNotFound, "no repositories found").
+			WithHint("Make sure your preferred_orgs are correct and your forge credentials are set")
 	}
 
 	// Save repos to cache
 	if err := cache.SaveRepoCache(repos); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving repository cache: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("saving repository cache: %w", err)
 	}
 
 	fmt.Printf("✓ Cached %d repositories\n", len(repos))
@@ -59,57 +62,66 @@ func runReload(cmd *cobra.Command, args []string) {
 	// Show cache stats
 	fmt.Println("\nCache updated successfully!")
 	fmt.Printf("\nNote: Branches are fetched on-demand from GitHub during tab completion.\n")
+	return nil
 }
 
-// fetchRepositoriesFromGitHub fetches all repositories from configured GitHub organizations
-func fetchRepositoriesFromGitHub() []string {
-	preferredOrgs := config.GetStringSlice("preferred_orgs")
-	if len(preferredOrgs) == 0 {
+// fetchRepositoriesFromForges fetches all repositories from the configured organizations and groups
+func fetchRepositoriesFromForges() []cache.RepoEntry {
+	targets := configuredOrgs()
+	if len(targets) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: No preferred_orgs configured\n")
-		return []string{}
+		return []cache.RepoEntry{}
 	}
 
-	repoMap := make(map[string]bool)
-	var repos []string
+	ctx := context.Background()
+
+	// Repos are keyed by host and owner so same-named repos in different orgs are all kept
+	repoMap := make(map[cache.RepoEntry]bool)
+	var repos []cache.RepoEntry
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	for _, org := range preferredOrgs {
-		if org == "" {
+	for _, target := range targets {
+		client, err := forgeForHost(target.Host)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: Skipping %s: %v\n", target, err)
 			continue
 		}
 
 		wg.Add(1)
-		go func(organization string) {
+		go func(client forge.Forge, organization string) {
 			defer wg.Done()
 
-			fmt.Printf("  Fetching from %s...\n", organization)
+			fmt.Printf("  Fetching from %s (%s)...\n", organization, client.Host())
 
-			// Use gh CLI to list repos in the organization
-			cmd := exec.Command("gh", "repo", "list", organization, "--limit", "1000", "--json", "name", "-q", ".[].name")
-			output, err := cmd.Output()
+			remoteRepos, err := client.ListRepos(ctx, organization)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "  Warning: Failed to fetch repos from %s: %v\n", organization, err)
 				return
 			}
 
-			// Parse output
-			lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 			count := 0
 
 			mu.Lock()
-			for _, line := range lines {
-				repoName := strings.TrimSpace(line)
-				if repoName != "" && !repoMap[repoName] {
-					repoMap[repoName] = true
-					repos = append(repos, repoName)
+			for _, r := range remoteRepos {
+				if r.Name == "" {
+					continue
+				}
+				owner := r.Owner
+				if owner == "" {
+					owner = organization
+				}
+				entry := cache.RepoEntry{Host: client.Host(), Owner: owner, Name: r.Name}
+				if !repoMap[entry] {
+					repoMap[entry] = true
+					repos = append(repos, entry)
 					count++
 				}
 			}
 			mu.Unlock()
 
 			fmt.Printf("  ✓ Found %d repos in %s\n", count, organization)
-		}(org)
+		}(client, target.Owner)
 	}
 
 	wg.Wait()
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/velvee-ai/ai-workflow/pkg/cache"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+// worktreeManager returns the workspace that maps repositories to container folders.
+func worktreeManager() *workspace.Workspace {
+	return services.Get().WorktreeManager
+}
+
+// getGitFolder returns the expanded default_git_folder.
+func getGitFolder() (string, error) {
+	gitFolder, err := worktreeManager().Root()
+	if err != nil {
+		return "", errs.Wrap(errs.NotConfigured, err, "").
+			WithHint("Run: work config set default_git_folder ~/git")
+	}
+	return gitFolder, nil
+}
+
+// repoDisplayName returns the key of a container folder for use in output and filters.
+func repoDisplayName(containerPath string) string {
+	return worktreeManager().KeyForPath(containerPath)
+}
+
+// findRemoteRepoByKey resolves a repo argument against the forges. Qualified arguments
+// are looked up directly; bare names are searched for in the configured orgs.
+func findRemoteRepoByKey(arg string) (forge.Forge, *forge.Repo) {
+	host, owner, name := workspace.ParseRepoArg(arg)
+	if owner == "" {
+		return findRemoteRepo(name)
+	}
+
+	client, err := forgeForHost(host)
+	if err != nil {
+		return nil, nil
+	}
+	repo, err := client.GetRepo(context.Background(), owner, name)
+	if err != nil {
+		return nil, nil
+	}
+	if repo.Owner == "" {
+		repo.Owner = owner
+	}
+	if repo.Name == "" {
+		repo.Name = name
+	}
+	return client, repo
+}
+
+// repoCompletionNames returns the completion name of each cached repository. github.com
+// repos are shown by bare name unless another cached org has a repo of the same name,
+// in which case all of them are shown as owner/name. The second result holds the
+// ambiguous bare names.
+func repoCompletionNames(entries []cache.RepoEntry) ([]string, map[string]bool) {
+	ws := worktreeManager()
+
+	owners := make(map[string]map[string]bool)
+	for _, e := range entries {
+		if e.Host == forge.DefaultHost && e.Owner != "" {
+			if owners[e.Name] == nil {
+				owners[e.Name] = make(map[string]bool)
+			}
+			owners[e.Name][e.Owner] = true
+		}
+	}
+
+	ambiguous := make(map[string]bool)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Host == forge.DefaultHost && len(owners[e.Name]) > 1 {
+			ambiguous[e.Name] = true
+			names = append(names, workspace.QualifiedKey(e.Host, e.Owner, e.Name))
+			continue
+		}
+		names = append(names, ws.Key(e.Host, e.Owner, e.Name))
+	}
+	return names, ambiguous
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/output"
 	"github.com/velvee-ai/ai-workflow/pkg/services"
 )
 
@@ -16,10 +19,30 @@ var (
 	date    string
 )
 
+var (
+	// outputFormat is the value of the global --output flag
+	outputFormat string
+
+	// printer writes command results in the selected output format
+	printer = output.NewPrinter(output.Text, os.Stdout)
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "work",
 	Short: "Work - Git workflow and development tool",
 	Long:  `Work is a CLI tool for orchestrating git workflows, featuring powerful git worktree management for parallel branch development.`,
+	Args:  cobra.NoArgs,
+	// Errors are rendered once by Execute
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.ParseFormat(outputFormat)
+		if err != nil {
+			return errs.Wrap(errs.Usage, err, "invalid --output")
+		}
+		printer = output.NewPrinter(format, os.Stdout)
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Default behavior when no subcommand is provided
 		cmd.Help()
@@ -37,12 +60,88 @@ var versionCmd = &cobra.Command{
 	},
 }
 
-// Execute runs the root command
+// ErrorReport is the structured document written for a failed command.
+type ErrorReport struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a command failure for scripts.
+type ErrorDetail struct {
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// Execute runs the root command, renders any error and exits with its exit code
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	wrapArgsErrors(rootCmd)
+
+	err := rootCmd.Execute()
+	if err == nil {
+		return
 	}
+
+	renderError(err)
+	os.Exit(errs.ExitCode(err))
+}
+
+// renderError is the single place command errors are shown: as "Error: ..." plus
+// an optional hint on stderr, or as an ErrorReport on stdout for --output json/yaml.
+func renderError(err error) {
+	if errs.IsReported(err) {
+		return
+	}
+
+	if !printer.IsText() {
+		report := ErrorReport{Error: ErrorDetail{
+			Kind:     errs.KindOf(err).String(),
+			Message:  err.Error(),
+			Hint:     errs.HintOf(err),
+			ExitCode: errs.ExitCode(err),
+		}}
+		if printer.Render(report) == nil {
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if hint := errs.HintOf(err); hint != "" {
+		fmt.Fprintln(os.Stderr, hint)
+	}
+}
+
+// wrapArgsErrors marks positional argument errors (wrong count, unknown command)
+// as usage errors so they exit with errs.ExitUsage.
+func wrapArgsErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			if err := validate(c, args); err != nil {
+				return errs.Wrap(errs.Usage, err, "").WithHint("Run '%s --help' for usage.", c.CommandPath())
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		wrapArgsErrors(sub)
+	}
+}
+
+// render writes the structured result of a command when --output is json or yaml.
+func render(v any) error {
+	if err := printer.Render(v); err != nil {
+		return fmt.Errorf("rendering %s output: %w", printer.Format(), err)
+	}
+	return nil
+}
+
+// commandOutput returns where output of child processes (git, hooks) is sent:
+// stdout in text mode, stderr when stdout is reserved for a structured document.
+func commandOutput() io.Writer {
+	if printer.IsText() {
+		return os.Stdout
+	}
+	return os.Stderr
 }
 
 // SetVersionInfo sets the version information
@@ -59,8 +158,13 @@ func init() {
 	// Add version command
 	rootCmd.AddCommand(versionCmd)
 
-	// Global flags can be added here
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.work/config.yaml)")
+	// Flag parsing errors are usage errors (exit code 2)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return errs.Wrap(errs.Usage, err, "").WithHint("Run '%s --help' for usage.", cmd.CommandPath())
+	})
+
+	// Global flags
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json or yaml")
 }
 
 // initConfig initializes the configuration and services
@@ -5,12 +5,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
 )
 
 var setupCmd = &cobra.Command{
@@ -25,7 +28,7 @@ This will guide you through setting up:
 
 Example:
   work setup`,
-	Run: runSetup,
+	RunE: runSetup,
 }
 
 var doctorCmd = &cobra.Command{
@@ -42,10 +45,10 @@ This checks:
 
 Example:
   work doctor`,
-	Run: runDoctor,
+	RunE: runDoctor,
 }
 
-func runSetup(cmd *cobra.Command, args []string) {
+func runSetup(cmd *cobra.Command, args []string) error {
 	// Get current values
 	currentGitFolder := config.GetString("default_git_folder")
 	if currentGitFolder == "" {
@@ -94,8 +97,8 @@ func runSetup(cmd *cobra.Command, args []string) {
 
 		huh.NewGroup(
 			huh.NewInput().
-				Title("GitHub Organizations").
-				Description("Organizations to search for repositories (comma-separated)").
+				Title("Organizations").
+				Description("Organizations to search for repositories (comma-separated; prefix other hosts, e.g. gitlab.example.com/group)").
 				Placeholder(currentOrgsStr).
 				Value(&orgsInput),
 		),
@@ -114,10 +117,8 @@ func runSetup(cmd *cobra.Command, args []string) {
 	)
 
 	// Run the form
-	err := form.Run()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	if err := form.Run(); err != nil {
+		return err
 	}
 
 	// Process git folder
@@ -143,14 +144,12 @@ func runSetup(cmd *cobra.Command, args []string) {
 		)
 
 		if err := confirmForm.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
 
 		if createDir {
 			if err := os.MkdirAll(gitFolder, 0755); err != nil {
-				fmt.Fprintf(os.Stderr, "Error creating directory: %v\n", err)
-				os.Exit(1)
+				return fmt.Errorf("creating directory: %w", err)
 			}
 		}
 	}
@@ -193,23 +192,57 @@ func runSetup(cmd *cobra.Command, args []string) {
 	}
 	fmt.Printf("⌨️  Preferred IDE: %s\n", ide)
 	fmt.Println("\n💡 Tip: Run 'work doctor' to verify everything is working correctly.")
+	return nil
 }
 
-func runDoctor(cmd *cobra.Command, args []string) {
-	fmt.Println("🩺 Work CLI Health Check")
-	fmt.Println("========================")
+// Doctor check levels
+const (
+	checkOK   = "ok"
+	checkWarn = "warning"
+	checkFail = "failed"
+)
+
+// DoctorCheck is the result of a single health check.
+type DoctorCheck struct {
+	Name     string   `json:"name"`
+	Status   string   `json:"status"` // "ok", "warning" or "failed"
+	Message  string   `json:"message,omitempty"`
+	Details  []string `json:"details,omitempty"`
+	Critical bool     `json:"critical"`
+	order    int
+}
+
+// failed reports whether the check failed.
+func (c DoctorCheck) failed() bool {
+	return c.Status == checkFail
+}
 
-	// Run checks concurrently where possible
-	type checkResult struct {
-		name        string
-		status      string
-		details     []string
-		critical    bool
-		failed      bool
-		order       int
+// statusLine renders the status for text output, e.g. "✓ git version 2.43.0".
+func (c DoctorCheck) statusLine() string {
+	icon := "✓"
+	switch c.Status {
+	case checkWarn:
+		icon = "⚠"
+	case checkFail:
+		icon = "❌"
+	}
+	if c.Message == "" {
+		return icon
 	}
+	return icon + " " + c.Message
+}
+
+// DoctorReport is the structured result of 'work doctor'.
+type DoctorReport struct {
+	Checks  []DoctorCheck `json:"checks"`
+	Healthy bool          `json:"healthy"` // All critical checks passed
+}
 
-	results := make(chan checkResult, 5)
+func runDoctor(cmd *cobra.Command, args []string) error {
+	printer.Println("🩺 Work CLI Health Check")
+	printer.Println("========================")
+
+	results := make(chan DoctorCheck, 5)
 	var wg sync.WaitGroup
 
 	// Independent checks that can run in parallel
@@ -217,14 +250,13 @@ func runDoctor(cmd *cobra.Command, args []string) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		result := checkResult{name: "git", order: 1, critical: true}
+		result := DoctorCheck{Name: "git", order: 1, Critical: true}
 		if err := exec.Command("git", "--version").Run(); err != nil {
-			result.status = "❌ NOT FOUND"
-			result.details = []string{"Install git: https://git-scm.com/downloads"}
-			result.failed = true
+			result.Status, result.Message = checkFail, "NOT FOUND"
+			result.Details = []string{"Install git: https://git-scm.com/downloads"}
 		} else {
 			output, _ := exec.Command("git", "--version").Output()
-			result.status = fmt.Sprintf("✓ %s", strings.TrimSpace(string(output)))
+			result.Status, result.Message = checkOK, strings.TrimSpace(string(output))
 		}
 		results <- result
 	}()
@@ -233,12 +265,11 @@ func runDoctor(cmd *cobra.Command, args []string) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		result := checkResult{name: "default_git_folder", order: 3, critical: true}
+		result := DoctorCheck{Name: "default_git_folder", order: 3, Critical: true}
 		gitFolder := config.GetString("default_git_folder")
 		if gitFolder == "" {
-			result.status = "❌ NOT CONFIGURED"
-			result.details = []string{"Run: work setup"}
-			result.failed = true
+			result.Status, result.Message = checkFail, "NOT CONFIGURED"
+			result.Details = []string{"Run: work setup"}
 		} else {
 			// Expand home directory if needed
 			if strings.HasPrefix(gitFolder, "~/") {
@@ -247,21 +278,18 @@ func runDoctor(cmd *cobra.Command, args []string) {
 			}
 
 			if info, err := os.Stat(gitFolder); os.IsNotExist(err) {
-				result.status = fmt.Sprintf("❌ DOES NOT EXIST (%s)", gitFolder)
-				result.details = []string{"Run: work setup"}
-				result.failed = true
+				result.Status, result.Message = checkFail, fmt.Sprintf("DOES NOT EXIST (%s)", gitFolder)
+				result.Details = []string{"Run: work setup"}
 			} else if !info.IsDir() {
-				result.status = fmt.Sprintf("❌ NOT A DIRECTORY (%s)", gitFolder)
-				result.failed = true
+				result.Status, result.Message = checkFail, fmt.Sprintf("NOT A DIRECTORY (%s)", gitFolder)
 			} else {
 				// Test write permissions
 				testFile := filepath.Join(gitFolder, ".work-test")
 				if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
-					result.status = fmt.Sprintf("❌ NOT WRITABLE (%s)", gitFolder)
-					result.failed = true
+					result.Status, result.Message = checkFail, fmt.Sprintf("NOT WRITABLE (%s)", gitFolder)
 				} else {
 					os.Remove(testFile)
-					result.status = fmt.Sprintf("✓ %s", gitFolder)
+					result.Status, result.Message = checkOK, gitFolder
 				}
 			}
 		}
@@ -272,10 +300,10 @@ func runDoctor(cmd *cobra.Command, args []string) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		result := checkResult{name: "preferred_ide", order: 5, critical: false}
+		result := DoctorCheck{Name: "preferred_ide", order: 5, Critical: false}
 		ide := config.GetString("preferred_ide")
 		if ide == "" || ide == "none" {
-			result.status = "✓ none (auto-open disabled)"
+			result.Status, result.Message = checkOK, "none (auto-open disabled)"
 		} else {
 			var command string
 			switch ide {
@@ -289,10 +317,10 @@ func runDoctor(cmd *cobra.Command, args []string) {
 
 			// Check if command exists in PATH (cross-platform)
 			if _, err := exec.LookPath(command); err != nil {
-				result.status = fmt.Sprintf("⚠ %s command not found (set to '%s')", command, ide)
-				result.details = []string{"IDE won't auto-open but checkout will still work"}
+				result.Status, result.Message = checkWarn, fmt.Sprintf("%s command not found (set to '%s')", command, ide)
+				result.Details = []string{"IDE won't auto-open but checkout will still work"}
 			} else {
-				result.status = fmt.Sprintf("✓ %s", ide)
+				result.Status, result.Message = checkOK, ide
 			}
 		}
 		results <- result
@@ -304,24 +332,24 @@ func runDoctor(cmd *cobra.Command, args []string) {
 		defer wg.Done()
 
 		// 2. Check GitHub CLI
-		ghResult := checkResult{name: "gh (GitHub CLI)", order: 2, critical: true}
+		ghResult := DoctorCheck{Name: "gh (GitHub CLI)", order: 2, Critical: true}
 		if err := exec.Command("gh", "--version").Run(); err != nil {
-			ghResult.status = "❌ NOT FOUND"
-			ghResult.details = []string{"Install gh: https://cli.github.com/"}
-			ghResult.failed = true
+			ghResult.Status, ghResult.Message = checkFail, "NOT FOUND"
+			ghResult.Details = []string{"Install gh: https://cli.github.com/"}
 			results <- ghResult
 			return
 		}
 
 		output, _ := exec.Command("gh", "--version").Output()
 		lines := strings.Split(string(output), "\n")
+		ghResult.Status = checkOK
 		if len(lines) > 0 {
-			ghResult.status = fmt.Sprintf("✓ %s", strings.TrimSpace(lines[0]))
+			ghResult.Message = strings.TrimSpace(lines[0])
 		}
 		results <- ghResult
 
 		// Check gh authentication (depends on gh being installed)
-		authResult := checkResult{name: "gh authentication", order: 2, critical: true}
+		authResult := DoctorCheck{Name: "gh authentication", order: 2, Critical: true}
 		authCmd := exec.Command("gh", "auth", "status")
 		authOutput, err := authCmd.CombinedOutput()
 		outputStr := string(authOutput)
@@ -330,58 +358,59 @@ func runDoctor(cmd *cobra.Command, args []string) {
 		hasFailedAuth := strings.Contains(outputStr, "X Failed to log in")
 
 		if err != nil && !hasValidAuth {
-			authResult.status = "❌ NOT AUTHENTICATED"
-			authResult.details = []string{"Run: gh auth login"}
-			authResult.failed = true
+			authResult.Status, authResult.Message = checkFail, "NOT AUTHENTICATED"
+			authResult.Details = []string{"Run: gh auth login"}
 		} else if hasValidAuth && hasFailedAuth {
-			authResult.status = "⚠️  PARTIAL AUTHENTICATION"
-			authResult.details = []string{
+			authResult.Status, authResult.Message = checkWarn, "PARTIAL AUTHENTICATION"
+			authResult.Details = []string{
 				"",
 				"Details from 'gh auth status':",
 			}
 			for _, line := range strings.Split(strings.TrimSpace(outputStr), "\n") {
-				authResult.details = append(authResult.details, line)
+				authResult.Details = append(authResult.Details, line)
 			}
-			authResult.details = append(authResult.details,
+			authResult.Details = append(authResult.Details,
 				"",
 				"You have at least one valid account, but some accounts have invalid tokens.",
 				"To fix invalid accounts, run: gh auth login -h github.com",
 			)
 		} else {
-			authResult.status = "✓"
+			authResult.Status = checkOK
 		}
 		results <- authResult
 
 		// 4. Check preferred orgs (depends on gh)
-		orgsResult := checkResult{name: "preferred_orgs", order: 4, critical: false}
+		orgsResult := DoctorCheck{Name: "preferred_orgs", order: 4, Critical: false}
 		orgs := config.GetStringSlice("preferred_orgs")
 		if len(orgs) == 0 {
-			orgsResult.status = "⚠ NOT CONFIGURED"
-			orgsResult.details = []string{"Run: work setup"}
+			orgsResult.Status, orgsResult.Message = checkWarn, "NOT CONFIGURED"
+			orgsResult.Details = []string{"Run: work setup"}
 			results <- orgsResult
 			return
 		}
 
-		orgsResult.status = fmt.Sprintf("✓ %v", orgs)
+		orgsResult.Status, orgsResult.Message = checkOK, fmt.Sprintf("%v", orgs)
 		results <- orgsResult
 
 		// Try to verify access to at least one org
-		orgAccessResult := checkResult{name: "org access", order: 4, critical: false}
+		orgAccessResult := DoctorCheck{Name: "org access", order: 4, Critical: false}
 		hasAccess := false
 		for _, org := range orgs {
-			if org == "" || org == "myorg" {
+			ref, err := forge.ParseOrgRef(org)
+			// Only github.com orgs can be checked through gh
+			if err != nil || ref.Owner == "myorg" || ref.Host != forge.DefaultHost {
 				continue
 			}
-			orgCmd := exec.Command("gh", "api", fmt.Sprintf("orgs/%s", org))
+			orgCmd := exec.Command("gh", "api", fmt.Sprintf("orgs/%s", ref.Owner))
 			if err := orgCmd.Run(); err == nil {
 				hasAccess = true
 				break
 			}
 		}
 		if hasAccess {
-			orgAccessResult.status = "✓"
+			orgAccessResult.Status = checkOK
 		} else {
-			orgAccessResult.status = "⚠ Cannot access configured orgs (may need valid org names)"
+			orgAccessResult.Status, orgAccessResult.Message = checkWarn, "Cannot access configured orgs (may need valid org names)"
 		}
 		results <- orgAccessResult
 	}()
@@ -393,35 +422,41 @@ func runDoctor(cmd *cobra.Command, args []string) {
 	}()
 
 	// Collect all results
-	var allResults []checkResult
+	var allResults []DoctorCheck
 	for result := range results {
 		allResults = append(allResults, result)
 	}
 
 	// Sort results by order to maintain consistent output
-	for i := 0; i < len(allResults); i++ {
-		for j := i + 1; j < len(allResults); j++ {
-			if allResults[i].order > allResults[j].order {
-				allResults[i], allResults[j] = allResults[j], allResults[i]
-			}
+	sort.SliceStable(allResults, func(i, j int) bool {
+		if allResults[i].order != allResults[j].order {
+			return allResults[i].order < allResults[j].order
 		}
-	}
+		return allResults[i].Name < allResults[j].Name
+	})
 
 	// Display results in order
 	allGood := true
 	for _, result := range allResults {
-		fmt.Printf("Checking %s... %s\n", result.name, result.status)
-		for _, detail := range result.details {
+		printer.Printf("Checking %s... %s\n", result.Name, result.statusLine())
+		for _, detail := range result.Details {
 			if detail == "" {
-				fmt.Println()
+				printer.Println()
 			} else {
-				fmt.Printf("   %s\n", detail)
+				printer.Printf("   %s\n", detail)
 			}
 		}
-		if result.critical && result.failed {
+		if result.Critical && result.failed() {
 			allGood = false
 		}
-		fmt.Println() // Blank line separator
+		printer.Println() // Blank line separator
+	}
+
+	if !printer.IsText() {
+		if err := render(DoctorReport{Checks: allResults, Healthy: allGood}); err != nil {
+			return err
+		}
+		return doctorResult(allGood)
 	}
 
 	// Summary
@@ -434,6 +469,15 @@ func runDoctor(cmd *cobra.Command, args []string) {
 		fmt.Println("Fix the issues above, then run 'work doctor' again")
 	}
 	fmt.Println("========================")
+	return doctorResult(allGood)
+}
+
+// doctorResult makes 'work doctor' exit non-zero when a critical check failed
+func doctorResult(healthy bool) error {
+	if healthy {
+		return nil
+	}
+	return errs.Reported(errs.Failure, "critical health checks failed")
 }
 
 func init() {
@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/velvee-ai/ai-workflow/pkg/cache"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+	"github.com/velvee-ai/ai-workflow/pkg/parallel"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [repo]",
+	Short: "Show the state of all repositories and worktrees",
+	Long: `Show a dashboard of every worktree under your git folder (or a single repository).
+
+For each worktree it shows:
+  - Branch and commits ahead/behind its upstream
+  - Commits ahead/behind the default branch (origin/<default>)
+  - Local changes: +staged ~modified ?untracked !conflicted
+  - Age of the last commit
+  - State of the branch's pull request on the forge
+  - When the worktree was last opened with 'work checkout'
+
+Status is computed from local refs; run 'work sync' or 'git fetch' to refresh them.
+
+Examples:
+  work status                 # All repositories
+  work status ai-workflow     # One repository
+  work status --watch         # Refresh every 10 seconds until interrupted
+  work status --no-pr -o json # Skip forge lookups, machine-readable output`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeReposForSync,
+	RunE:              runStatus,
+}
+
+var (
+	statusWatch    bool
+	statusInterval time.Duration
+	statusNoPR     bool
+)
+
+// prCacheTTL bounds how often --watch asks the forge for pull request state
+const prCacheTTL = time.Minute
+
+// minStatusInterval is the shortest --interval, since each refresh scans every worktree
+const minStatusInterval = time.Second
+
+// StatusEntry describes the state of a single worktree.
+type StatusEntry struct {
+	Repo           string              `json:"repo"`
+	Path           string              `json:"path"`
+	Worktree       string              `json:"worktree"` // Folder relative to the repository container
+	Branch         string              `json:"branch"`
+	Upstream       string              `json:"upstream,omitempty"`
+	Ahead          int                 `json:"ahead"`
+	Behind         int                 `json:"behind"`
+	DefaultBranch  string              `json:"default_branch"`
+	AheadOfDefault int                 `json:"ahead_of_default"`
+	BehindDefault  int                 `json:"behind_default"`
+	Staged         int                 `json:"staged"`
+	Modified       int                 `json:"modified"`
+	Untracked      int                 `json:"untracked"`
+	Conflicted     int                 `json:"conflicted"`
+	LastCommit     time.Time           `json:"last_commit"`
+	PullRequest    *forge.PullRequest  `json:"pull_request,omitempty"`
+	Metadata       *cache.WorktreeMeta `json:"metadata,omitempty"` // Recorded by checkout
+	Error          string              `json:"error,omitempty"`
+
+	// hasDefault is false when origin/<default> could not be compared with HEAD
+	hasDefault bool
+}
+
+// StatusReport is the structured result of 'work status'.
+type StatusReport struct {
+	Worktrees []StatusEntry `json:"worktrees"`
+	Errors    []RepoError   `json:"errors,omitempty"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	if statusWatch && statusInterval < minStatusInterval {
+		return errs.New(errs.Usage, "--interval must be at least %s, got %s", minStatusInterval, statusInterval)
+	}
+
+	repos, err := discoverRepos()
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		var filtered []string
+		for _, repoPath := range repos {
+			if repoDisplayName(repoPath) == args[0] {
+				filtered = append(filtered, repoPath)
+			}
+		}
+		if len(filtered) == 0 {
+			return errs.New(errs.RepoNotFound, "repository '%s' not found", args[0])
+		}
+		repos = filtered
+	}
+
+	prs := cache.New[*forge.PullRequest](prCacheTTL)
+
+	if !statusWatch {
+		report := collectStatus(context.Background(), repos, prs, "Checking")
+		if !printer.IsText() {
+			return render(report)
+		}
+		printStatusTable(os.Stdout, report)
+		return nil
+	}
+
+	if !printer.IsText() {
+		return errs.New(errs.Usage, "--watch cannot be combined with --output %s", printer.Format())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for {
+		report := collectStatus(ctx, repos, prs, "")
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Clear the screen and redraw
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("Every %s: work status    %s\n\n", statusInterval, time.Now().Format("15:04:05"))
+		printStatusTable(os.Stdout, report)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(statusInterval):
+		}
+	}
+}
+
+// collectStatus gathers the status of every worktree of the given repo containers on the
+// worker pool, with a progress view titled title (none when empty). Pull request lookups
+// are cached in prs so --watch does not query the forge on every refresh.
+func collectStatus(ctx context.Context, repos []string, prs *cache.Cache[*forge.PullRequest], title string) StatusReport {
+	report := StatusReport{Worktrees: []StatusEntry{}}
+	metas := loadWorktreeMeta()
+	// Pull request lookups of all repositories share max_parallel slots, so the
+	// forge gets no more requests at once however many worktrees there are
+	lookups := make(chan struct{}, poolOptions().Limit)
+
+	results, failures := forEachRepo(ctx, title, repos, func(ctx context.Context, repoPath string) ([]StatusEntry, error) {
+		return repoStatus(ctx, repoPath, metas, prs, lookups)
+	})
+	for i, entries := range results {
+		if err := failures[i]; err != nil {
+			report.Errors = append(report.Errors, RepoError{Repo: repoDisplayName(repos[i]), Error: err.Error()})
+			continue
+		}
+		report.Worktrees = append(report.Worktrees, entries...)
+	}
+
+	sort.Slice(report.Worktrees, func(i, j int) bool {
+		if report.Worktrees[i].Repo != report.Worktrees[j].Repo {
+			return report.Worktrees[i].Repo < report.Worktrees[j].Repo
+		}
+		return report.Worktrees[i].Path < report.Worktrees[j].Path
+	})
+	return report
+}
+
+// repoStatus returns the status of every worktree of one repository, including main.
+// Worktrees are read max_parallel at a time; each pull request lookup holds a slot
+// in lookups while it runs.
+func repoStatus(ctx context.Context, repoPath string, metas map[string]cache.WorktreeMeta, prs *cache.Cache[*forge.PullRequest], lookups chan struct{}) ([]StatusEntry, error) {
+	runner := services.Get().GitRunner
+	gitDir := workspace.GitDir(repoPath)
+	repoName := repoDisplayName(repoPath)
+
+	defaultBranch, err := runner.GetDefaultBranch(ctx, gitDir)
+	if err != nil {
+		defaultBranch = getDefaultBranch(gitDir)
+	}
+
+	listed, err := runner.ListWorktrees(ctx, gitDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	// The bare repository of a bare container has no working copy to report on
+	var worktrees []gitexec.Worktree
+	for _, wt := range listed {
+		if !wt.Bare {
+			worktrees = append(worktrees, wt)
+		}
+	}
+
+	// Pull requests are looked up on the forge hosting origin
+	var client forge.Forge
+	var owner, name string
+	if !statusNoPR {
+		if c, origin, err := getOriginForge(gitDir); err == nil {
+			client, owner, name = c, origin.Owner(), origin.Name()
+		}
+	}
+
+	entries, _ := parallel.Map(ctx, worktrees, parallel.Options{Limit: poolOptions().Limit}, func(ctx context.Context, wt gitexec.Worktree) (StatusEntry, error) {
+		path, branch := wt.Path, wt.Branch
+		entry := StatusEntry{
+			Repo:          repoName,
+			Path:          path,
+			Worktree:      workspace.WorktreeName(repoPath, path),
+			Branch:        branch,
+			DefaultBranch: defaultBranch,
+		}
+		if meta, ok := metas[path]; ok {
+			entry.Metadata = &meta
+		}
+
+		status, err := runner.Status(ctx, path)
+		if err != nil {
+			entry.Error = err.Error()
+			return entry, nil
+		}
+		entry.Upstream = status.Upstream
+		entry.Ahead, entry.Behind = status.Ahead, status.Behind
+		entry.Staged, entry.Modified = status.Staged, status.Modified
+		entry.Untracked, entry.Conflicted = status.Untracked, status.Conflicted
+
+		if ahead, behind, err := runner.AheadBehind(ctx, path, "origin/"+defaultBranch, "HEAD"); err == nil {
+			entry.AheadOfDefault, entry.BehindDefault = ahead, behind
+			entry.hasDefault = true
+		}
+		if when, err := runner.LastCommitTime(ctx, path, "HEAD"); err == nil {
+			entry.LastCommit = when
+		}
+
+		if client != nil && branch != "" && branch != defaultBranch {
+			key := client.Host() + "/" + owner + "/" + name + "#" + branch
+			if pr, ok := prs.Get(key); ok {
+				entry.PullRequest = pr
+			} else if pr, err := findPRLimited(ctx, lookups, client, owner, name, branch); err == nil {
+				prs.Set(key, pr)
+				entry.PullRequest = pr
+			}
+		}
+		return entry, nil
+	})
+	// Worktrees not read before the repository timed out or was interrupted
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// findPRLimited looks up the pull request of branch once a slot in lookups is free.
+func findPRLimited(ctx context.Context, lookups chan struct{}, client forge.Forge, owner, name, branch string) (*forge.PullRequest, error) {
+	select {
+	case lookups <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-lookups }()
+	return client.FindPR(ctx, owner, name, branch)
+}
+
+// printStatusTable renders the report as an aligned table.
+func printStatusTable(w io.Writer, report StatusReport) {
+	for _, e := range report.Errors {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %s\n", e.Repo, e.Error)
+	}
+	if len(report.Worktrees) == 0 {
+		fmt.Fprintln(w, "No worktrees found")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPO\tWORKTREE\tBRANCH\tUPSTREAM\tDEFAULT\tCHANGES\tLAST COMMIT\tOPENED\tPR")
+	for _, e := range report.Worktrees {
+		branch := e.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		if e.Error != "" {
+			fmt.Fprintf(tw, "%s\t%s\t%s\terror: %s\t\t\t\t\t\n", e.Repo, e.Worktree, branch, e.Error)
+			continue
+		}
+
+		upstream := "-"
+		if e.Upstream != "" {
+			upstream = formatAheadBehind(e.Ahead, e.Behind)
+		}
+		vsDefault := "-"
+		if e.hasDefault {
+			vsDefault = formatAheadBehind(e.AheadOfDefault, e.BehindDefault)
+		}
+
+		var opened time.Time
+		if e.Metadata != nil {
+			opened = e.Metadata.LastOpened
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Repo,
+			e.Worktree,
+			branch,
+			upstream,
+			vsDefault,
+			formatChanges(e),
+			formatAge(e.LastCommit),
+			formatAge(opened),
+			formatPR(e.PullRequest),
+		)
+	}
+	tw.Flush()
+}
+
+// formatAheadBehind renders commit counts as "↑2 ↓1", or "=" when in sync.
+func formatAheadBehind(ahead, behind int) string {
+	var parts []string
+	if ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", ahead))
+	}
+	if behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", behind))
+	}
+	if len(parts) == 0 {
+		return "="
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatChanges renders local change counts as "+staged ~modified ?untracked !conflicted".
+func formatChanges(e StatusEntry) string {
+	var parts []string
+	if e.Staged > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", e.Staged))
+	}
+	if e.Modified > 0 {
+		parts = append(parts, fmt.Sprintf("~%d", e.Modified))
+	}
+	if e.Untracked > 0 {
+		parts = append(parts, fmt.Sprintf("?%d", e.Untracked))
+	}
+	if e.Conflicted > 0 {
+		parts = append(parts, fmt.Sprintf("!%d", e.Conflicted))
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatAge renders how long ago t was, e.g. "5m ago" or "3d ago".
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	default:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/24/30))
+	}
+}
+
+// formatPR renders a pull request as "#12 open".
+func formatPR(pr *forge.PullRequest) string {
+	if pr == nil {
+		return "-"
+	}
+	return fmt.Sprintf("#%d %s", pr.Number, pr.State)
+}
+
+func init() {
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Refresh the dashboard until interrupted")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 10*time.Second, "Refresh interval for --watch (at least 1s)")
+	statusCmd.Flags().BoolVar(&statusNoPR, "no-pr", false, "Skip pull request lookups on the forge")
+
+	rootCmd.AddCommand(statusCmd)
+}
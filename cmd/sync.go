@@ -2,15 +2,17 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/velvee-ai/ai-workflow/pkg/errs"
 	"github.com/velvee-ai/ai-workflow/pkg/services"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
 )
 
 var syncCmd = &cobra.Command{
@@ -23,39 +25,61 @@ This command helps keep your default branches up-to-date by:
   - Pulling the latest changes with rebase
   - Reporting any errors or conflicts
 
+Repositories are synced max_parallel at a time (default 8), each within
+repo_timeout (default 2m); see 'work config set'.
+
 Examples:
   work sync              # Sync all repositories
   work sync ai-workflow  # Sync specific repository`,
 	ValidArgsFunction: completeReposForSync,
-	Run:               runSync,
+	RunE:              runSync,
 }
 
 // SyncResult holds the result of syncing a repository
 type SyncResult struct {
-	RepoName      string
-	DefaultBranch string
-	Success       bool
-	Error         error
-	Message       string
+	RepoName      string `json:"repo"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	Success       bool   `json:"success"`
+	Error         error  `json:"-"`
+	Message       string `json:"message,omitempty"`
+}
+
+// MarshalJSON serializes the error as its message.
+func (r SyncResult) MarshalJSON() ([]byte, error) {
+	type plain SyncResult
+	doc := struct {
+		plain
+		Error string `json:"error,omitempty"`
+	}{plain: plain(r)}
+	if r.Error != nil {
+		doc.Error = r.Error.Error()
+	}
+	return json.Marshal(doc)
+}
+
+// SyncReport is the structured result of 'work sync'.
+type SyncReport struct {
+	Repositories []SyncResult `json:"repositories"`
+	Succeeded    int          `json:"succeeded"`
+	Failed       int          `json:"failed"`
 }
 
-func runSync(cmd *cobra.Command, args []string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+func runSync(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
 
 	var repoFilter string
 	if len(args) > 0 {
 		repoFilter = args[0]
 	}
 
-	repos := discoverRepos()
-	if repos == nil {
-		return
+	repos, err := discoverRepos()
+	if err != nil {
+		return err
 	}
 
 	if len(repos) == 0 {
-		fmt.Println("No repositories found in git folder")
-		return
+		printer.Println("No repositories found in git folder")
+		return render(SyncReport{Repositories: []SyncResult{}})
 	}
 
 	// Filter to specific repo if provided
@@ -63,67 +87,72 @@ func runSync(cmd *cobra.Command, args []string) {
 	if repoFilter != "" {
 		found := false
 		for _, repoPath := range repos {
-			if filepath.Base(repoPath) == repoFilter {
+			if repoDisplayName(repoPath) == repoFilter {
 				reposToSync = []string{repoPath}
 				found = true
 				break
 			}
 		}
 		if !found {
-			fmt.Fprintf(os.Stderr, "Error: Repository '%s' not found\n", repoFilter)
-			os.Exit(1)
+			return errs.New(errs.RepoNotFound, "repository '%s' not found", repoFilter)
 		}
 	} else {
 		reposToSync = repos
 	}
 
 	if len(reposToSync) == 1 {
-		fmt.Printf("Syncing %s...\n", filepath.Base(reposToSync[0]))
+		printer.Printf("Syncing %s...\n", repoDisplayName(reposToSync[0]))
 	} else {
-		fmt.Printf("Syncing %d repositories...\n", len(reposToSync))
-	}
-
-	// Process repositories concurrently
-	var wg sync.WaitGroup
-	results := make(chan SyncResult, len(reposToSync))
-
-	for _, repoPath := range reposToSync {
-		wg.Add(1)
-		go func(rPath string) {
-			defer wg.Done()
-			result := syncRepository(ctx, rPath)
-			results <- result
-		}(repoPath)
+		printer.Printf("Syncing %d repositories...\n", len(reposToSync))
 	}
 
-	// Close results channel after all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	// Process repositories on the worker pool
+	results, failures := forEachRepo(ctx, "Syncing", reposToSync, func(ctx context.Context, repoPath string) (SyncResult, error) {
+		result := syncRepository(ctx, repoPath)
+		return result, result.Error
+	})
 
 	// Collect and display results
-	successCount := 0
-	errorCount := 0
+	report := SyncReport{Repositories: []SyncResult{}}
 	var errors []SyncResult
 
-	for result := range results {
+	for i, result := range results {
+		// Timed out or never started
+		if failures[i] != nil {
+			result.RepoName = repoDisplayName(reposToSync[i])
+			result.Success = false
+			result.Error = failures[i]
+		}
+		report.Repositories = append(report.Repositories, result)
 		if result.Success {
-			successCount++
-			fmt.Printf("✓ %s (%s): %s\n", result.RepoName, result.DefaultBranch, result.Message)
+			report.Succeeded++
+			printer.Printf("✓ %s (%s): %s\n", result.RepoName, result.DefaultBranch, result.Message)
 		} else {
-			errorCount++
+			report.Failed++
 			errors = append(errors, result)
-			fmt.Fprintf(os.Stderr, "✗ %s: %s\n", result.RepoName, result.Error.Error())
+			if printer.IsText() {
+				fmt.Fprintf(os.Stderr, "✗ %s: %s\n", result.RepoName, result.Error.Error())
+			}
 		}
 	}
 
+	// Structured output: results in a stable order
+	if !printer.IsText() {
+		sort.Slice(report.Repositories, func(i, j int) bool {
+			return report.Repositories[i].RepoName < report.Repositories[j].RepoName
+		})
+		if err := render(report); err != nil {
+			return err
+		}
+		return syncResult(errors)
+	}
+
 	// Summary
 	fmt.Println()
-	if errorCount == 0 {
-		fmt.Printf("All %d repositories synced successfully\n", successCount)
+	if report.Failed == 0 {
+		fmt.Printf("All %d repositories synced successfully\n", report.Succeeded)
 	} else {
-		fmt.Printf("Synced: %d successful, %d failed\n", successCount, errorCount)
+		fmt.Printf("Synced: %d successful, %d failed\n", report.Succeeded, report.Failed)
 		if len(errors) > 0 {
 			fmt.Println("\nFailed repositories:")
 			for _, err := range errors {
@@ -131,12 +160,26 @@ func runSync(cmd *cobra.Command, args []string) {
 			}
 		}
 	}
+	return syncResult(errors)
+}
+
+// syncResult makes 'work sync' exit non-zero when a repository failed to sync.
+// A single failure keeps its own kind (e.g. dirty worktree) for the exit code.
+func syncResult(failed []SyncResult) error {
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return errs.Reported(errs.KindOf(failed[0].Error), "%s failed to sync", failed[0].RepoName)
+	default:
+		return errs.Reported(errs.Failure, "%d repositories failed to sync", len(failed))
+	}
 }
 
 // syncRepository syncs the default branch of a single repository
 func syncRepository(ctx context.Context, repoPath string) SyncResult {
-	repoName := filepath.Base(repoPath)
-	mainPath := filepath.Join(repoPath, "main")
+	repoName := repoDisplayName(repoPath)
+	gitDir := workspace.GitDir(repoPath)
 
 	result := SyncResult{
 		RepoName: repoName,
@@ -145,10 +188,10 @@ func syncRepository(ctx context.Context, repoPath string) SyncResult {
 	runner := services.Get().GitRunner
 
 	// Get default branch
-	defaultBranch, err := runner.GetDefaultBranch(ctx, mainPath)
+	defaultBranch, err := runner.GetDefaultBranch(ctx, gitDir)
 	if err != nil {
 		// Fallback to checking locally
-		defaultBranch = getLocalDefaultBranch(ctx, mainPath)
+		defaultBranch = getLocalDefaultBranch(ctx, gitDir)
 		if defaultBranch == "" {
 			result.Error = fmt.Errorf("could not determine default branch")
 			return result
@@ -156,6 +199,19 @@ func syncRepository(ctx context.Context, repoPath string) SyncResult {
 	}
 	result.DefaultBranch = defaultBranch
 
+	// main/, or the default branch worktree of a bare container. A bare container
+	// without one only needs its remote-tracking branches updated.
+	mainPath, err := worktreeManager().DefaultWorktree(ctx, repoPath, defaultBranch)
+	if errors.Is(err, workspace.ErrNoDefaultWorktree) {
+		if err := runner.FetchPrune(ctx, gitDir); err != nil {
+			result.Error = fmt.Errorf("failed to fetch: %w", err)
+			return result
+		}
+		result.Message = "Fetched"
+		result.Success = true
+		return result
+	}
+
 	// Get current branch
 	currentBranch, err := runner.GetCurrentBranch(ctx, mainPath)
 	if err != nil {
@@ -180,14 +236,14 @@ func syncRepository(ctx context.Context, repoPath string) SyncResult {
 	}
 
 	if len(status) > 0 {
-		result.Error = fmt.Errorf("has uncommitted changes, refusing to sync")
+		result.Error = errs.New(errs.DirtyWorktree, "has uncommitted changes, refusing to sync")
 		return result
 	}
 
 	// Pull with rebase
 	pullResult, err := runner.Run(ctx, mainPath, "pull", "--rebase")
 	if err != nil {
-		result.Error = fmt.Errorf("failed to pull: %w", err)
+		result.Error = errs.Wrap(errs.Conflict, err, "failed to pull")
 		return result
 	}
 
@@ -235,15 +291,15 @@ func completeReposForSync(cmd *cobra.Command, args []string, toComplete string)
 		return []string{}, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	repos := discoverRepos()
-	if repos == nil {
+	repos, err := discoverRepos()
+	if err != nil {
 		return []string{}, cobra.ShellCompDirectiveNoFileComp
 	}
 
 	// Extract just the repo names
 	var repoNames []string
 	for _, repoPath := range repos {
-		repoNames = append(repoNames, filepath.Base(repoPath))
+		repoNames = append(repoNames, repoDisplayName(repoPath))
 	}
 
 	return repoNames, cobra.ShellCompDirectiveNoFileComp
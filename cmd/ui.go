@@ -0,0 +1,529 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/velvee-ai/ai-workflow/pkg/cache"
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/parallel"
+	"github.com/velvee-ai/ai-workflow/pkg/services"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Browse and manage worktrees interactively",
+	Long: `Open a full-screen browser of all repositories and their worktrees.
+
+Keys:
+  ↑/↓ j/k   Move between repositories and worktrees
+  enter/o   Open the selected worktree in your IDE
+  v         View status and diff of the selected worktree
+  n         Create a worktree for a new or existing branch
+  s         Sync the repository's default branch
+  d         Remove the selected worktree (only if it has no changes)
+  r         Rescan
+  q         Quit`,
+	Args: cobra.NoArgs,
+	RunE: runUI,
+}
+
+var (
+	uiTitleStyle    = lipgloss.NewStyle().Bold(true)
+	uiRepoStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	uiSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	uiMutedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	uiStaleStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	uiErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// uiMode is the screen the UI is currently showing
+type uiMode int
+
+const (
+	uiBrowse uiMode = iota
+	uiConfirmRemove
+	uiNewBranch
+	uiDetail
+)
+
+// uiRow is one line of the browser: a repository header or one of its worktrees
+type uiRow struct {
+	repoPath string
+	repoName string
+	worktree *WorktreeInfo // nil for repository rows
+}
+
+// uiScanMsg delivers the result of scanning all repositories
+type uiScanMsg struct {
+	rows   []uiRow
+	errors []RepoError
+}
+
+// uiActionMsg reports the outcome of an action; rescan is set when worktrees may have changed
+type uiActionMsg struct {
+	message string
+	err     error
+	rescan  bool
+}
+
+// uiDetailMsg delivers the status and diff of a worktree
+type uiDetailMsg struct {
+	title   string
+	content string
+	err     error
+}
+
+type uiModel struct {
+	ctx   context.Context
+	repos []string
+
+	rows   []uiRow
+	errors []RepoError
+	cursor int
+	offset int
+
+	mode    uiMode
+	busy    string // Description of the running action, empty when idle
+	message string
+	isError bool
+
+	spinner  spinner.Model
+	input    textinput.Model
+	viewport viewport.Model
+	title    string
+
+	width, height int
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	repos, err := discoverRepos()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tea.NewProgram(newUIModel(repos), tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("running UI: %w", err)
+	}
+	return nil
+}
+
+func newUIModel(repos []string) uiModel {
+	sp := spinner.New()
+	sp.Spinner = spinner.MiniDot
+
+	input := textinput.New()
+	input.Placeholder = "branch name"
+	input.CharLimit = 200
+
+	return uiModel{
+		ctx:      context.Background(),
+		repos:    repos,
+		busy:     "Scanning worktrees…",
+		spinner:  sp,
+		input:    input,
+		viewport: viewport.New(80, 20),
+	}
+}
+
+func (m uiModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.scan())
+}
+
+// scan runs scanWorktrees for every repository on the worker pool
+func (m uiModel) scan() tea.Cmd {
+	ctx, repos := m.ctx, m.repos
+	return func() tea.Msg {
+		type repoResult struct {
+			repoPath  string
+			worktrees []WorktreeInfo
+			err       error
+		}
+
+		metas := loadWorktreeMeta()
+		results, failures := parallel.Map(ctx, repos, poolOptions(), func(ctx context.Context, repoPath string) (repoResult, error) {
+			// Fetch warnings would corrupt the full-screen display
+			worktrees, err := scanWorktrees(ctx, repoPath, repoDisplayName(repoPath), metas, io.Discard)
+			return repoResult{worktrees: worktrees}, err
+		})
+		for i := range results {
+			results[i].repoPath, results[i].err = repos[i], failures[i]
+		}
+
+		sort.Slice(results, func(i, j int) bool {
+			return repoDisplayName(results[i].repoPath) < repoDisplayName(results[j].repoPath)
+		})
+
+		var msg uiScanMsg
+		for _, r := range results {
+			name := repoDisplayName(r.repoPath)
+			if r.err != nil {
+				msg.errors = append(msg.errors, RepoError{Repo: name, Error: r.err.Error()})
+				continue
+			}
+			msg.rows = append(msg.rows, uiRow{repoPath: r.repoPath, repoName: name})
+			sortWorktrees(r.worktrees)
+			for i := range r.worktrees {
+				if r.worktrees[i].Orphaned {
+					continue // Not a worktree; 'work cleanup' deals with it
+				}
+				msg.rows = append(msg.rows, uiRow{repoPath: r.repoPath, repoName: name, worktree: &r.worktrees[i]})
+			}
+		}
+		return msg
+	}
+}
+
+func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.Height = max(msg.Height-3, 1)
+		m.clampOffset()
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case uiScanMsg:
+		m.busy = ""
+		m.rows, m.errors = msg.rows, msg.errors
+		if m.cursor >= len(m.rows) {
+			m.cursor = max(len(m.rows)-1, 0)
+		}
+		m.clampOffset()
+		return m, nil
+
+	case uiActionMsg:
+		m.busy = ""
+		m.setMessage(msg.message, msg.err)
+		if msg.rescan {
+			m.busy = "Scanning worktrees…"
+			return m, tea.Batch(m.spinner.Tick, m.scan())
+		}
+		return m, nil
+
+	case uiDetailMsg:
+		m.busy = ""
+		if msg.err != nil {
+			m.setMessage("", msg.err)
+			return m, nil
+		}
+		m.mode = uiDetail
+		m.title = msg.title
+		m.viewport.SetContent(msg.content)
+		m.viewport.GotoTop()
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case uiDetail:
+			return m.updateDetail(msg)
+		case uiConfirmRemove:
+			return m.updateConfirmRemove(msg)
+		case uiNewBranch:
+			return m.updateNewBranch(msg)
+		default:
+			return m.updateBrowse(msg)
+		}
+	}
+
+	return m, nil
+}
+
+func (m uiModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		m.clampOffset()
+		return m, nil
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+		m.clampOffset()
+		return m, nil
+	}
+
+	// Actions are ignored while another one is running
+	if m.busy != "" || len(m.rows) == 0 {
+		return m, nil
+	}
+	row := m.rows[m.cursor]
+	m.message = ""
+
+	switch msg.String() {
+	case "r":
+		m.busy = "Scanning worktrees…"
+		return m, tea.Batch(m.spinner.Tick, m.scan())
+
+	case "enter", "o":
+		if row.worktree == nil {
+			return m, nil
+		}
+		return m, openWorktreeInIDE(*row.worktree)
+
+	case "v":
+		if row.worktree == nil {
+			return m, nil
+		}
+		m.busy = "Loading diff…"
+		return m, tea.Batch(m.spinner.Tick, m.loadDetail(*row.worktree))
+
+	case "s":
+		m.busy = fmt.Sprintf("Syncing %s…", row.repoName)
+		return m, tea.Batch(m.spinner.Tick, m.syncRepo(row.repoPath))
+
+	case "d":
+		if row.worktree == nil {
+			return m, nil
+		}
+		if row.worktree.HasChanges {
+			m.setMessage("", fmt.Errorf("%s has uncommitted changes, refusing to remove", row.worktree.Branch))
+			return m, nil
+		}
+		m.mode = uiConfirmRemove
+		return m, nil
+
+	case "n":
+		m.mode = uiNewBranch
+		m.input.Reset()
+		return m, m.input.Focus()
+	}
+
+	return m, nil
+}
+
+func (m uiModel) updateConfirmRemove(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = uiBrowse
+	if msg.String() != "y" {
+		return m, nil
+	}
+
+	info := *m.rows[m.cursor].worktree
+	m.busy = fmt.Sprintf("Removing %s…", info.Branch)
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		if err := removeWorktreeSafely(m.ctx, info, false); err != nil {
+			return uiActionMsg{err: fmt.Errorf("could not remove %s: %w", info.Branch, err)}
+		}
+		return uiActionMsg{message: fmt.Sprintf("Removed worktree %s", info.Branch), rescan: true}
+	})
+}
+
+func (m uiModel) updateNewBranch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = uiBrowse
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		m.mode = uiBrowse
+		m.input.Blur()
+		branch := strings.TrimSpace(m.input.Value())
+		if branch == "" {
+			return m, nil
+		}
+		return m, checkoutInUI(m.rows[m.cursor].repoName, branch)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m uiModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		m.mode = uiBrowse
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// syncRepo syncs the default branch of a repository, like 'work sync <repo>'
+func (m uiModel) syncRepo(repoPath string) tea.Cmd {
+	return func() tea.Msg {
+		result := syncRepository(m.ctx, repoPath)
+		if result.Error != nil {
+			return uiActionMsg{err: fmt.Errorf("sync %s: %w", result.RepoName, result.Error)}
+		}
+		return uiActionMsg{message: fmt.Sprintf("%s: %s", result.RepoName, result.Message), rescan: true}
+	}
+}
+
+// loadDetail collects the status and full diff against HEAD of a worktree
+func (m uiModel) loadDetail(info WorktreeInfo) tea.Cmd {
+	return func() tea.Msg {
+		runner := services.Get().GitRunner
+
+		status, err := runner.RunSimple(m.ctx, info.Path, "status", "--short", "--branch")
+		if err != nil {
+			return uiDetailMsg{err: err}
+		}
+		diff, err := runner.RunSimple(m.ctx, info.Path, "diff", "HEAD", "--stat", "--patch")
+		if err != nil {
+			return uiDetailMsg{err: err}
+		}
+
+		content := status
+		if meta := info.Metadata; meta != nil && meta.Source != "" {
+			content = "Source: " + meta.Source + "\n" + content
+		}
+		if diff != "" {
+			content += "\n\n" + diff
+		}
+		return uiDetailMsg{title: fmt.Sprintf("%s · %s", info.RepoName, info.Branch), content: content}
+	}
+}
+
+// openWorktreeInIDE opens a worktree in the configured IDE
+func openWorktreeInIDE(info WorktreeInfo) tea.Cmd {
+	return func() tea.Msg {
+		ide := config.GetString("preferred_ide")
+		if ide == "" || ide == "none" {
+			return uiActionMsg{err: fmt.Errorf("no IDE configured; run: work config set preferred_ide vscode")}
+		}
+		openInIDE(info.Path)
+		cache.TouchWorktree(info.Path, info.RepoPath, info.Branch) // Only used for ranking
+		return uiActionMsg{message: fmt.Sprintf("Opened %s in %s", info.Name(), ide)}
+	}
+}
+
+// checkoutInUI runs 'work checkout <repo> <branch>' with the terminal released.
+// Running the command as a child process reuses the complete checkout flow
+// (cloning, post-checkout script, IDE) without it exiting the UI on errors.
+func checkoutInUI(repoName, branch string) tea.Cmd {
+	self, err := os.Executable()
+	if err != nil {
+		return func() tea.Msg { return uiActionMsg{err: err} }
+	}
+
+	var stderr bytes.Buffer
+	c := exec.Command(self, "checkout", repoName, branch)
+	c.Stderr = &stderr
+
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			if lines := strings.Split(strings.TrimSpace(stderr.String()), "\n"); lines[0] != "" {
+				err = fmt.Errorf("%s", strings.TrimPrefix(lines[0], "Error: "))
+			}
+			return uiActionMsg{err: fmt.Errorf("checkout %s: %w", branch, err), rescan: true}
+		}
+		return uiActionMsg{message: fmt.Sprintf("Checked out %s in %s", branch, repoName), rescan: true}
+	})
+}
+
+func (m *uiModel) setMessage(message string, err error) {
+	m.isError = err != nil
+	m.message = message
+	if err != nil {
+		m.message = err.Error()
+	}
+}
+
+// listHeight is the number of rows available to the repository list
+func (m uiModel) listHeight() int {
+	if m.height == 0 {
+		return len(m.rows)
+	}
+	return max(m.height-4, 1)
+}
+
+// clampOffset scrolls the list so the cursor stays visible
+func (m *uiModel) clampOffset() {
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+func (m uiModel) View() string {
+	if m.mode == uiDetail {
+		return uiTitleStyle.Render(m.title) + "\n" +
+			m.viewport.View() + "\n" +
+			uiMutedStyle.Render("↑/↓ scroll · q back")
+	}
+
+	var b strings.Builder
+	b.WriteString(uiTitleStyle.Render("work · worktrees") + "\n\n")
+
+	if len(m.rows) == 0 && m.busy == "" {
+		b.WriteString(uiMutedStyle.Render("No repositories found") + "\n")
+	}
+
+	end := min(m.offset+m.listHeight(), len(m.rows))
+	for i := m.offset; i < end; i++ {
+		line := m.rowLine(m.rows[i])
+		if i == m.cursor {
+			line = uiSelectedStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.mode == uiConfirmRemove:
+		b.WriteString(fmt.Sprintf("Remove worktree %s? (y/N) ", m.rows[m.cursor].worktree.Branch))
+	case m.mode == uiNewBranch:
+		b.WriteString(fmt.Sprintf("New worktree in %s: %s", m.rows[m.cursor].repoName, m.input.View()))
+	case m.busy != "":
+		b.WriteString(m.spinner.View() + " " + m.busy)
+	case m.message != "" && m.isError:
+		b.WriteString(uiErrorStyle.Render(m.message))
+	case m.message != "":
+		b.WriteString(m.message)
+	case len(m.errors) > 0:
+		b.WriteString(uiErrorStyle.Render(fmt.Sprintf("%d repositories could not be scanned (first: %s: %s)",
+			len(m.errors), m.errors[0].Repo, m.errors[0].Error)))
+	default:
+		b.WriteString(uiMutedStyle.Render("o open · v diff · n new · s sync · d remove · r rescan · q quit"))
+	}
+
+	return b.String()
+}
+
+// rowLine renders a repository header or a worktree line
+func (m uiModel) rowLine(row uiRow) string {
+	if row.worktree == nil {
+		return uiRepoStyle.Render(row.repoName)
+	}
+
+	wt := row.worktree
+	status := wt.StatusString()
+	if wt.IsStale() {
+		status = uiStaleStyle.Render(status)
+	}
+	return fmt.Sprintf("  %-40s %s %s", wt.Name(), status, uiMutedStyle.Render(formatBytes(wt.SizeBytes)))
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
@@ -0,0 +1,291 @@
+// Package archive saves worktrees removed by 'work cleanup run --archive' so they
+// can be recreated later: the branch head is kept under refs/work/archive/ and
+// selected untracked or ignored files in a tarball under ~/.work/archive.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/velvee-ai/ai-workflow/pkg/cache"
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+// RefPrefix is where archived branch heads are kept.
+const RefPrefix = "refs/work/archive/"
+
+// DefaultFiles are the globs of untracked or ignored files archived when none are configured.
+var DefaultFiles = []string{".env", ".env.*"}
+
+// ErrNotFound is returned when no archive has the requested ID.
+var ErrNotFound = errors.New("archive not found")
+
+// timestampFormat names refs and IDs; it sorts chronologically.
+const timestampFormat = "20060102-150405"
+
+// Archive describes a removed worktree.
+type Archive struct {
+	ID        string    `json:"id"`
+	Repo      string    `json:"repo"`
+	Container string    `json:"container"`
+	Branch    string    `json:"branch"`
+	Path      string    `json:"path"`   // Where the worktree was
+	Commit    string    `json:"commit"` // Branch head when it was archived
+	Ref       string    `json:"ref"`
+	Unpushed  int       `json:"unpushed"` // Commits not on any remote-tracking branch
+	Files     []string  `json:"files,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Metadata is the worktree's record in the cache database, restored with it
+	Metadata *cache.WorktreeMeta `json:"metadata,omitempty"`
+}
+
+// Options selects the worktree to archive.
+type Options struct {
+	Repo      string // Display name, used in the ID
+	Container string
+	Path      string
+	Branch    string
+	Files     []string // Globs of untracked or ignored files to keep; DefaultFiles when empty
+	Metadata  *cache.WorktreeMeta
+}
+
+// GetArchiveDir returns the directory holding archives.
+func GetArchiveDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".work", "archive"), nil
+}
+
+// MoveFolder moves a folder that is not a worktree, e.g. an orphaned folder in a
+// repository container, to <archive directory>/folders/<repo>-<name>-<timestamp>
+// instead of deleting it, and returns its new path.
+func MoveFolder(repo, name, path string, now time.Time) (string, error) {
+	dir, err := GetArchiveDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "folders")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	prefix := workspace.Slug(repo) + "-" + workspace.Slug(name) + "-"
+	dest := filepath.Join(dir, prefix+now.Format(timestampFormat))
+	for n := 2; exists(dest); n++ {
+		dest = filepath.Join(dir, fmt.Sprintf("%s%s-%d", prefix, now.Format(timestampFormat), n))
+	}
+	if err := os.Rename(path, dest); err != nil {
+		return "", fmt.Errorf("moving %s to the archive: %w", path, err)
+	}
+	return dest, nil
+}
+
+// Create archives the worktree described by opts. The worktree itself is left alone.
+func Create(ctx context.Context, runner *gitexec.Runner, opts Options, now time.Time) (*Archive, error) {
+	if opts.Branch == "" {
+		return nil, fmt.Errorf("worktree '%s' has no branch", opts.Path)
+	}
+	dir, err := GetArchiveDir()
+	if err != nil {
+		return nil, err
+	}
+	// Archived files are typically secrets like .env.local, readable by the owner only
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to restrict archive directory: %w", err)
+	}
+
+	commit, err := runner.RunSimple(ctx, opts.Path, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("reading HEAD: %w", err)
+	}
+	a := &Archive{
+		Repo:      opts.Repo,
+		Container: opts.Container,
+		Branch:    opts.Branch,
+		Path:      opts.Path,
+		Commit:    commit,
+		CreatedAt: now,
+		Metadata:  opts.Metadata,
+	}
+	if n, err := runner.UnpushedCommits(ctx, opts.Path, "HEAD", workspace.PullHeadRefs+"*"); err == nil {
+		a.Unpushed = n
+	}
+
+	// Two worktrees archived in the same second get distinct IDs and refs
+	prefix := workspace.Slug(opts.Repo) + "-" + workspace.Slug(opts.Branch) + "-"
+	stamp := now.Format(timestampFormat)
+	for n := 2; exists(filepath.Join(dir, prefix+stamp+".json")); n++ {
+		stamp = fmt.Sprintf("%s-%d", now.Format(timestampFormat), n)
+	}
+	a.ID = prefix + stamp
+	a.Ref = RefPrefix + opts.Branch + "/" + stamp
+
+	files, err := untrackedFiles(ctx, runner, opts.Path, opts.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := runner.Run(ctx, opts.Path, "update-ref", a.Ref, commit); err != nil {
+		return nil, fmt.Errorf("saving %s: %w", a.Ref, err)
+	}
+	if len(files) > 0 {
+		if err := writeTarball(filepath.Join(dir, a.ID+".tar.gz"), opts.Path, files); err != nil {
+			runner.RunIgnoreError(ctx, opts.Path, "update-ref", "-d", a.Ref)
+			return nil, err
+		}
+		a.Files = files
+	}
+	if err := a.save(dir); err != nil {
+		runner.RunIgnoreError(ctx, opts.Path, "update-ref", "-d", a.Ref)
+		os.Remove(filepath.Join(dir, a.ID+".tar.gz"))
+		return nil, err
+	}
+	return a, nil
+}
+
+// List returns all archives, newest first.
+func List() ([]Archive, error) {
+	dir, err := GetArchiveDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var archives []Archive
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		a, err := Load(id)
+		if err != nil {
+			continue // Skip manifests we cannot read
+		}
+		archives = append(archives, *a)
+	}
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i].CreatedAt.After(archives[j].CreatedAt)
+	})
+	return archives, nil
+}
+
+// Load returns the archive with the given ID, or ErrNotFound.
+func Load(id string) (*Archive, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("%w: '%s'", ErrNotFound, id)
+	}
+	dir, err := GetArchiveDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: '%s'", ErrNotFound, id)
+	} else if err != nil {
+		return nil, err
+	}
+	var a Archive
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("reading archive '%s': %w", id, err)
+	}
+	return &a, nil
+}
+
+// Extract writes the archived files into dest, the restored worktree.
+func (a *Archive) Extract(dest string) error {
+	if len(a.Files) == 0 {
+		return nil
+	}
+	dir, err := GetArchiveDir()
+	if err != nil {
+		return err
+	}
+	return extractTarball(filepath.Join(dir, a.ID+".tar.gz"), dest)
+}
+
+// Delete removes the archive's ref and files.
+func Delete(ctx context.Context, runner *gitexec.Runner, a *Archive) error {
+	dir, err := GetArchiveDir()
+	if err != nil {
+		return err
+	}
+	// The ref went away with the repository if it was deleted
+	if gitDir := workspace.GitDir(a.Container); exists(gitDir) {
+		if _, err := runner.Run(ctx, gitDir, "update-ref", "-d", a.Ref); err != nil {
+			return fmt.Errorf("deleting %s: %w", a.Ref, err)
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, a.ID+".tar.gz")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Remove(filepath.Join(dir, a.ID+".json"))
+}
+
+func (a *Archive) save(dir string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, a.ID+".json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to save archive: %w", err)
+	}
+	return nil
+}
+
+// untrackedFiles lists the untracked and ignored files in worktree that match globs.
+func untrackedFiles(ctx context.Context, runner *gitexec.Runner, worktree string, globs []string) ([]string, error) {
+	if len(globs) == 0 {
+		globs = DefaultFiles
+	}
+	out, err := runner.RunSimple(ctx, worktree, "ls-files", "--others", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("listing untracked files: %w", err)
+	}
+	var files []string
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" && Match(globs, name) {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// Match reports whether the slash-separated path name matches one of globs. Like
+// .gitignore patterns, globs without a slash match the base name in any folder.
+func Match(globs []string, name string) bool {
+	for _, glob := range globs {
+		target := name
+		if !strings.Contains(glob, "/") {
+			target = path.Base(name)
+		}
+		if ok, _ := path.Match(strings.TrimPrefix(glob, "/"), target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
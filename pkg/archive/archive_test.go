@@ -0,0 +1,200 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/velvee-ai/ai-workflow/pkg/cache"
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+)
+
+func TestMatch(t *testing.T) {
+	globs := []string{".env", ".env.*", "config/local.yaml"}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{".env", true},
+		{".env.local", true},
+		{"web/.env.development.local", true},
+		{"config/local.yaml", true},
+		{"web/config/local.yaml", false},
+		{"env", false},
+		{"node_modules/pkg/index.js", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(globs, tt.name); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAndExtract(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	runner := gitexec.New(5 * time.Second)
+	ctx := context.Background()
+
+	// A clone with one pushed and one unpushed commit, an ignored .env.local and
+	// ignored build output
+	dir := t.TempDir()
+	origin := filepath.Join(dir, "origin")
+	container := filepath.Join(dir, "api")
+	repo := filepath.Join(container, "main")
+	mustRun(t, runner, "", "init", "--initial-branch=main", origin)
+	commit(t, runner, origin, ".gitignore", ".env*\nbuild/\n")
+	mustRun(t, runner, "", "clone", origin, repo)
+	mustRun(t, runner, repo, "checkout", "-b", "feature/login")
+	commit(t, runner, repo, "login.go", "package login\n")
+	writeFile(t, filepath.Join(repo, ".env.local"), "TOKEN=secret\n")
+	writeFile(t, filepath.Join(repo, "build", "out.bin"), "binary")
+
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	opts := Options{
+		Repo:      "acme/api",
+		Container: container,
+		Path:      repo,
+		Branch:    "feature/login",
+		Metadata:  &cache.WorktreeMeta{Path: repo, Branch: "feature/login", Source: "PROJ-1"},
+	}
+	a, err := Create(ctx, runner, opts, now)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if a.ID != "acme-api-feature-login-20260301-093000" {
+		t.Errorf("ID = %q", a.ID)
+	}
+	if a.Ref != "refs/work/archive/feature/login/20260301-093000" {
+		t.Errorf("Ref = %q", a.Ref)
+	}
+	if a.Unpushed != 1 {
+		t.Errorf("Unpushed = %d, want 1", a.Unpushed)
+	}
+	if want := []string{".env.local"}; !reflect.DeepEqual(a.Files, want) {
+		t.Errorf("Files = %v, want %v", a.Files, want)
+	}
+	if got, _ := runner.RunSimple(ctx, repo, "rev-parse", a.Ref); got != a.Commit {
+		t.Errorf("%s = %q, want %q", a.Ref, got, a.Commit)
+	}
+
+	// The files may hold secrets, so only the owner can read them
+	archiveDir, _ := GetArchiveDir()
+	for _, name := range []string{archiveDir, filepath.Join(archiveDir, a.ID+".json"), filepath.Join(archiveDir, a.ID+".tar.gz")} {
+		if info, err := os.Stat(name); err != nil {
+			t.Error(err)
+		} else if info.Mode().Perm()&0077 != 0 {
+			t.Errorf("%s has mode %v, want no access for group and others", name, info.Mode().Perm())
+		}
+	}
+
+	// A second archive in the same second gets its own ID and ref
+	b, err := Create(ctx, runner, opts, now)
+	if err != nil {
+		t.Fatalf("second Create: %v", err)
+	}
+	if b.ID != a.ID+"-2" || b.Ref != a.Ref+"-2" {
+		t.Errorf("second archive = %q, %q", b.ID, b.Ref)
+	}
+
+	loaded, err := Load(a.ID)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Commit != a.Commit || loaded.Metadata == nil || loaded.Metadata.Source != "PROJ-1" {
+		t.Errorf("Load = %+v", loaded)
+	}
+	list, err := List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("List = %v, %v", list, err)
+	}
+
+	dest := t.TempDir()
+	if err := loaded.Extract(dest); err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, ".env.local")); err != nil || string(data) != "TOKEN=secret\n" {
+		t.Errorf(".env.local = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "build")); !os.IsNotExist(err) {
+		t.Errorf("build/ was extracted")
+	}
+
+	if err := Delete(ctx, runner, loaded); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := Load(a.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load after Delete = %v, want ErrNotFound", err)
+	}
+	if _, err := runner.Run(ctx, repo, "rev-parse", "--verify", "--quiet", a.Ref); err == nil {
+		t.Errorf("%s still exists", a.Ref)
+	}
+}
+
+func TestLoad_RejectsPaths(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := Load("../config"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(../config) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMoveFolder(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	now := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	var moved []string
+	for range 2 {
+		folder := filepath.Join(t.TempDir(), "feature", "old")
+		writeFile(t, filepath.Join(folder, "notes.txt"), "keep me")
+		dest, err := MoveFolder("acme/api", "feature/old", folder, now)
+		if err != nil {
+			t.Fatalf("MoveFolder failed: %v", err)
+		}
+		if _, err := os.Stat(folder); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", folder)
+		}
+		if data, err := os.ReadFile(filepath.Join(dest, "notes.txt")); err != nil || string(data) != "keep me" {
+			t.Errorf("moved notes.txt = %q, %v", data, err)
+		}
+		moved = append(moved, filepath.Base(dest))
+	}
+
+	want := []string{"acme-api-feature-old-20260301-093000", "acme-api-feature-old-20260301-093000-2"}
+	if !reflect.DeepEqual(moved, want) {
+		t.Errorf("moved to %v, want %v", moved, want)
+	}
+	if archives, err := List(); err != nil || len(archives) != 0 {
+		t.Errorf("List() = %v, %v; want no archives", archives, err)
+	}
+}
+
+func commit(t *testing.T, runner *gitexec.Runner, repo, file, content string) {
+	t.Helper()
+	writeFile(t, filepath.Join(repo, file), content)
+	mustRun(t, runner, repo, "add", file)
+	mustRun(t, runner, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "add "+file)
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustRun(t *testing.T, runner *gitexec.Runner, workDir string, args ...string) {
+	t.Helper()
+	if _, err := runner.Run(context.Background(), workDir, args...); err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+}
@@ -16,10 +16,27 @@ var (
 	metadataBucket = []byte("metadata")
 )
 
-// RepoCache stores repository names with metadata
+// RepoEntry identifies a repository by forge host and owning org
+type RepoEntry struct {
+	Host  string `json:"host"`
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+}
+
+// RepoCache stores repositories with metadata
 type RepoCache struct {
-	Repos     []string  `json:"repos"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Entries   []RepoEntry `json:"entries"`
+	Repos     []string    `json:"repos,omitempty"` // legacy: bare github.com names
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// entries returns the cached repositories, converting legacy bare names to github.com entries
+func (c RepoCache) entries() []RepoEntry {
+	entries := append([]RepoEntry(nil), c.Entries...)
+	for _, name := range c.Repos {
+		entries = append(entries, RepoEntry{Host: "github.com", Name: name})
+	}
+	return entries
 }
 
 // BranchCache stores branches for a specific repository with metadata
@@ -82,6 +99,9 @@ func openDB() (*bolt.DB, error) {
 		if _, err := tx.CreateBucketIfNotExists(metadataBucket); err != nil {
 			return err
 		}
+		if _, err := tx.CreateBucketIfNotExists(worktreeBucket); err != nil {
+			return err
+		}
 		return nil
 	})
 	if err != nil {
@@ -93,7 +113,7 @@ func openDB() (*bolt.DB, error) {
 }
 
 // SaveRepoCache saves the repository list to the cache database
-func SaveRepoCache(repos []string) error {
+func SaveRepoCache(repos []RepoEntry) error {
 	db, err := openDB()
 	if err != nil {
 		return err
@@ -101,7 +121,7 @@ func SaveRepoCache(repos []string) error {
 	defer db.Close()
 
 	cache := RepoCache{
-		Repos:     repos,
+		Entries:   repos,
 		UpdatedAt: time.Now(),
 	}
 
@@ -122,7 +142,7 @@ func SaveRepoCache(repos []string) error {
 }
 
 // LoadRepoCache loads the repository list from the cache database
-func LoadRepoCache() ([]string, error) {
+func LoadRepoCache() ([]RepoEntry, error) {
 	db, err := openDB()
 	if err != nil {
 		return nil, err
@@ -142,7 +162,7 @@ func LoadRepoCache() ([]string, error) {
 		return nil, fmt.Errorf("failed to load repo cache: %w", err)
 	}
 
-	return cache.Repos, nil
+	return cache.entries(), nil
 }
 
 // SaveBranchCache saves the branch list for a repository to the cache database
@@ -229,7 +249,7 @@ func GetCacheStats() (map[string]interface{}, error) {
 		data := repoBkt.Get([]byte("all"))
 		if data != nil {
 			if err := json.Unmarshal(data, &repoCache); err == nil {
-				stats["repo_count"] = len(repoCache.Repos)
+				stats["repo_count"] = len(repoCache.entries())
 				stats["repos_updated_at"] = repoCache.UpdatedAt
 			}
 		}
@@ -242,6 +262,7 @@ func GetCacheStats() (map[string]interface{}, error) {
 			return nil
 		})
 		stats["cached_repos_with_branches"] = branchCount
+		stats["worktree_records"] = tx.Bucket(worktreeBucket).Stats().KeyN
 
 		return nil
 	})
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// worktreeBucket holds a WorktreeMeta per worktree path. Unlike the other buckets it
+// is not a cache: the records cannot be fetched again.
+var worktreeBucket = []byte("worktrees")
+
+// WorktreeMeta records why a worktree exists and when it was last used.
+type WorktreeMeta struct {
+	Path       string    `json:"path"`
+	Container  string    `json:"container"`
+	Branch     string    `json:"branch"`
+	Source     string    `json:"source,omitempty"`   // Issue, ticket or pull request URL
+	BaseRef    string    `json:"base_ref,omitempty"` // Ref a new branch was started from
+	Command    string    `json:"command,omitempty"`  // Command line that created the worktree
+	CreatedAt  time.Time `json:"created_at"`
+	LastOpened time.Time `json:"last_opened"`
+	Published  bool      `json:"published,omitempty"` // origin/<branch> was seen, so the branch was pushed
+}
+
+// Unpublished reports whether the worktree's branch was created by work, rather
+// than checked out from origin, and has not been seen on origin since: a missing
+// origin/<branch> means it was never pushed, not that it was deleted.
+func (m *WorktreeMeta) Unpublished() bool {
+	return m.BaseRef != "" && m.BaseRef != "origin/"+m.Branch && !m.Published
+}
+
+// SaveWorktreeMeta stores meta under meta.Path, replacing an earlier record.
+func SaveWorktreeMeta(meta WorktreeMeta) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		return putWorktreeMeta(tx.Bucket(worktreeBucket), meta)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save worktree metadata: %w", err)
+	}
+	return nil
+}
+
+// TouchWorktree sets the last-opened time of the worktree at path to now. Worktrees
+// without a record get one with only the path, container and branch.
+func TouchWorktree(path, container, branch string) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(worktreeBucket)
+		meta := WorktreeMeta{Path: path, Container: container, Branch: branch}
+		if data := b.Get([]byte(path)); data != nil {
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return err
+			}
+		}
+		meta.LastOpened = time.Now()
+		return putWorktreeMeta(b, meta)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update worktree metadata: %w", err)
+	}
+	return nil
+}
+
+// MarkWorktreePublished records that the branch of the worktree at path was seen on
+// origin. Worktrees without a record are left alone.
+func MarkWorktreePublished(path string) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(worktreeBucket)
+		data := b.Get([]byte(path))
+		if data == nil {
+			return nil
+		}
+		var meta WorktreeMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return err
+		}
+		meta.Published = true
+		return putWorktreeMeta(b, meta)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update worktree metadata: %w", err)
+	}
+	return nil
+}
+
+// LoadWorktreeMeta returns every worktree record keyed by path.
+func LoadWorktreeMeta() (map[string]WorktreeMeta, error) {
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	metas := make(map[string]WorktreeMeta)
+	err = db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(worktreeBucket).ForEach(func(k, v []byte) error {
+			var meta WorktreeMeta
+			if err := json.Unmarshal(v, &meta); err != nil {
+				return nil // Skip records from an incompatible version
+			}
+			metas[string(k)] = meta
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load worktree metadata: %w", err)
+	}
+	return metas, nil
+}
+
+// DeleteWorktreeMeta removes the records of the worktrees at paths.
+func DeleteWorktreeMeta(paths ...string) error {
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(worktreeBucket)
+		for _, path := range paths {
+			if err := b.Delete([]byte(path)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete worktree metadata: %w", err)
+	}
+	return nil
+}
+
+func putWorktreeMeta(b *bolt.Bucket, meta WorktreeMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(meta.Path), data)
+}
+
+// RankRecent orders names by their last-opened time, most recent first. Names
+// without a time keep their relative order after the others.
+func RankRecent(names []string, lastOpened map[string]time.Time) []string {
+	ranked := append([]string(nil), names...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return lastOpened[ranked[i]].After(lastOpened[ranked[j]])
+	})
+	return ranked
+}
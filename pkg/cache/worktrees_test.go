@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorktreeMeta(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	created := time.Now().Add(-time.Hour).Truncate(time.Second)
+	meta := WorktreeMeta{
+		Path:      "/git/api/feat",
+		Container: "/git/api",
+		Branch:    "feat",
+		Source:    "https://github.com/acme/api/issues/42",
+		BaseRef:   "origin/main",
+		Command:   "work checkout branch #42",
+		CreatedAt: created,
+	}
+	if err := SaveWorktreeMeta(meta); err != nil {
+		t.Fatalf("SaveWorktreeMeta failed: %v", err)
+	}
+	if err := TouchWorktree("/git/api/feat", "/git/api", "feat"); err != nil {
+		t.Fatalf("TouchWorktree failed: %v", err)
+	}
+	// Worktrees created outside work get a record on first use
+	if err := TouchWorktree("/git/api/fix", "/git/api", "fix"); err != nil {
+		t.Fatalf("TouchWorktree failed: %v", err)
+	}
+
+	metas, err := LoadWorktreeMeta()
+	if err != nil {
+		t.Fatalf("LoadWorktreeMeta failed: %v", err)
+	}
+	got := metas["/git/api/feat"]
+	if got.Source != meta.Source || got.BaseRef != meta.BaseRef || !got.CreatedAt.Equal(created) {
+		t.Errorf("record not kept: %+v", got)
+	}
+	if time.Since(got.LastOpened) > time.Minute {
+		t.Errorf("LastOpened not updated: %v", got.LastOpened)
+	}
+	if fix := metas["/git/api/fix"]; fix.Branch != "fix" || !fix.CreatedAt.IsZero() {
+		t.Errorf("unexpected record: %+v", fix)
+	}
+
+	if err := DeleteWorktreeMeta("/git/api/feat", "/git/api/missing"); err != nil {
+		t.Fatalf("DeleteWorktreeMeta failed: %v", err)
+	}
+	if metas, _ := LoadWorktreeMeta(); len(metas) != 1 {
+		t.Errorf("expected one record after delete, got %v", metas)
+	}
+}
+
+func TestWorktreeMeta_Unpublished(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		name string
+		meta WorktreeMeta
+		want bool
+	}{
+		{name: "new branch never pushed", meta: WorktreeMeta{Path: "/git/api/new", Branch: "new", BaseRef: "origin/main"}, want: true},
+		{name: "new branch from --from", meta: WorktreeMeta{Path: "/git/api/fix", Branch: "fix", BaseRef: "release/1.2"}, want: true},
+		{name: "new branch pushed", meta: WorktreeMeta{Path: "/git/api/pushed", Branch: "pushed", BaseRef: "origin/main", Published: true}},
+		{name: "remote branch checked out", meta: WorktreeMeta{Path: "/git/api/theirs", Branch: "theirs", BaseRef: "origin/theirs"}},
+		{name: "existing local branch", meta: WorktreeMeta{Path: "/git/api/local", Branch: "local"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.meta.Unpublished(); got != tt.want {
+				t.Errorf("Unpublished() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A checkout --create branch is unpublished until origin/<branch> is seen
+	meta := WorktreeMeta{Path: "/git/api/new", Container: "/git/api", Branch: "new", BaseRef: "origin/main"}
+	if err := SaveWorktreeMeta(meta); err != nil {
+		t.Fatalf("SaveWorktreeMeta failed: %v", err)
+	}
+	if err := MarkWorktreePublished("/git/api/new"); err != nil {
+		t.Fatalf("MarkWorktreePublished failed: %v", err)
+	}
+	if err := MarkWorktreePublished("/git/api/unknown"); err != nil {
+		t.Fatalf("MarkWorktreePublished without a record failed: %v", err)
+	}
+	metas, err := LoadWorktreeMeta()
+	if err != nil {
+		t.Fatalf("LoadWorktreeMeta failed: %v", err)
+	}
+	if got := metas["/git/api/new"]; got.Unpublished() || got.BaseRef != "origin/main" {
+		t.Errorf("record after MarkWorktreePublished = %+v", got)
+	}
+	if _, ok := metas["/git/api/unknown"]; ok {
+		t.Error("MarkWorktreePublished created a record")
+	}
+}
+
+func TestRankRecent(t *testing.T) {
+	now := time.Now()
+	names := []string{"api", "web", "cli", "docs"}
+	lastOpened := map[string]time.Time{
+		"cli": now.Add(-time.Hour),
+		"web": now,
+	}
+
+	want := []string{"web", "cli", "api", "docs"}
+	if got := RankRecent(names, lastOpened); !reflect.DeepEqual(got, want) {
+		t.Errorf("RankRecent() = %v, want %v", got, want)
+	}
+	if names[0] != "api" {
+		t.Error("RankRecent modified its input")
+	}
+}
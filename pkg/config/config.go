@@ -11,13 +11,114 @@ import (
 
 // Config holds all configuration settings
 type Config struct {
-	DefaultGitFolder   string   `mapstructure:"default_git_folder" json:"default_git_folder"`
-	PreferredOrgs      []string `mapstructure:"preferred_orgs" json:"preferred_orgs"`
-	PreferredIDE       string   `mapstructure:"preferred_ide" json:"preferred_ide"`
-	CheckoutBaseBranch string   `mapstructure:"checkout_base_branch" json:"checkout_base_branch"`
-	CacheTTL           string   `mapstructure:"cache_ttl" json:"cache_ttl"` // Duration string like "5m"
+	DefaultGitFolder   string        `mapstructure:"default_git_folder" json:"default_git_folder"`
+	PreferredOrgs      []string      `mapstructure:"preferred_orgs" json:"preferred_orgs"`
+	PreferredIDE       string        `mapstructure:"preferred_ide" json:"preferred_ide"`
+	CheckoutBaseBranch string        `mapstructure:"checkout_base_branch" json:"checkout_base_branch"`
+	CacheTTL           string        `mapstructure:"cache_ttl" json:"cache_ttl"`     // Duration string like "5m"
+	RepoLayout         string        `mapstructure:"repo_layout" json:"repo_layout"` // "flat" (<repo>) or "org" (<org>/<repo>) for github.com repos
+	Forges             []ForgeConfig `mapstructure:"forges" json:"forges"`
+
+	// WorktreeDirTemplate is a text/template naming the folder of a new worktree,
+	// e.g. "{{.Branch | slug}}" for feature-login instead of feature/login
+	WorktreeDirTemplate string `mapstructure:"worktree_dir_template" json:"worktree_dir_template"`
+	// ContainerLayout is how new clones are laid out: ContainerLayoutMain or ContainerLayoutBare
+	ContainerLayout string `mapstructure:"container_layout" json:"container_layout"`
+	// IssueBranchTemplate is a text/template naming branches created for issues,
+	// e.g. "{{.Number}}-{{.Title | slug | lower}}"
+	IssueBranchTemplate string `mapstructure:"issue_branch_template" json:"issue_branch_template"`
+	// Trackers resolve Jira and Linear ticket keys given to 'checkout branch'
+	Trackers []TrackerConfig `mapstructure:"trackers" json:"trackers"`
+	// Cleanup holds the policies applied by 'work cleanup run --policy'
+	Cleanup CleanupPolicy `mapstructure:"cleanup" json:"cleanup"`
+	// MaxParallel is how many repositories 'sync', 'status' and 'cleanup' work on at once
+	MaxParallel int `mapstructure:"max_parallel" json:"max_parallel"`
+	// RepoTimeout is a duration string like "2m" limiting the time spent on each of them
+	RepoTimeout string `mapstructure:"repo_timeout" json:"repo_timeout"`
+}
+
+// ForgeConfig selects the git hosting backend used for a host.
+// github.com is always available as GitHub and does not need an entry.
+type ForgeConfig struct {
+	Host     string   `mapstructure:"host" json:"host" yaml:"host"`
+	Type     string   `mapstructure:"type" json:"type" yaml:"type"`                                    // "github", "gitlab", "gitea"/"forgejo"; detected when empty
+	APIURL   string   `mapstructure:"api_url" json:"api_url,omitempty" yaml:"api_url,omitempty"`       // Defaults to the backend's standard API path on Host
+	TokenEnv string   `mapstructure:"token_env" json:"token_env,omitempty" yaml:"token_env,omitempty"` // Environment variable holding the API token
+	Orgs     []string `mapstructure:"orgs" json:"orgs,omitempty" yaml:"orgs,omitempty"`                // Groups/orgs to list on this host
+}
+
+// CleanupPolicy selects worktrees for 'work cleanup --policy' beyond merged and deleted
+// branches. Empty fields disable a rule. ArchiveFiles applies to 'work cleanup run --archive',
+// ProtectedBranches and Repos to 'work cleanup run --delete-branches'.
+type CleanupPolicy struct {
+	InactiveFor         string        `mapstructure:"inactive_for" json:"inactive_for,omitempty" yaml:"inactive_for,omitempty"`                               // e.g. "30d": not opened or committed to for this long
+	PRClosed            bool          `mapstructure:"pr_closed" json:"pr_closed,omitempty" yaml:"pr_closed,omitempty"`                                        // The branch's pull request is closed or merged
+	MaxWorktreesPerRepo int           `mapstructure:"max_worktrees_per_repo" json:"max_worktrees_per_repo,omitempty" yaml:"max_worktrees_per_repo,omitempty"` // Keep the N most recently used
+	MinFreeDisk         string        `mapstructure:"min_free_disk" json:"min_free_disk,omitempty" yaml:"min_free_disk,omitempty"`                            // e.g. "20GB": remove least recently used below this
+	ArchiveFiles        []string      `mapstructure:"archive_files" json:"archive_files,omitempty" yaml:"archive_files,omitempty"`                            // Globs of untracked or ignored files to archive, e.g. ".env.local"
+	ProtectedBranches   []string      `mapstructure:"protected_branches" json:"protected_branches" yaml:"protected_branches"`                                 // Globs of branches never deleted, e.g. "release/*"
+	Repos               []RepoCleanup `mapstructure:"repos" json:"repos,omitempty" yaml:"repos,omitempty"`
+}
+
+// RepoCleanup holds cleanup settings for one repository, named as in 'work cleanup run <repo>'.
+type RepoCleanup struct {
+	Repo              string   `mapstructure:"repo" json:"repo" yaml:"repo"`
+	ProtectedBranches []string `mapstructure:"protected_branches" json:"protected_branches,omitempty" yaml:"protected_branches,omitempty"` // Added to the global list
+}
+
+// ProtectedBranchesFor returns the globs of branches of repo that cleanup never deletes.
+// The default branch is always protected and is not included.
+func (c CleanupPolicy) ProtectedBranchesFor(repo string) []string {
+	patterns := append([]string(nil), c.ProtectedBranches...)
+	for _, r := range c.Repos {
+		if r.Repo == repo {
+			patterns = append(patterns, r.ProtectedBranches...)
+		}
+	}
+	return patterns
 }
 
+// TrackerConfig connects an issue tracker whose ticket keys (PROJ-123) can be checked out.
+type TrackerConfig struct {
+	Type       string   `mapstructure:"type" json:"type" yaml:"type"`                                          // "jira" or "linear"
+	URL        string   `mapstructure:"url" json:"url,omitempty" yaml:"url,omitempty"`                         // Jira site, or the Linear API endpoint (defaults to Linear's)
+	Email      string   `mapstructure:"email" json:"email,omitempty" yaml:"email,omitempty"`                   // Jira Cloud account; empty for a personal access token
+	TokenEnv   string   `mapstructure:"token_env" json:"token_env,omitempty" yaml:"token_env,omitempty"`       // Defaults to JIRA_API_TOKEN or LINEAR_API_KEY
+	Projects   []string `mapstructure:"projects" json:"projects,omitempty" yaml:"projects,omitempty"`          // Project/team keys; empty handles any key
+	InProgress string   `mapstructure:"in_progress" json:"in_progress,omitempty" yaml:"in_progress,omitempty"` // Status set on checkout, "In Progress" by default
+}
+
+// Repository folder layouts under default_git_folder.
+const (
+	RepoLayoutFlat = "flat" // github.com repos at <repo>, same-named repos from other orgs at <org>/<repo>
+	RepoLayoutOrg  = "org"  // github.com repos always at <org>/<repo>
+)
+
+// Container layouts, i.e. where the repository itself lives inside a repo folder.
+const (
+	ContainerLayoutMain = "main" // Full clone in <repo>/main, branches as worktrees next to it
+	ContainerLayoutBare = "bare" // Bare repository in <repo>/.bare, every branch (default included) a worktree
+)
+
+// DefaultWorktreeDirTemplate names worktree folders after their branch, so
+// feature/login is checked out in <repo>/feature/login.
+const DefaultWorktreeDirTemplate = "{{.Branch}}"
+
+// DefaultProtectedBranches are the branches 'work cleanup run --delete-branches' keeps
+// unless cleanup.protected_branches is set.
+var DefaultProtectedBranches = []string{"release/*"}
+
+// DefaultMaxParallel and DefaultRepoTimeout bound the work on repositories in
+// 'sync', 'status' and 'cleanup'.
+const (
+	DefaultMaxParallel = 8
+	DefaultRepoTimeout = "2m"
+)
+
+// DefaultIssueBranchTemplate names issue branches after the issue number and the
+// start of its title, e.g. 42-Crash-on-login.
+const DefaultIssueBranchTemplate = "{{.Number}}-{{.Title | slug | trunc 40}}"
+
 var (
 	configFileName = "config"
 	configFileType = "yaml"
@@ -69,6 +170,13 @@ func setDefaults() {
 	viper.SetDefault("preferred_ide", "none") // Options: "vscode", "cursor", "none"
 	viper.SetDefault("checkout_base_branch", "main")
 	viper.SetDefault("cache_ttl", "5m") // 5 minutes
+	viper.SetDefault("repo_layout", RepoLayoutFlat)
+	viper.SetDefault("worktree_dir_template", DefaultWorktreeDirTemplate)
+	viper.SetDefault("issue_branch_template", DefaultIssueBranchTemplate)
+	viper.SetDefault("container_layout", ContainerLayoutMain)
+	viper.SetDefault("cleanup.protected_branches", DefaultProtectedBranches)
+	viper.SetDefault("max_parallel", DefaultMaxParallel)
+	viper.SetDefault("repo_timeout", DefaultRepoTimeout)
 }
 
 // GetConfigDir returns the configuration directory path
@@ -118,6 +226,15 @@ func Save(cfg *Config) error {
 	viper.Set("preferred_ide", cfg.PreferredIDE)
 	viper.Set("checkout_base_branch", cfg.CheckoutBaseBranch)
 	viper.Set("cache_ttl", cfg.CacheTTL)
+	viper.Set("repo_layout", cfg.RepoLayout)
+	viper.Set("forges", cfg.Forges)
+	viper.Set("trackers", cfg.Trackers)
+	viper.Set("cleanup", cfg.Cleanup)
+	viper.Set("worktree_dir_template", cfg.WorktreeDirTemplate)
+	viper.Set("issue_branch_template", cfg.IssueBranchTemplate)
+	viper.Set("container_layout", cfg.ContainerLayout)
+	viper.Set("max_parallel", cfg.MaxParallel)
+	viper.Set("repo_timeout", cfg.RepoTimeout)
 
 	return viper.WriteConfig()
 }
@@ -0,0 +1,205 @@
+// Package errs defines the typed errors returned by work commands and the
+// process exit code each kind maps to, so scripts can tell failures apart.
+package errs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+// Kind classifies an error. Each kind has a distinct exit code.
+type Kind int
+
+const (
+	// Failure is any error without a more specific kind.
+	Failure Kind = iota
+	// Usage is an invalid flag, argument or flag combination.
+	Usage
+	// NotConfigured means required configuration (e.g. default_git_folder) is missing.
+	NotConfigured
+	// RepoNotFound means a repository could not be found locally or on the forges.
+	RepoNotFound
+	// DirtyWorktree means a worktree has uncommitted changes that block the operation.
+	DirtyWorktree
+	// ForgeAuth means the forge rejected or is missing credentials.
+	ForgeAuth
+	// Conflict means the operation collides with existing state: a folder on
+	// another branch, a rebase conflict, a protected branch.
+	Conflict
+)
+
+// Exit codes returned by the work binary.
+const (
+	ExitOK            = 0
+	ExitFailure       = 1
+	ExitUsage         = 2
+	ExitNotConfigured = 3
+	ExitRepoNotFound  = 4
+	ExitDirtyWorktree = 5
+	ExitForgeAuth     = 6
+	ExitConflict      = 7
+)
+
+// ExitCode returns the process exit code for the kind.
+func (k Kind) ExitCode() int {
+	switch k {
+	case Usage:
+		return ExitUsage
+	case NotConfigured:
+		return ExitNotConfigured
+	case RepoNotFound:
+		return ExitRepoNotFound
+	case DirtyWorktree:
+		return ExitDirtyWorktree
+	case ForgeAuth:
+		return ExitForgeAuth
+	case Conflict:
+		return ExitConflict
+	default:
+		return ExitFailure
+	}
+}
+
+// String returns the kind's name as used in structured output.
+func (k Kind) String() string {
+	switch k {
+	case Usage:
+		return "usage"
+	case NotConfigured:
+		return "not_configured"
+	case RepoNotFound:
+		return "repo_not_found"
+	case DirtyWorktree:
+		return "dirty_worktree"
+	case ForgeAuth:
+		return "forge_auth"
+	case Conflict:
+		return "conflict"
+	default:
+		return "failure"
+	}
+}
+
+// Error is an error with a kind and an optional hint telling the user how to fix it.
+type Error struct {
+	Kind Kind
+	Msg  string
+	Hint string // e.g. "Run: work config set default_git_folder ~/git"
+	Err  error  // Underlying cause, may be nil
+
+	// Reported is set when the command has already shown the failure (for
+	// example in a per-repository report); only the exit code is used.
+	Reported bool
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Msg
+	case e.Msg == "":
+		return e.Err.Error()
+	default:
+		return e.Msg + ": " + e.Err.Error()
+	}
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// New returns an error of the given kind.
+func New(kind Kind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns an error of the given kind with err as its cause.
+func Wrap(kind Kind, err error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Reported returns an error that only carries an exit code, for commands that
+// have already printed what went wrong.
+func Reported(kind Kind, format string, args ...any) *Error {
+	e := New(kind, format, args...)
+	e.Reported = true
+	return e
+}
+
+// WithHint sets the hint shown below the error message.
+func (e *Error) WithHint(format string, args ...any) *Error {
+	e.Hint = fmt.Sprintf(format, args...)
+	return e
+}
+
+// KindOf returns the first kind other than Failure in err's chain, so a Failure
+// wrapping e.g. a DirtyWorktree error is a DirtyWorktree. Untyped errors wrapping
+// forge or workspace sentinels get the matching kind; everything else is a Failure.
+func KindOf(err error) Kind {
+	if kind := typedKind(err); kind != Failure {
+		return kind
+	}
+
+	switch {
+	case errors.Is(err, forge.ErrUnauthorized):
+		return ForgeAuth
+	case errors.Is(err, forge.ErrRefExists), errors.Is(err, forge.ErrProtectedBranch), errors.Is(err, workspace.ErrDirInUse):
+		return Conflict
+	case errors.Is(err, workspace.ErrUncommittedChanges):
+		return DirtyWorktree
+	case errors.Is(err, workspace.ErrNotConfigured):
+		return NotConfigured
+	case errors.Is(err, workspace.ErrNotInContainer):
+		return RepoNotFound
+	default:
+		return Failure
+	}
+}
+
+// typedKind returns the first kind other than Failure of an *Error in err's chain,
+// following joined errors too, or Failure if there is none.
+func typedKind(err error) Kind {
+	switch e := err.(type) {
+	case nil:
+		return Failure
+	case *Error:
+		if e.Kind != Failure {
+			return e.Kind
+		}
+		return typedKind(e.Err)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			if kind := typedKind(inner); kind != Failure {
+				return kind
+			}
+		}
+		return Failure
+	default:
+		return typedKind(errors.Unwrap(err))
+	}
+}
+
+// HintOf returns the first hint found in err's chain.
+func HintOf(err error) string {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Hint != "" {
+			return e.Hint
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
+
+// IsReported reports whether err has already been shown to the user.
+func IsReported(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Reported
+}
+
+// ExitCode returns the process exit code for err; 0 when err is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	return KindOf(err).ExitCode()
+}
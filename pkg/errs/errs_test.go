@@ -0,0 +1,91 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: ExitOK},
+		{name: "plain error", err: errors.New("boom"), want: ExitFailure},
+		{name: "usage", err: New(Usage, "bad flag"), want: ExitUsage},
+		{name: "not configured", err: New(NotConfigured, "missing"), want: ExitNotConfigured},
+		{name: "repo not found", err: New(RepoNotFound, "no repo"), want: ExitRepoNotFound},
+		{name: "dirty worktree", err: New(DirtyWorktree, "changes"), want: ExitDirtyWorktree},
+		{name: "conflict", err: New(Conflict, "exists"), want: ExitConflict},
+		{name: "wrapped typed error", err: fmt.Errorf("checkout: %w", New(RepoNotFound, "no repo")), want: ExitRepoNotFound},
+		{name: "forge unauthorized", err: fmt.Errorf("list repos: %w", forge.ErrUnauthorized), want: ExitForgeAuth},
+		{name: "forge protected branch", err: fmt.Errorf("create ref: %w", forge.ErrProtectedBranch), want: ExitConflict},
+		{name: "workspace not configured", err: fmt.Errorf("discover: %w", workspace.ErrNotConfigured), want: ExitNotConfigured},
+		{name: "not in container", err: workspace.ErrNotInContainer, want: ExitRepoNotFound},
+		{name: "uncommitted changes", err: fmt.Errorf("convert: %w", workspace.ErrUncommittedChanges), want: ExitDirtyWorktree},
+		{name: "worktree folder in use", err: fmt.Errorf("%w: 'api/feature'", workspace.ErrDirInUse), want: ExitConflict},
+		{name: "failure wrapping forge error", err: Wrap(Failure, forge.ErrUnauthorized, "create PR"), want: ExitForgeAuth},
+		{name: "kind wins over cause", err: Wrap(RepoNotFound, forge.ErrUnauthorized, "lookup"), want: ExitRepoNotFound},
+		{name: "failure wrapping typed error", err: Wrap(Failure, New(DirtyWorktree, "changes"), "convert"), want: ExitDirtyWorktree},
+		{name: "failures wrapping typed error", err: fmt.Errorf("sync: %w", Wrap(Failure, fmt.Errorf("pull: %w", New(Conflict, "diverged")), "")), want: ExitConflict},
+		{name: "joined typed error", err: errors.Join(errors.New("boom"), New(RepoNotFound, "no repo")), want: ExitRepoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_Message(t *testing.T) {
+	cause := errors.New("exit status 128")
+
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{name: "message only", err: New(Failure, "clone failed"), want: "clone failed"},
+		{name: "message and cause", err: Wrap(Failure, cause, "clone %s", "api"), want: "clone api: exit status 128"},
+		{name: "cause only", err: &Error{Err: cause}, want: "exit status 128"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !errors.Is(Wrap(Failure, cause, "clone"), cause) {
+		t.Error("expected wrapped cause to be found by errors.Is")
+	}
+}
+
+func TestHintAndReported(t *testing.T) {
+	err := fmt.Errorf("checkout: %w", New(NotConfigured, "default_git_folder not configured").
+		WithHint("Run: work config set default_git_folder ~/git"))
+
+	if got := HintOf(err); got != "Run: work config set default_git_folder ~/git" {
+		t.Errorf("HintOf() = %q", got)
+	}
+	if HintOf(errors.New("plain")) != "" {
+		t.Error("expected no hint for a plain error")
+	}
+
+	if IsReported(err) {
+		t.Error("expected error not to be reported")
+	}
+	if !IsReported(Reported(Failure, "2 repositories failed")) {
+		t.Error("expected reported error")
+	}
+}
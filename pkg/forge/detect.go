@@ -0,0 +1,83 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// wellKnownHosts maps public forges to their backend so they never need probing.
+var wellKnownHosts = map[string]string{
+	"github.com":   KindGitHub,
+	"gitlab.com":   KindGitLab,
+	"codeberg.org": KindGitea,
+	"gitea.com":    KindGitea,
+}
+
+// DetectKind determines which backend serves host, typically the Host of a
+// repository's origin remote. Well-known hosts are answered directly; anything
+// else is identified by probing the version endpoints of each API over HTTPS.
+func DetectKind(ctx context.Context, host string) (string, error) {
+	if kind, ok := wellKnownHosts[host]; ok {
+		return kind, nil
+	}
+	return detectKindAt(ctx, &http.Client{Timeout: 5 * time.Second}, "https://"+host)
+}
+
+// detectKindAt probes baseURL for the Gitea/Forgejo, GitLab and GitHub Enterprise APIs in turn.
+func detectKindAt(ctx context.Context, client *http.Client, baseURL string) (string, error) {
+	probes := []struct {
+		kind   string
+		path   string
+		accept func(status int, body []byte) bool
+	}{
+		{
+			// Gitea and Forgejo answer unauthenticated with {"version": "..."}
+			kind: KindGitea,
+			path: "/api/v1/version",
+			accept: func(status int, body []byte) bool {
+				var v struct {
+					Version string `json:"version"`
+				}
+				return status == http.StatusOK && json.Unmarshal(body, &v) == nil && v.Version != ""
+			},
+		},
+		{
+			// GitLab requires authentication for /version, so a 401 still identifies it
+			kind: KindGitLab,
+			path: "/api/v4/version",
+			accept: func(status int, body []byte) bool {
+				return status == http.StatusOK || status == http.StatusUnauthorized
+			},
+		},
+		{
+			kind: KindGitHub,
+			path: "/api/v3/meta",
+			accept: func(status int, body []byte) bool {
+				return status == http.StatusOK
+			},
+		},
+	}
+
+	for _, probe := range probes {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+probe.path, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("probing %s: %w", baseURL, err)
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+
+		if probe.accept(resp.StatusCode, body) {
+			return probe.kind, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not detect forge type for %s", baseURL)
+}
@@ -0,0 +1,194 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+var _ Forge = (*Fake)(nil)
+
+// Fake is an in-memory Forge for tests. Populate Repos and Branches before use;
+// created refs and pull requests are recorded so tests can assert on them.
+type Fake struct {
+	mu sync.Mutex
+
+	HostName string
+	Repos    map[string][]Repo        // keyed by owner
+	Branches map[string][]Branch      // keyed by "owner/repo"
+	Releases map[string]*Release      // keyed by "owner/repo"
+	PRs      map[string][]PullRequest // keyed by "owner/repo"
+	Issues   map[string][]Issue       // keyed by "owner/repo"
+	Err      error                    // returned by every call when set
+}
+
+// NewFake returns an empty Fake for host.
+func NewFake(host string) *Fake {
+	return &Fake{
+		HostName: host,
+		Repos:    make(map[string][]Repo),
+		Branches: make(map[string][]Branch),
+		Releases: make(map[string]*Release),
+		PRs:      make(map[string][]PullRequest),
+		Issues:   make(map[string][]Issue),
+	}
+}
+
+// Name returns "fake".
+func (f *Fake) Name() string { return "fake" }
+
+// Host returns the configured host name.
+func (f *Fake) Host() string { return f.HostName }
+
+// ListRepos returns the repositories registered for owner.
+func (f *Fake) ListRepos(ctx context.Context, owner string) ([]Repo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	repos, ok := f.Repos[owner]
+	if !ok {
+		return nil, fmt.Errorf("owner %s: %w", owner, ErrNotFound)
+	}
+	return append([]Repo(nil), repos...), nil
+}
+
+// GetRepo returns a registered repository.
+func (f *Fake) GetRepo(ctx context.Context, owner, repo string) (*Repo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	for _, r := range f.Repos[owner] {
+		if r.Name == repo {
+			result := r
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("repo %s/%s: %w", owner, repo, ErrNotFound)
+}
+
+// ListBranches returns the registered branches.
+func (f *Fake) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return append([]Branch(nil), f.Branches[owner+"/"+repo]...), nil
+}
+
+// GetBranchSHA returns the SHA of a registered branch.
+func (f *Fake) GetBranchSHA(ctx context.Context, owner, repo, branch string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return "", f.Err
+	}
+	for _, b := range f.Branches[owner+"/"+repo] {
+		if b.Name == branch {
+			return b.SHA, nil
+		}
+	}
+	return "", fmt.Errorf("branch %s: %w", branch, ErrNotFound)
+}
+
+// CreateRef registers a new branch.
+func (f *Fake) CreateRef(ctx context.Context, owner, repo, branch, sha string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	key := owner + "/" + repo
+	for _, b := range f.Branches[key] {
+		if b.Name == branch {
+			return ErrRefExists
+		}
+	}
+	f.Branches[key] = append(f.Branches[key], Branch{Name: branch, SHA: sha})
+	return nil
+}
+
+// CreatePR records a new open pull request.
+func (f *Fake) CreatePR(ctx context.Context, owner, repo string, pr NewPullRequest) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	key := owner + "/" + repo
+	created := PullRequest{
+		Number: len(f.PRs[key]) + 1,
+		Title:  pr.Title,
+		State:  "open",
+		URL:    fmt.Sprintf("https://%s/%s/pull/%d", f.HostName, key, len(f.PRs[key])+1),
+		Head:   pr.Head,
+		Base:   pr.Base,
+	}
+	f.PRs[key] = append(f.PRs[key], created)
+	return &created, nil
+}
+
+// GetPR returns a registered pull request by number.
+func (f *Fake) GetPR(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	for _, pr := range f.PRs[owner+"/"+repo] {
+		if pr.Number == number {
+			result := pr
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("pull request #%d: %w", number, ErrNotFound)
+}
+
+// PullRef returns refs/pull/<number>/head.
+func (f *Fake) PullRef(number int) string { return fmt.Sprintf("refs/pull/%d/head", number) }
+
+// FindPR returns the registered pull request for branch, preferring open ones.
+func (f *Fake) FindPR(ctx context.Context, owner, repo, branch string) (*PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var prs []PullRequest
+	for _, pr := range f.PRs[owner+"/"+repo] {
+		if pr.Head == branch {
+			prs = append(prs, pr)
+		}
+	}
+	return pickPR(prs), nil
+}
+
+// GetIssue returns a registered issue by number.
+func (f *Fake) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	for _, issue := range f.Issues[owner+"/"+repo] {
+		if issue.Number == number {
+			result := issue
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("issue #%d: %w", number, ErrNotFound)
+}
+
+// GetLatestRelease returns the registered release, or nil.
+func (f *Fake) GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Releases[owner+"/"+repo], nil
+}
@@ -0,0 +1,134 @@
+package forge
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNotFound is returned when the requested resource does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrRefExists is returned by CreateRef when the branch already exists.
+	ErrRefExists = errors.New("reference already exists")
+
+	// ErrUnauthorized is returned when the forge rejects the credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrProtectedBranch is returned by CreateRef when the branch name matches a protected branch rule.
+	ErrProtectedBranch = errors.New("branch name is protected")
+
+	// ErrUnsupportedHost is returned by Registry.ForHost when no backend is configured for a host.
+	ErrUnsupportedHost = errors.New("no forge configured for host")
+)
+
+// Repo describes a repository hosted on a forge.
+type Repo struct {
+	Owner         string `json:"owner"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// Branch describes a branch of a hosted repository.
+type Branch struct {
+	Name string `json:"name"`
+	SHA  string `json:"sha"`
+}
+
+// PullRequest describes a pull request (or merge request).
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"` // "open", "closed" or "merged"
+	URL    string `json:"url"`
+	Head   string `json:"head"`
+	Base   string `json:"base"`
+	// HeadSHA is the commit the head branch pointed to, e.g. when it was merged
+	HeadSHA string `json:"head_sha,omitempty"`
+}
+
+// Issue describes an issue of a hosted repository.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"` // "open" or "closed"
+	URL    string `json:"url"`
+}
+
+// NewPullRequest holds the fields needed to open a pull request.
+type NewPullRequest struct {
+	Title string
+	Body  string
+	Head  string
+	Base  string
+}
+
+// Release describes a published release.
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+}
+
+// Forge is the interface implemented by every supported git hosting service.
+// Owner is the organization, user or group that owns the repository.
+type Forge interface {
+	// Name returns the backend name, e.g. "github".
+	Name() string
+
+	// Host returns the hostname this client talks to, e.g. "github.com".
+	Host() string
+
+	// ListRepos returns all repositories owned by the given owner.
+	ListRepos(ctx context.Context, owner string) ([]Repo, error)
+
+	// GetRepo returns a single repository.
+	GetRepo(ctx context.Context, owner, repo string) (*Repo, error)
+
+	// ListBranches returns the branches of a repository.
+	ListBranches(ctx context.Context, owner, repo string) ([]Branch, error)
+
+	// GetBranchSHA returns the commit SHA the branch points to.
+	GetBranchSHA(ctx context.Context, owner, repo, branch string) (string, error)
+
+	// CreateRef creates a branch pointing at sha. Returns ErrRefExists if the branch already exists.
+	CreateRef(ctx context.Context, owner, repo, branch, sha string) error
+
+	// CreatePR opens a pull request.
+	CreatePR(ctx context.Context, owner, repo string, pr NewPullRequest) (*PullRequest, error)
+
+	// GetPR returns a pull request by number. Returns ErrNotFound if there is none.
+	GetPR(ctx context.Context, owner, repo string, number int) (*PullRequest, error)
+
+	// PullRef returns the ref under which the repository publishes the head of pull
+	// request number, e.g. refs/pull/42/head. It also exists for pull requests from forks.
+	PullRef(number int) string
+
+	// FindPR returns the pull request for a head branch: the open one if there is one,
+	// otherwise the most recent. Returns nil if the branch has none.
+	FindPR(ctx context.Context, owner, repo, branch string) (*PullRequest, error)
+
+	// GetIssue returns an issue by number. Returns ErrNotFound if there is none.
+	GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error)
+
+	// GetLatestRelease returns the latest release, or nil if the repository has none.
+	GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error)
+}
+
+// pickPR returns the open pull request among prs, or the first (most recent) one.
+func pickPR(prs []PullRequest) *PullRequest {
+	if len(prs) == 0 {
+		return nil
+	}
+	for _, pr := range prs {
+		if pr.State == "open" {
+			result := pr
+			return &result
+		}
+	}
+	result := prs[0]
+	return &result
+}
@@ -0,0 +1,271 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	giteaPerPage = 50
+
+	// giteaMaxPRPages bounds FindPR, which has to filter pull requests client-side
+	giteaMaxPRPages = 4
+)
+
+var _ Forge = (*Gitea)(nil)
+
+// Gitea implements Forge against the Gitea REST API (v1), which Forgejo also serves.
+type Gitea struct {
+	host   string
+	client *apiClient
+}
+
+// NewGitea creates a Gitea/Forgejo client for host. If apiURL is empty it defaults to https://<host>/api/v1.
+func NewGitea(host, apiURL, token string) *Gitea {
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://%s/api/v1", host)
+	}
+
+	client := newAPIClient(apiURL, func(req *http.Request) {
+		if token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	})
+
+	return &Gitea{host: host, client: client}
+}
+
+type giteaRepo struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+func (r giteaRepo) toRepo() Repo {
+	return Repo{
+		Owner:         r.Owner.Login,
+		Name:          r.Name,
+		FullName:      r.FullName,
+		CloneURL:      r.CloneURL,
+		SSHURL:        r.SSHURL,
+		DefaultBranch: r.DefaultBranch,
+	}
+}
+
+type giteaBranch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+type giteaPull struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	Merged  bool   `json:"merged"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p giteaPull) toPullRequest() PullRequest {
+	state := p.State
+	if p.Merged {
+		state = "merged"
+	}
+	return PullRequest{
+		Number:  p.Number,
+		Title:   p.Title,
+		State:   state,
+		URL:     p.HTMLURL,
+		Head:    p.Head.Ref,
+		Base:    p.Base.Ref,
+		HeadSHA: p.Head.SHA,
+	}
+}
+
+// Name returns "gitea".
+func (g *Gitea) Name() string { return "gitea" }
+
+// Host returns the Gitea hostname.
+func (g *Gitea) Host() string { return g.host }
+
+// ListRepos lists repositories of an organization, falling back to a user account.
+func (g *Gitea) ListRepos(ctx context.Context, owner string) ([]Repo, error) {
+	repos, err := g.listRepos(ctx, fmt.Sprintf("/orgs/%s/repos", url.PathEscape(owner)))
+	if errors.Is(err, ErrNotFound) {
+		repos, err = g.listRepos(ctx, fmt.Sprintf("/users/%s/repos", url.PathEscape(owner)))
+	}
+	return repos, err
+}
+
+func (g *Gitea) listRepos(ctx context.Context, path string) ([]Repo, error) {
+	var repos []Repo
+	for page := 1; ; page++ {
+		var batch []giteaRepo
+		if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s?limit=%d&page=%d", path, giteaPerPage, page), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, r := range batch {
+			repos = append(repos, r.toRepo())
+		}
+		if len(batch) < giteaPerPage {
+			return repos, nil
+		}
+	}
+}
+
+// GetRepo returns repository details.
+func (g *Gitea) GetRepo(ctx context.Context, owner, repo string) (*Repo, error) {
+	var r giteaRepo
+	if _, err := g.client.do(ctx, http.MethodGet, repoPath(owner, repo), nil, &r); err != nil {
+		return nil, err
+	}
+	result := r.toRepo()
+	return &result, nil
+}
+
+// ListBranches returns all branches of the repository.
+func (g *Gitea) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	var branches []Branch
+	for page := 1; ; page++ {
+		var batch []giteaBranch
+		path := fmt.Sprintf("%s/branches?limit=%d&page=%d", repoPath(owner, repo), giteaPerPage, page)
+		if _, err := g.client.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, b := range batch {
+			branches = append(branches, Branch{Name: b.Name, SHA: b.Commit.ID})
+		}
+		if len(batch) < giteaPerPage {
+			return branches, nil
+		}
+	}
+}
+
+// GetBranchSHA returns the commit SHA of a branch.
+func (g *Gitea) GetBranchSHA(ctx context.Context, owner, repo, branch string) (string, error) {
+	var b giteaBranch
+	path := fmt.Sprintf("%s/branches/%s", repoPath(owner, repo), escapeRef(branch))
+	if _, err := g.client.do(ctx, http.MethodGet, path, nil, &b); err != nil {
+		return "", err
+	}
+	if b.Commit.ID == "" {
+		return "", fmt.Errorf("empty SHA returned for branch %s", branch)
+	}
+	return b.Commit.ID, nil
+}
+
+// CreateRef creates a new branch pointing at sha.
+func (g *Gitea) CreateRef(ctx context.Context, owner, repo, branch, sha string) error {
+	body := map[string]string{
+		"new_branch_name": branch,
+		"old_ref_name":    sha,
+	}
+	_, err := g.client.do(ctx, http.MethodPost, repoPath(owner, repo)+"/branches", body, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return ErrRefExists
+	}
+	return err
+}
+
+// CreatePR opens a pull request.
+func (g *Gitea) CreatePR(ctx context.Context, owner, repo string, pr NewPullRequest) (*PullRequest, error) {
+	body := map[string]string{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	}
+	var created giteaPull
+	if _, err := g.client.do(ctx, http.MethodPost, repoPath(owner, repo)+"/pulls", body, &created); err != nil {
+		return nil, err
+	}
+	result := created.toPullRequest()
+	return &result, nil
+}
+
+// GetPR returns a pull request by number.
+func (g *Gitea) GetPR(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	var pull giteaPull
+	if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/pulls/%d", repoPath(owner, repo), number), nil, &pull); err != nil {
+		return nil, err
+	}
+	result := pull.toPullRequest()
+	return &result, nil
+}
+
+// PullRef returns refs/pull/<number>/head.
+func (g *Gitea) PullRef(number int) string { return fmt.Sprintf("refs/pull/%d/head", number) }
+
+// FindPR scans the most recently updated pull requests for one whose head is branch.
+// The Gitea API cannot filter by head branch, so only the newest few pages are searched.
+func (g *Gitea) FindPR(ctx context.Context, owner, repo, branch string) (*PullRequest, error) {
+	var prs []PullRequest
+	for page := 1; page <= giteaMaxPRPages; page++ {
+		var batch []giteaPull
+		path := fmt.Sprintf("%s/pulls?state=all&sort=recentupdate&limit=%d&page=%d", repoPath(owner, repo), giteaPerPage, page)
+		if _, err := g.client.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, p := range batch {
+			if p.Head.Ref == branch {
+				prs = append(prs, p.toPullRequest())
+			}
+		}
+		if len(batch) < giteaPerPage {
+			break
+		}
+	}
+	return pickPR(prs), nil
+}
+
+// GetIssue returns an issue by number.
+func (g *Gitea) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	var issue struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		State   string `json:"state"`
+		HTMLURL string `json:"html_url"`
+	}
+	if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/issues/%d", repoPath(owner, repo), number), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &Issue{Number: issue.Number, Title: issue.Title, State: issue.State, URL: issue.HTMLURL}, nil
+}
+
+// GetLatestRelease returns the newest published release, or nil if there is none.
+func (g *Gitea) GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	var releases []struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+		HTMLURL string `json:"html_url"`
+	}
+	path := repoPath(owner, repo) + "/releases?draft=false&pre-release=false&limit=1"
+	_, err := g.client.do(ctx, http.MethodGet, path, nil, &releases)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	return &Release{TagName: releases[0].TagName, Name: releases[0].Name, URL: releases[0].HTMLURL}, nil
+}
@@ -0,0 +1,168 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestGitea(t *testing.T, handler http.HandlerFunc) *Gitea {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token test-token" {
+			t.Errorf("expected token auth header, got %q", got)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewGitea("forgejo.example.com", server.URL, "test-token")
+}
+
+func TestGitea_GetBranchSHA(t *testing.T) {
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/me/side/branches/main" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"name":"main","commit":{"id":"deadbeef"}}`))
+	})
+
+	sha, err := g.GetBranchSHA(context.Background(), "me", "side", "main")
+	if err != nil {
+		t.Fatalf("GetBranchSHA failed: %v", err)
+	}
+	if sha != "deadbeef" {
+		t.Errorf("expected deadbeef, got %s", sha)
+	}
+}
+
+func TestGitea_CreateRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "created", status: http.StatusCreated},
+		{name: "already exists", status: http.StatusConflict, wantErr: ErrRefExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]string
+				json.NewDecoder(r.Body).Decode(&body)
+				if body["new_branch_name"] != "feature" || body["old_ref_name"] != "deadbeef" {
+					t.Errorf("unexpected body: %v", body)
+				}
+				w.WriteHeader(tt.status)
+			})
+
+			err := g.CreateRef(context.Background(), "me", "side", "feature", "deadbeef")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGitea_CreatePRMerged(t *testing.T) {
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number":3,"state":"closed","merged":true,"html_url":"https://forgejo.example.com/me/side/pulls/3"}`))
+	})
+
+	pr, err := g.CreatePR(context.Background(), "me", "side", NewPullRequest{Title: "t", Head: "feature", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if pr.State != "merged" {
+		t.Errorf("expected merged state, got %s", pr.State)
+	}
+}
+
+func TestGitea_FindPRFiltersByHead(t *testing.T) {
+	g := newTestGitea(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/me/side/pulls" || r.URL.Query().Get("state") != "all" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"number":5,"state":"open","head":{"ref":"other"}},{"number":3,"state":"closed","head":{"ref":"feature"}}]`))
+	})
+
+	pr, err := g.FindPR(context.Background(), "me", "side", "feature")
+	if err != nil {
+		t.Fatalf("FindPR failed: %v", err)
+	}
+	if pr == nil || pr.Number != 3 || pr.State != "closed" {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+}
+
+func TestDetectKindAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes map[string]int
+		body   string
+		want   string
+	}{
+		{name: "gitea", routes: map[string]int{"/api/v1/version": http.StatusOK}, body: `{"version":"1.21.0"}`, want: KindGitea},
+		{name: "gitlab unauthenticated", routes: map[string]int{"/api/v4/version": http.StatusUnauthorized}, want: KindGitLab},
+		{name: "github enterprise", routes: map[string]int{"/api/v3/meta": http.StatusOK}, body: `{}`, want: KindGitHub},
+		{name: "unknown", routes: map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status, ok := tt.routes[r.URL.Path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := detectKindAt(context.Background(), server.Client(), server.URL)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("expected detection to fail, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detection failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRegistry_DetectsUnknownHost(t *testing.T) {
+	registry := NewRegistry()
+	calls := 0
+	registry.EnableDetection(func(ctx context.Context, host string) (string, error) {
+		calls++
+		return KindGitea, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		f, err := registry.ForHost("forgejo.example.com")
+		if err != nil {
+			t.Fatalf("ForHost failed: %v", err)
+		}
+		if f.Name() != KindGitea || f.Host() != "forgejo.example.com" {
+			t.Errorf("unexpected client %s for %s", f.Name(), f.Host())
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected detection to run once, ran %d times", calls)
+	}
+}
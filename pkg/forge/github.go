@@ -0,0 +1,335 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const githubPerPage = 100
+
+var _ Forge = (*GitHub)(nil)
+
+// GitHub implements Forge against the GitHub (and GitHub Enterprise) REST API.
+type GitHub struct {
+	host   string
+	client *apiClient
+}
+
+// NewGitHub creates a GitHub client for host. If apiURL is empty it is derived
+// from the host: api.github.com for github.com, https://<host>/api/v3 otherwise.
+func NewGitHub(host, apiURL, token string) *GitHub {
+	return NewGitHubLazy(host, apiURL, func() string { return token })
+}
+
+// NewGitHubLazy is NewGitHub with the token resolved by calling token on the first
+// request, so that commands which never reach the forge do not run e.g. 'gh auth token'.
+func NewGitHubLazy(host, apiURL string, token func() string) *GitHub {
+	token = sync.OnceValue(token)
+	if host == "" {
+		host = "github.com"
+	}
+	if apiURL == "" {
+		if host == "github.com" {
+			apiURL = "https://api.github.com"
+		} else {
+			apiURL = fmt.Sprintf("https://%s/api/v3", host)
+		}
+	}
+
+	client := newAPIClient(apiURL, func(req *http.Request) {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token := token(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	})
+
+	return &GitHub{host: host, client: client}
+}
+
+// GitHubToken resolves an API token for host. It checks the environment variables
+// used by the gh CLI first and falls back to `gh auth token` for users who are
+// already logged in through gh.
+func GitHubToken(host string) string {
+	envVars := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if host != "" && host != "github.com" {
+		envVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	for _, name := range envVars {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+
+	args := []string{"auth", "token"}
+	if host != "" {
+		args = append(args, "--hostname", host)
+	}
+	output, err := exec.Command("gh", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+type githubRepo struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	DefaultBranch string `json:"default_branch"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+func (r githubRepo) toRepo() Repo {
+	return Repo{
+		Owner:         r.Owner.Login,
+		Name:          r.Name,
+		FullName:      r.FullName,
+		CloneURL:      r.CloneURL,
+		SSHURL:        r.SSHURL,
+		DefaultBranch: r.DefaultBranch,
+	}
+}
+
+type githubPull struct {
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	State    string  `json:"state"`
+	HTMLURL  string  `json:"html_url"`
+	MergedAt *string `json:"merged_at"`
+	Head     struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p githubPull) toPullRequest() PullRequest {
+	state := p.State
+	if p.MergedAt != nil && *p.MergedAt != "" {
+		state = "merged"
+	}
+	return PullRequest{
+		Number:  p.Number,
+		Title:   p.Title,
+		State:   state,
+		URL:     p.HTMLURL,
+		Head:    p.Head.Ref,
+		Base:    p.Base.Ref,
+		HeadSHA: p.Head.SHA,
+	}
+}
+
+// Name returns "github".
+func (g *GitHub) Name() string { return "github" }
+
+// Host returns the GitHub hostname.
+func (g *GitHub) Host() string { return g.host }
+
+// ListRepos lists repositories of an organization, falling back to a user account.
+// /users/{owner}/repos only has public repositories, so those of the authenticated
+// user are listed through /user/repos, which includes private ones.
+func (g *GitHub) ListRepos(ctx context.Context, owner string) ([]Repo, error) {
+	repos, err := g.listRepos(ctx, fmt.Sprintf("/orgs/%s/repos", url.PathEscape(owner)))
+	if !errors.Is(err, ErrNotFound) {
+		return repos, err
+	}
+	if login, err := g.authenticatedUser(ctx); err == nil && strings.EqualFold(login, owner) {
+		return g.listRepos(ctx, "/user/repos?affiliation=owner")
+	}
+	return g.listRepos(ctx, fmt.Sprintf("/users/%s/repos", url.PathEscape(owner)))
+}
+
+// authenticatedUser returns the login the token belongs to.
+func (g *GitHub) authenticatedUser(ctx context.Context) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if _, err := g.client.do(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
+
+func (g *GitHub) listRepos(ctx context.Context, path string) ([]Repo, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var repos []Repo
+	for page := 1; ; page++ {
+		var batch []githubRepo
+		if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, githubPerPage, page), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, r := range batch {
+			repos = append(repos, r.toRepo())
+		}
+		if len(batch) < githubPerPage {
+			return repos, nil
+		}
+	}
+}
+
+// GetRepo returns repository details.
+func (g *GitHub) GetRepo(ctx context.Context, owner, repo string) (*Repo, error) {
+	var r githubRepo
+	if _, err := g.client.do(ctx, http.MethodGet, repoPath(owner, repo), nil, &r); err != nil {
+		return nil, err
+	}
+	result := r.toRepo()
+	return &result, nil
+}
+
+// ListBranches returns all branches of the repository.
+func (g *GitHub) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	var branches []Branch
+	for page := 1; ; page++ {
+		var batch []struct {
+			Name   string `json:"name"`
+			Commit struct {
+				SHA string `json:"sha"`
+			} `json:"commit"`
+		}
+		path := fmt.Sprintf("%s/branches?per_page=%d&page=%d", repoPath(owner, repo), githubPerPage, page)
+		if _, err := g.client.do(ctx, http.MethodGet, path, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, b := range batch {
+			branches = append(branches, Branch{Name: b.Name, SHA: b.Commit.SHA})
+		}
+		if len(batch) < githubPerPage {
+			return branches, nil
+		}
+	}
+}
+
+// GetBranchSHA returns the commit SHA of a branch.
+func (g *GitHub) GetBranchSHA(ctx context.Context, owner, repo, branch string) (string, error) {
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	path := fmt.Sprintf("%s/git/ref/heads/%s", repoPath(owner, repo), escapeRef(branch))
+	if _, err := g.client.do(ctx, http.MethodGet, path, nil, &ref); err != nil {
+		return "", err
+	}
+	if ref.Object.SHA == "" {
+		return "", fmt.Errorf("empty SHA returned for branch %s", branch)
+	}
+	return ref.Object.SHA, nil
+}
+
+// CreateRef creates a new branch pointing at sha.
+func (g *GitHub) CreateRef(ctx context.Context, owner, repo, branch, sha string) error {
+	body := map[string]string{
+		"ref": "refs/heads/" + branch,
+		"sha": sha,
+	}
+	_, err := g.client.do(ctx, http.MethodPost, repoPath(owner, repo)+"/git/refs", body, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		return ErrRefExists
+	}
+	return err
+}
+
+// CreatePR opens a pull request.
+func (g *GitHub) CreatePR(ctx context.Context, owner, repo string, pr NewPullRequest) (*PullRequest, error) {
+	body := map[string]string{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  pr.Head,
+		"base":  pr.Base,
+	}
+	var created githubPull
+	if _, err := g.client.do(ctx, http.MethodPost, repoPath(owner, repo)+"/pulls", body, &created); err != nil {
+		return nil, err
+	}
+	result := created.toPullRequest()
+	return &result, nil
+}
+
+// GetPR returns a pull request by number.
+func (g *GitHub) GetPR(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	var pull githubPull
+	if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/pulls/%d", repoPath(owner, repo), number), nil, &pull); err != nil {
+		return nil, err
+	}
+	result := pull.toPullRequest()
+	return &result, nil
+}
+
+// PullRef returns refs/pull/<number>/head.
+func (g *GitHub) PullRef(number int) string { return fmt.Sprintf("refs/pull/%d/head", number) }
+
+// FindPR looks up pull requests whose head is branch in the same repository.
+func (g *GitHub) FindPR(ctx context.Context, owner, repo, branch string) (*PullRequest, error) {
+	var pulls []githubPull
+	path := fmt.Sprintf("%s/pulls?state=all&head=%s&per_page=10", repoPath(owner, repo), url.QueryEscape(owner+":"+branch))
+	if _, err := g.client.do(ctx, http.MethodGet, path, nil, &pulls); err != nil {
+		return nil, err
+	}
+	prs := make([]PullRequest, 0, len(pulls))
+	for _, p := range pulls {
+		prs = append(prs, p.toPullRequest())
+	}
+	return pickPR(prs), nil
+}
+
+// GetIssue returns an issue by number.
+func (g *GitHub) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	var issue struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		State   string `json:"state"`
+		HTMLURL string `json:"html_url"`
+	}
+	if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/issues/%d", repoPath(owner, repo), number), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &Issue{Number: issue.Number, Title: issue.Title, State: issue.State, URL: issue.HTMLURL}, nil
+}
+
+// GetLatestRelease returns the latest published release, or nil if there is none.
+func (g *GitHub) GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	var rel struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+		HTMLURL string `json:"html_url"`
+	}
+	_, err := g.client.do(ctx, http.MethodGet, repoPath(owner, repo)+"/releases/latest", nil, &rel)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Release{TagName: rel.TagName, Name: rel.Name, URL: rel.HTMLURL}, nil
+}
+
+// repoPath builds the /repos/{owner}/{repo} API path.
+func repoPath(owner, repo string) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+}
+
+// escapeRef escapes each segment of a ref name while keeping the slashes.
+func escapeRef(ref string) string {
+	parts := strings.Split(ref, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
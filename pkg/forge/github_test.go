@@ -0,0 +1,287 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestGitHub(t *testing.T, handler http.HandlerFunc) *GitHub {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewGitHub("github.com", server.URL, "test-token")
+}
+
+func TestGitHub_GetRepo(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/api" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		w.Write([]byte(`{"name":"api","full_name":"acme/api","clone_url":"https://github.com/acme/api.git","default_branch":"trunk","owner":{"login":"acme"}}`))
+	})
+
+	repo, err := gh.GetRepo(context.Background(), "acme", "api")
+	if err != nil {
+		t.Fatalf("GetRepo failed: %v", err)
+	}
+	if repo.DefaultBranch != "trunk" || repo.Owner != "acme" || repo.CloneURL != "https://github.com/acme/api.git" {
+		t.Errorf("unexpected repo: %+v", repo)
+	}
+}
+
+func TestNewGitHubLazy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer lazy-token" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		w.Write([]byte(`{"name":"api"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	calls := 0
+	gh := NewGitHubLazy("github.com", server.URL, func() string {
+		calls++
+		return "lazy-token"
+	})
+	if calls != 0 {
+		t.Fatalf("token resolved %d times before any request", calls)
+	}
+	for range 2 {
+		if _, err := gh.GetRepo(context.Background(), "acme", "api"); err != nil {
+			t.Fatalf("GetRepo failed: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("token resolved %d times, want 1", calls)
+	}
+}
+
+func TestGitHub_ListReposFallsBackToUser(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/octocat/repos":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		case "/user":
+			w.Write([]byte(`{"login":"hubot"}`))
+		case "/users/octocat/repos":
+			w.Write([]byte(`[{"name":"hello"},{"name":"world"}]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	repos, err := gh.ListRepos(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("ListRepos failed: %v", err)
+	}
+	if len(repos) != 2 || repos[0].Name != "hello" {
+		t.Errorf("unexpected repos: %+v", repos)
+	}
+}
+
+func TestGitHub_ListReposOfAuthenticatedUser(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/octocat/repos":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+		case "/user":
+			w.Write([]byte(`{"login":"Octocat"}`))
+		case "/user/repos":
+			if got := r.URL.Query().Get("affiliation"); got != "owner" {
+				t.Errorf("affiliation = %q, want owner", got)
+			}
+			if got := r.URL.Query().Get("per_page"); got != "100" {
+				t.Errorf("per_page = %q, want 100", got)
+			}
+			w.Write([]byte(`[{"name":"public"},{"name":"secret","private":true}]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	repos, err := gh.ListRepos(context.Background(), "octocat")
+	if err != nil {
+		t.Fatalf("ListRepos failed: %v", err)
+	}
+	if len(repos) != 2 || repos[1].Name != "secret" {
+		t.Errorf("unexpected repos: %+v", repos)
+	}
+}
+
+func TestGitHub_ListBranchesPaginates(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		var batch []map[string]interface{}
+		count := githubPerPage
+		if r.URL.Query().Get("page") == "2" {
+			count = 3
+		}
+		for i := 0; i < count; i++ {
+			batch = append(batch, map[string]interface{}{"name": "b", "commit": map[string]string{"sha": "abc"}})
+		}
+		json.NewEncoder(w).Encode(batch)
+	})
+
+	branches, err := gh.ListBranches(context.Background(), "acme", "api")
+	if err != nil {
+		t.Fatalf("ListBranches failed: %v", err)
+	}
+	if len(branches) != githubPerPage+3 {
+		t.Errorf("expected %d branches, got %d", githubPerPage+3, len(branches))
+	}
+}
+
+func TestGitHub_CreateRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "created", status: http.StatusCreated},
+		{name: "already exists", status: http.StatusUnprocessableEntity, wantErr: ErrRefExists},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/repos/acme/api/git/refs" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				var body map[string]string
+				json.NewDecoder(r.Body).Decode(&body)
+				if body["ref"] != "refs/heads/feature/x" || body["sha"] != "abc123" {
+					t.Errorf("unexpected body: %v", body)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{}`))
+			})
+
+			err := gh.CreateRef(context.Background(), "acme", "api", "feature/x", "abc123")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGitHub_GetLatestReleaseNone(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	rel, err := gh.GetLatestRelease(context.Background(), "acme", "api")
+	if err != nil {
+		t.Fatalf("expected no error for missing release, got %v", err)
+	}
+	if rel != nil {
+		t.Errorf("expected nil release, got %+v", rel)
+	}
+}
+
+func TestGitHub_CreatePR(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"number":7,"state":"open","html_url":"https://github.com/acme/api/pull/7","head":{"ref":"feature"},"base":{"ref":"main"}}`))
+	})
+
+	pr, err := gh.CreatePR(context.Background(), "acme", "api", NewPullRequest{Title: "t", Head: "feature", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if pr.Number != 7 || pr.State != "open" || pr.Head != "feature" {
+		t.Errorf("unexpected PR: %+v", pr)
+	}
+}
+
+func TestGitHub_GetPR(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/api/pulls/12":
+			w.Write([]byte(`{"number":12,"state":"closed","merged_at":"2024-01-01T00:00:00Z","html_url":"https://github.com/acme/api/pull/12","head":{"ref":"fix"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	pr, err := gh.GetPR(context.Background(), "acme", "api", 12)
+	if err != nil {
+		t.Fatalf("GetPR failed: %v", err)
+	}
+	if pr.Number != 12 || pr.State != "merged" || pr.Head != "fix" {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+	if _, err := gh.GetPR(context.Background(), "acme", "api", 13); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if ref := gh.PullRef(12); ref != "refs/pull/12/head" {
+		t.Errorf("PullRef() = %q", ref)
+	}
+}
+
+func TestGitHub_GetIssue(t *testing.T) {
+	gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/api/issues/42" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"number":42,"title":"Crash on login","state":"open","html_url":"https://github.com/acme/api/issues/42"}`))
+	})
+
+	issue, err := gh.GetIssue(context.Background(), "acme", "api", 42)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Number != 42 || issue.Title != "Crash on login" || issue.URL != "https://github.com/acme/api/issues/42" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+}
+
+func TestGitHub_FindPR(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantNum   int
+		wantState string
+		wantSHA   string
+	}{
+		{name: "prefers open", body: `[{"number":9,"state":"closed","merged_at":"2024-01-01T00:00:00Z","head":{"ref":"feature"}},{"number":7,"state":"open","head":{"ref":"feature"}}]`, wantNum: 7, wantState: "open"},
+		{name: "merged", body: `[{"number":9,"state":"closed","merged_at":"2024-01-01T00:00:00Z","head":{"ref":"feature","sha":"abc123"}}]`, wantNum: 9, wantState: "merged", wantSHA: "abc123"},
+		{name: "none", body: `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gh := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/repos/acme/api/pulls" || r.URL.Query().Get("head") != "acme:feature" || r.URL.Query().Get("state") != "all" {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				w.Write([]byte(tt.body))
+			})
+
+			pr, err := gh.FindPR(context.Background(), "acme", "api", "feature")
+			if err != nil {
+				t.Fatalf("FindPR failed: %v", err)
+			}
+			if tt.wantNum == 0 {
+				if pr != nil {
+					t.Errorf("expected no pull request, got %+v", pr)
+				}
+				return
+			}
+			if pr == nil || pr.Number != tt.wantNum || pr.State != tt.wantState || pr.HeadSHA != tt.wantSHA {
+				t.Errorf("unexpected pull request: %+v", pr)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const gitlabPerPage = 100
+
+var _ Forge = (*GitLab)(nil)
+
+// GitLab implements Forge against the GitLab REST API (v4).
+// Owners are groups (including subgroups such as "group/subgroup") or users,
+// and pull requests map onto merge requests.
+type GitLab struct {
+	host   string
+	client *apiClient
+}
+
+// NewGitLab creates a GitLab client for host. If apiURL is empty it defaults to https://<host>/api/v4.
+func NewGitLab(host, apiURL, token string) *GitLab {
+	if host == "" {
+		host = "gitlab.com"
+	}
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://%s/api/v4", host)
+	}
+
+	client := newAPIClient(apiURL, func(req *http.Request) {
+		if token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	})
+
+	return &GitLab{host: host, client: client}
+}
+
+type gitlabProject struct {
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	SSHURLToRepo      string `json:"ssh_url_to_repo"`
+	DefaultBranch     string `json:"default_branch"`
+	Namespace         struct {
+		FullPath string `json:"full_path"`
+	} `json:"namespace"`
+}
+
+func (p gitlabProject) toRepo() Repo {
+	return Repo{
+		Owner:         p.Namespace.FullPath,
+		Name:          p.Path,
+		FullName:      p.PathWithNamespace,
+		CloneURL:      p.HTTPURLToRepo,
+		SSHURL:        p.SSHURLToRepo,
+		DefaultBranch: p.DefaultBranch,
+	}
+}
+
+type gitlabMergeRequest struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	State        string `json:"state"`
+	WebURL       string `json:"web_url"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	SHA          string `json:"sha"` // Head commit
+}
+
+func (m gitlabMergeRequest) toPullRequest() PullRequest {
+	state := m.State
+	switch state {
+	case "opened", "locked":
+		state = "open"
+	}
+	return PullRequest{
+		Number:  m.IID,
+		Title:   m.Title,
+		State:   state,
+		URL:     m.WebURL,
+		Head:    m.SourceBranch,
+		Base:    m.TargetBranch,
+		HeadSHA: m.SHA,
+	}
+}
+
+// Name returns "gitlab".
+func (g *GitLab) Name() string { return "gitlab" }
+
+// Host returns the GitLab hostname.
+func (g *GitLab) Host() string { return g.host }
+
+// ListRepos lists the projects of a group (including subgroups), falling back to a user namespace.
+func (g *GitLab) ListRepos(ctx context.Context, owner string) ([]Repo, error) {
+	repos, err := g.listProjects(ctx, fmt.Sprintf("/groups/%s/projects?include_subgroups=true&archived=false", url.PathEscape(owner)))
+	if errors.Is(err, ErrNotFound) {
+		repos, err = g.listProjects(ctx, fmt.Sprintf("/users/%s/projects?archived=false", url.PathEscape(owner)))
+	}
+	return repos, err
+}
+
+func (g *GitLab) listProjects(ctx context.Context, basePath string) ([]Repo, error) {
+	var repos []Repo
+	for page := 1; ; page++ {
+		var batch []gitlabProject
+		if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s&per_page=%d&page=%d", basePath, gitlabPerPage, page), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, p := range batch {
+			repos = append(repos, p.toRepo())
+		}
+		if len(batch) < gitlabPerPage {
+			return repos, nil
+		}
+	}
+}
+
+// GetRepo returns project details.
+func (g *GitLab) GetRepo(ctx context.Context, owner, repo string) (*Repo, error) {
+	var p gitlabProject
+	if _, err := g.client.do(ctx, http.MethodGet, projectPath(owner, repo), nil, &p); err != nil {
+		return nil, err
+	}
+	result := p.toRepo()
+	return &result, nil
+}
+
+// ListBranches returns all branches of the project.
+func (g *GitLab) ListBranches(ctx context.Context, owner, repo string) ([]Branch, error) {
+	var branches []Branch
+	for page := 1; ; page++ {
+		var batch []gitlabBranch
+		p := fmt.Sprintf("%s/repository/branches?per_page=%d&page=%d", projectPath(owner, repo), gitlabPerPage, page)
+		if _, err := g.client.do(ctx, http.MethodGet, p, nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, b := range batch {
+			branches = append(branches, Branch{Name: b.Name, SHA: b.Commit.ID})
+		}
+		if len(batch) < gitlabPerPage {
+			return branches, nil
+		}
+	}
+}
+
+type gitlabBranch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+// GetBranchSHA returns the commit SHA of a branch.
+func (g *GitLab) GetBranchSHA(ctx context.Context, owner, repo, branch string) (string, error) {
+	var b gitlabBranch
+	p := fmt.Sprintf("%s/repository/branches/%s", projectPath(owner, repo), url.PathEscape(branch))
+	if _, err := g.client.do(ctx, http.MethodGet, p, nil, &b); err != nil {
+		return "", err
+	}
+	if b.Commit.ID == "" {
+		return "", fmt.Errorf("empty SHA returned for branch %s", branch)
+	}
+	return b.Commit.ID, nil
+}
+
+// CreateRef creates a new branch pointing at sha. Branch names matching one of the
+// project's protected branch patterns are refused with ErrProtectedBranch, since
+// GitLab would otherwise create a branch that only maintainers can push to.
+func (g *GitLab) CreateRef(ctx context.Context, owner, repo, branch, sha string) error {
+	var protected []struct {
+		Name string `json:"name"`
+	}
+	if _, err := g.client.do(ctx, http.MethodGet, projectPath(owner, repo)+"/protected_branches?per_page=100", nil, &protected); err != nil && !errors.Is(err, ErrUnauthorized) {
+		return err
+	}
+	for _, p := range protected {
+		if matched, _ := path.Match(p.Name, branch); matched {
+			return fmt.Errorf("%w: %s matches %q", ErrProtectedBranch, branch, p.Name)
+		}
+	}
+
+	p := fmt.Sprintf("%s/repository/branches?branch=%s&ref=%s", projectPath(owner, repo), url.QueryEscape(branch), url.QueryEscape(sha))
+	_, err := g.client.do(ctx, http.MethodPost, p, nil, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest && strings.Contains(apiErr.Message, "already exists") {
+		return ErrRefExists
+	}
+	return err
+}
+
+// CreatePR opens a merge request.
+func (g *GitLab) CreatePR(ctx context.Context, owner, repo string, pr NewPullRequest) (*PullRequest, error) {
+	body := map[string]string{
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+	}
+	var created gitlabMergeRequest
+	if _, err := g.client.do(ctx, http.MethodPost, projectPath(owner, repo)+"/merge_requests", body, &created); err != nil {
+		return nil, err
+	}
+	result := created.toPullRequest()
+	return &result, nil
+}
+
+// GetPR returns a merge request by its project-level IID.
+func (g *GitLab) GetPR(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	var mr gitlabMergeRequest
+	if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/merge_requests/%d", projectPath(owner, repo), number), nil, &mr); err != nil {
+		return nil, err
+	}
+	result := mr.toPullRequest()
+	return &result, nil
+}
+
+// PullRef returns refs/merge-requests/<number>/head.
+func (g *GitLab) PullRef(number int) string {
+	return fmt.Sprintf("refs/merge-requests/%d/head", number)
+}
+
+// FindPR looks up merge requests whose source branch is branch.
+func (g *GitLab) FindPR(ctx context.Context, owner, repo, branch string) (*PullRequest, error) {
+	var mrs []gitlabMergeRequest
+	p := fmt.Sprintf("%s/merge_requests?state=all&source_branch=%s&order_by=updated_at&per_page=10", projectPath(owner, repo), url.QueryEscape(branch))
+	if _, err := g.client.do(ctx, http.MethodGet, p, nil, &mrs); err != nil {
+		return nil, err
+	}
+	prs := make([]PullRequest, 0, len(mrs))
+	for _, m := range mrs {
+		prs = append(prs, m.toPullRequest())
+	}
+	return pickPR(prs), nil
+}
+
+// GetIssue returns an issue by its project-level IID.
+func (g *GitLab) GetIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	var issue struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		State  string `json:"state"`
+		WebURL string `json:"web_url"`
+	}
+	if _, err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("%s/issues/%d", projectPath(owner, repo), number), nil, &issue); err != nil {
+		return nil, err
+	}
+	state := issue.State
+	if state == "opened" {
+		state = "open"
+	}
+	return &Issue{Number: issue.IID, Title: issue.Title, State: state, URL: issue.WebURL}, nil
+}
+
+// GetLatestRelease returns the most recently released release, or nil if there is none.
+func (g *GitLab) GetLatestRelease(ctx context.Context, owner, repo string) (*Release, error) {
+	var releases []struct {
+		TagName string `json:"tag_name"`
+		Name    string `json:"name"`
+		Links   struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	p := projectPath(owner, repo) + "/releases?order_by=released_at&sort=desc&per_page=1"
+	_, err := g.client.do(ctx, http.MethodGet, p, nil, &releases)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	return &Release{TagName: releases[0].TagName, Name: releases[0].Name, URL: releases[0].Links.Self}, nil
+}
+
+// projectPath builds the /projects/:id API path, where :id is the URL-encoded "namespace/project".
+func projectPath(owner, repo string) string {
+	return "/projects/" + url.PathEscape(owner+"/"+repo)
+}
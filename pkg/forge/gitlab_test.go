@@ -0,0 +1,215 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestGitLab starts an httptest stand-in for the GitLab API. Handlers see the
+// escaped path so project IDs like "group%2Fsub%2Fapi" can be asserted on.
+func newTestGitLab(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, path string)) *GitLab {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "test-token" {
+			t.Errorf("expected private token header, got %q", got)
+		}
+		handler(w, r, r.URL.EscapedPath())
+	}))
+	t.Cleanup(server.Close)
+	return NewGitLab("gitlab.example.com", server.URL, "test-token")
+}
+
+func TestGitLab_ListReposIncludesSubgroups(t *testing.T) {
+	gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+		if path != "/groups/platform%2Fbackend/projects" {
+			t.Errorf("unexpected path %s", path)
+		}
+		if r.URL.Query().Get("include_subgroups") != "true" {
+			t.Error("expected include_subgroups=true")
+		}
+		w.Write([]byte(`[{"path":"api","path_with_namespace":"platform/backend/api","namespace":{"full_path":"platform/backend"}}]`))
+	})
+
+	repos, err := gl.ListRepos(context.Background(), "platform/backend")
+	if err != nil {
+		t.Fatalf("ListRepos failed: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "api" || repos[0].Owner != "platform/backend" {
+		t.Errorf("unexpected repos: %+v", repos)
+	}
+}
+
+func TestGitLab_GetRepo(t *testing.T) {
+	gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+		if path != "/projects/platform%2Fapi" {
+			t.Errorf("unexpected path %s", path)
+		}
+		w.Write([]byte(`{"path":"api","default_branch":"develop","http_url_to_repo":"https://gitlab.example.com/platform/api.git","namespace":{"full_path":"platform"}}`))
+	})
+
+	repo, err := gl.GetRepo(context.Background(), "platform", "api")
+	if err != nil {
+		t.Fatalf("GetRepo failed: %v", err)
+	}
+	if repo.DefaultBranch != "develop" || repo.CloneURL != "https://gitlab.example.com/platform/api.git" {
+		t.Errorf("unexpected repo: %+v", repo)
+	}
+}
+
+func TestGitLab_CreateRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		branch      string
+		protected   string
+		createCode  int
+		createBody  string
+		wantErr     error
+		wantCreated bool
+	}{
+		{name: "created", branch: "feature-x", protected: `[{"name":"main"}]`, createCode: http.StatusCreated, wantCreated: true},
+		{name: "protected wildcard", branch: "release/1.0", protected: `[{"name":"release/*"}]`, wantErr: ErrProtectedBranch},
+		{name: "already exists", branch: "feature-x", protected: `[]`, createCode: http.StatusBadRequest, createBody: `{"message":"Branch already exists"}`, wantErr: ErrRefExists, wantCreated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+				switch {
+				case r.Method == http.MethodGet && path == "/projects/platform%2Fapi/protected_branches":
+					w.Write([]byte(tt.protected))
+				case r.Method == http.MethodPost && path == "/projects/platform%2Fapi/repository/branches":
+					created = true
+					if r.URL.Query().Get("branch") != tt.branch || r.URL.Query().Get("ref") != "abc123" {
+						t.Errorf("unexpected query %s", r.URL.RawQuery)
+					}
+					w.WriteHeader(tt.createCode)
+					w.Write([]byte(tt.createBody))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, path)
+				}
+			})
+
+			err := gl.CreateRef(context.Background(), "platform", "api", tt.branch, "abc123")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("expected create call = %v", tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestGitLab_CreatePROpensMergeRequest(t *testing.T) {
+	gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+		if r.Method != http.MethodPost || path != "/projects/platform%2Fapi/merge_requests" {
+			t.Errorf("unexpected request %s %s", r.Method, path)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["source_branch"] != "feature" || body["target_branch"] != "main" || body["description"] != "details" {
+			t.Errorf("unexpected body: %v", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"iid":12,"state":"opened","web_url":"https://gitlab.example.com/platform/api/-/merge_requests/12","source_branch":"feature","target_branch":"main"}`))
+	})
+
+	pr, err := gl.CreatePR(context.Background(), "platform", "api", NewPullRequest{Title: "t", Body: "details", Head: "feature", Base: "main"})
+	if err != nil {
+		t.Fatalf("CreatePR failed: %v", err)
+	}
+	if pr.Number != 12 || pr.State != "open" {
+		t.Errorf("unexpected merge request: %+v", pr)
+	}
+}
+
+func TestGitLab_FindPR(t *testing.T) {
+	gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+		if path != "/projects/platform%2Fapi/merge_requests" || r.URL.Query().Get("source_branch") != "feature" {
+			t.Errorf("unexpected request %s?%s", path, r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"iid":4,"state":"merged","source_branch":"feature","target_branch":"main"}]`))
+	})
+
+	pr, err := gl.FindPR(context.Background(), "platform", "api", "feature")
+	if err != nil {
+		t.Fatalf("FindPR failed: %v", err)
+	}
+	if pr == nil || pr.Number != 4 || pr.State != "merged" {
+		t.Errorf("unexpected merge request: %+v", pr)
+	}
+}
+
+func TestGitLab_GetPR(t *testing.T) {
+	gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+		if path != "/projects/group%2Fsub%2Fapi/merge_requests/7" {
+			t.Errorf("unexpected request %s", path)
+		}
+		w.Write([]byte(`{"iid":7,"state":"opened","source_branch":"feature","target_branch":"main"}`))
+	})
+
+	pr, err := gl.GetPR(context.Background(), "group/sub", "api", 7)
+	if err != nil {
+		t.Fatalf("GetPR failed: %v", err)
+	}
+	if pr.Number != 7 || pr.State != "open" {
+		t.Errorf("unexpected merge request: %+v", pr)
+	}
+	if ref := gl.PullRef(7); ref != "refs/merge-requests/7/head" {
+		t.Errorf("PullRef() = %q", ref)
+	}
+}
+
+func TestGitLab_GetIssue(t *testing.T) {
+	gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+		if path != "/projects/platform%2Fapi/issues/12" {
+			t.Errorf("unexpected request %s", path)
+		}
+		w.Write([]byte(`{"iid":12,"title":"Crash on login","state":"opened","web_url":"https://gitlab.example.com/platform/api/-/issues/12"}`))
+	})
+
+	issue, err := gl.GetIssue(context.Background(), "platform", "api", 12)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Number != 12 || issue.Title != "Crash on login" || issue.State != "open" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+}
+
+func TestGitLab_GetLatestRelease(t *testing.T) {
+	gl := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request, path string) {
+		w.Write([]byte(`[{"tag_name":"v1.4.0","name":"1.4.0"}]`))
+	})
+
+	rel, err := gl.GetLatestRelease(context.Background(), "platform", "api")
+	if err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if rel == nil || rel.TagName != "v1.4.0" {
+		t.Errorf("unexpected release: %+v", rel)
+	}
+}
+
+func TestRegistry_ForHost(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGitHub("github.com", "", ""))
+	registry.Register(NewGitLab("gitlab.example.com", "", ""))
+
+	f, err := registry.ForHost("gitlab.example.com")
+	if err != nil || f.Name() != KindGitLab {
+		t.Fatalf("expected gitlab client, got %v, %v", f, err)
+	}
+
+	if _, err := registry.ForHost("unknown.example.com"); !errors.Is(err, ErrUnsupportedHost) {
+		t.Errorf("expected ErrUnsupportedHost, got %v", err)
+	}
+}
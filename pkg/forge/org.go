@@ -0,0 +1,48 @@
+package forge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultHost is the host assumed for org entries that do not name one.
+const DefaultHost = "github.com"
+
+// OrgRef identifies an organization, user or group (including subgroups) on a forge host.
+type OrgRef struct {
+	Host  string
+	Owner string
+}
+
+// ParseOrgRef parses a preferred_orgs entry. Entries may be host-qualified
+// ("github.com/acme", "ghe.corp.example/platform", "gitlab.example.com/group/subgroup")
+// or a bare org name, which refers to DefaultHost.
+func ParseOrgRef(entry string) (OrgRef, error) {
+	entry = strings.TrimSpace(entry)
+	entry = strings.TrimPrefix(entry, "https://")
+	entry = strings.TrimPrefix(entry, "http://")
+	entry = strings.Trim(entry, "/")
+	if entry == "" {
+		return OrgRef{}, fmt.Errorf("empty org entry")
+	}
+
+	first, rest, hasSlash := strings.Cut(entry, "/")
+	if !IsHostname(first) {
+		return OrgRef{Host: DefaultHost, Owner: entry}, nil
+	}
+	if !hasSlash || rest == "" {
+		return OrgRef{}, fmt.Errorf("org entry %q names a host but no org", entry)
+	}
+	return OrgRef{Host: first, Owner: rest}, nil
+}
+
+// String returns the host-qualified form "host/owner".
+func (o OrgRef) String() string {
+	return o.Host + "/" + o.Owner
+}
+
+// IsHostname reports whether a path segment looks like a hostname rather than
+// an org name: it contains a dot or a port, or is "localhost".
+func IsHostname(segment string) bool {
+	return strings.Contains(segment, ".") || strings.Contains(segment, ":") || segment == "localhost"
+}
@@ -0,0 +1,32 @@
+package forge
+
+import "testing"
+
+func TestParseOrgRef(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    OrgRef
+		wantErr bool
+	}{
+		{entry: "acme", want: OrgRef{Host: "github.com", Owner: "acme"}},
+		{entry: "github.com/acme", want: OrgRef{Host: "github.com", Owner: "acme"}},
+		{entry: "ghe.corp.example/platform", want: OrgRef{Host: "ghe.corp.example", Owner: "platform"}},
+		{entry: "gitlab.example.com/group/subgroup", want: OrgRef{Host: "gitlab.example.com", Owner: "group/subgroup"}},
+		{entry: "https://gitlab.example.com/group/", want: OrgRef{Host: "gitlab.example.com", Owner: "group"}},
+		{entry: "localhost:3000/me", want: OrgRef{Host: "localhost:3000", Owner: "me"}},
+		{entry: "gitlab.example.com", wantErr: true},
+		{entry: "  ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := ParseOrgRef(tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOrgRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParseOrgRef() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PullURL identifies a pull request (or merge request) by its web URL.
+type PullURL struct {
+	Host   string
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// ParsePullURL parses the web URL of a pull request: .../owner/repo/pull/42 on GitHub,
+// .../owner/repo/pulls/42 on Gitea and Forgejo, and .../group/sub/repo/-/merge_requests/42
+// on GitLab. Trailing paths such as /files are ignored.
+func ParsePullURL(raw string) (PullURL, error) {
+	rest := strings.TrimSpace(raw)
+	rest = strings.TrimPrefix(rest, "https://")
+	rest = strings.TrimPrefix(rest, "http://")
+
+	segments := strings.Split(strings.Trim(rest, "/"), "/")
+	for i := 3; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "pull", "pulls", "merge_requests":
+		default:
+			continue
+		}
+		number, err := strconv.Atoi(segments[i+1])
+		if err != nil || number <= 0 {
+			break
+		}
+		path := segments[1:i]
+		if path[len(path)-1] == "-" {
+			path = path[:len(path)-1]
+		}
+		if len(path) < 2 || !IsHostname(segments[0]) {
+			break
+		}
+		return PullURL{
+			Host:   segments[0],
+			Owner:  strings.Join(path[:len(path)-1], "/"),
+			Repo:   path[len(path)-1],
+			Number: number,
+		}, nil
+	}
+	return PullURL{}, fmt.Errorf("%q is not a pull request URL", raw)
+}
+
+// NewPullURL returns the web page for opening a pull request of head into base by
+// hand on a forge of the given kind (a merge request on GitLab), or "" for an
+// unknown kind.
+func NewPullURL(kind, host, owner, repo, head, base string) string {
+	web := fmt.Sprintf("https://%s/%s/%s", host, owner, repo)
+	switch kind {
+	case KindGitHub:
+		return fmt.Sprintf("%s/compare/%s...%s?expand=1", web, base, head)
+	case KindGitea, KindForgejo:
+		return fmt.Sprintf("%s/compare/%s...%s", web, base, head)
+	case KindGitLab:
+		query := url.Values{
+			"merge_request[source_branch]": {head},
+			"merge_request[target_branch]": {base},
+		}
+		return web + "/-/merge_requests/new?" + query.Encode()
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package forge
+
+import "testing"
+
+func TestParsePullURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    PullURL
+		wantErr bool
+	}{
+		{url: "https://github.com/acme/api/pull/42", want: PullURL{Host: "github.com", Owner: "acme", Repo: "api", Number: 42}},
+		{url: "https://github.com/acme/api/pull/42/files", want: PullURL{Host: "github.com", Owner: "acme", Repo: "api", Number: 42}},
+		{url: "forgejo.example.com/me/side/pulls/3", want: PullURL{Host: "forgejo.example.com", Owner: "me", Repo: "side", Number: 3}},
+		{url: "https://gitlab.example.com/group/sub/api/-/merge_requests/7", want: PullURL{Host: "gitlab.example.com", Owner: "group/sub", Repo: "api", Number: 7}},
+		{url: "https://github.com/acme/api/issues/42", wantErr: true},
+		{url: "https://github.com/acme/api/pull/abc", wantErr: true},
+		{url: "https://github.com/api/pull/1", wantErr: true},
+		{url: "42", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := ParsePullURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePullURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParsePullURL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPullURL(t *testing.T) {
+	tests := []struct {
+		kind string
+		host string
+		want string
+	}{
+		{kind: KindGitHub, host: "github.com", want: "https://github.com/acme/api/compare/main...feature/login?expand=1"},
+		{kind: KindGitea, host: "codeberg.org", want: "https://codeberg.org/acme/api/compare/main...feature/login"},
+		{kind: KindGitLab, host: "gitlab.com", want: "https://gitlab.com/acme/api/-/merge_requests/new?merge_request%5Bsource_branch%5D=feature%2Flogin&merge_request%5Btarget_branch%5D=main"},
+		{kind: "fake", host: "example.com", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			if got := NewPullURL(tt.kind, tt.host, "acme", "api", "feature/login", "main"); got != tt.want {
+				t.Errorf("NewPullURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Backend names accepted by New.
+const (
+	KindGitHub  = "github"
+	KindGitLab  = "gitlab"
+	KindGitea   = "gitea"
+	KindForgejo = "forgejo" // alias for KindGitea
+)
+
+// New creates a Forge client of the given kind for host. token is called for the
+// API token; GitHub clients call it on their first request, since it may run gh.
+func New(kind, host, apiURL string, token func() string) (Forge, error) {
+	switch kind {
+	case KindGitHub:
+		return NewGitHubLazy(host, apiURL, token), nil
+	case KindGitLab:
+		return NewGitLab(host, apiURL, token()), nil
+	case KindGitea, KindForgejo:
+		return NewGitea(host, apiURL, token()), nil
+	default:
+		return nil, fmt.Errorf("unknown forge type %q (supported: %s, %s, %s)", kind, KindGitHub, KindGitLab, KindGitea)
+	}
+}
+
+// TokenFunc returns a function resolving the API token for a forge with Token.
+func TokenFunc(kind, host, tokenEnv string) func() string {
+	return func() string { return Token(kind, host, tokenEnv) }
+}
+
+// Token resolves the API token for a forge. An explicit tokenEnv takes precedence,
+// otherwise the conventional environment variables for the backend are consulted.
+func Token(kind, host, tokenEnv string) string {
+	if tokenEnv != "" {
+		return os.Getenv(tokenEnv)
+	}
+	switch kind {
+	case KindGitHub:
+		return GitHubToken(host)
+	case KindGitLab:
+		return os.Getenv("GITLAB_TOKEN")
+	case KindGitea, KindForgejo:
+		if token := os.Getenv("GITEA_TOKEN"); token != "" {
+			return token
+		}
+		return os.Getenv("FORGEJO_TOKEN")
+	}
+	return ""
+}
+
+// DetectFunc determines the backend kind serving a host.
+type DetectFunc func(ctx context.Context, host string) (string, error)
+
+// HostOptions holds per-host settings applied when a client is created on demand.
+type HostOptions struct {
+	APIURL   string
+	TokenEnv string
+}
+
+// Registry maps hostnames to Forge clients so commands can pick the backend
+// that matches a repository's remote. Hosts without an explicit client are
+// detected on first use when detection is enabled.
+type Registry struct {
+	mu      sync.RWMutex
+	forges  map[string]Forge
+	options map[string]HostOptions
+	detect  DetectFunc
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		forges:  make(map[string]Forge),
+		options: make(map[string]HostOptions),
+	}
+}
+
+// EnableDetection makes ForHost create clients for unknown hosts using detect.
+func (r *Registry) EnableDetection(detect DetectFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.detect = detect
+}
+
+// SetHostOptions records settings used when a client for host is created on demand.
+func (r *Registry) SetHostOptions(host string, opts HostOptions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.options[host] = opts
+}
+
+// Register adds (or replaces) the client for f.Host().
+func (r *Registry) Register(f Forge) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.forges[f.Host()] = f
+}
+
+// ForHost returns the client registered for host, detecting the backend if needed.
+func (r *Registry) ForHost(host string) (Forge, error) {
+	r.mu.RLock()
+	f, ok := r.forges[host]
+	detect := r.detect
+	r.mu.RUnlock()
+	if ok {
+		return f, nil
+	}
+	if detect == nil || host == "" {
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedHost, host)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	kind, err := detect(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrUnsupportedHost, host, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.forges[host]; ok {
+		return f, nil
+	}
+	opts := r.options[host]
+	f, err = New(kind, host, opts.APIURL, TokenFunc(kind, host, opts.TokenEnv))
+	if err != nil {
+		return nil, err
+	}
+	r.forges[host] = f
+	return f, nil
+}
+
+// Hosts returns the registered hostnames in sorted order.
+func (r *Registry) Hosts() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	hosts := make([]string, 0, len(r.forges))
+	for host := range r.forges {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
@@ -108,43 +108,50 @@ func (r *Runner) IsWorktree(ctx context.Context, path string) bool {
 	return r.IsInsideWorkTree(ctx, path)
 }
 
-// GetDefaultBranch returns the default branch name (e.g., "main" or "master").
-// It uses the GitHub CLI to query the repository's default branch.
+// GetDefaultBranch returns the default branch name (e.g., "main" or "master") of the origin remote.
+// It reads the locally cached origin/HEAD first and falls back to asking the remote.
 func (r *Runner) GetDefaultBranch(ctx context.Context, workDir string) (string, error) {
-	// Apply timeout if not already set in context
-	if r.timeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, r.timeout)
-		defer cancel()
-	}
-
-	cmd := exec.CommandContext(ctx, "gh", "repo", "view", "--json", "defaultBranchRef", "--jq", ".defaultBranchRef.name")
-	if workDir != "" {
-		cmd.Dir = workDir
+	if output, err := r.RunSimple(ctx, workDir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if branch := strings.TrimPrefix(output, "origin/"); branch != "" && branch != output {
+			return branch, nil
+		}
 	}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
+	output, err := r.RunSimple(ctx, workDir, "ls-remote", "--symref", "origin", "HEAD")
 	if err != nil {
-		return "", fmt.Errorf("gh repo view failed: %w (stderr: %s)", err, strings.TrimSpace(stderr.String()))
+		return "", err
 	}
 
-	branch := strings.TrimSpace(stdout.String())
-	if branch == "" {
-		return "", fmt.Errorf("could not determine default branch")
+	// Output looks like: "ref: refs/heads/main\tHEAD"
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "ref: ") {
+			continue
+		}
+		ref := strings.Fields(strings.TrimPrefix(line, "ref: "))
+		if len(ref) > 0 {
+			if branch := strings.TrimPrefix(ref[0], "refs/heads/"); branch != "" {
+				return branch, nil
+			}
+		}
 	}
 
-	return branch, nil
+	return "", fmt.Errorf("could not determine default branch")
 }
 
 // Worktree represents a git worktree entry.
 type Worktree struct {
-	Path   string
-	Branch string
-	Commit string
+	Path     string
+	Branch   string // Empty when HEAD is detached
+	Commit   string
+	Bare     bool // The bare repository of a bare container, not a working copy
+	Detached bool
+
+	// Locked worktrees are protected from prune and remove, e.g. on removable media
+	Locked     bool
+	LockReason string
+	// Prunable worktrees have lost their folder; 'git worktree prune' forgets them
+	Prunable       bool
+	PrunableReason string
 }
 
 // ListWorktrees returns all worktrees for the repository.
@@ -153,7 +160,12 @@ func (r *Runner) ListWorktrees(ctx context.Context, workDir string) ([]Worktree,
 	if err != nil {
 		return nil, err
 	}
+	return parseWorktreeList(output), nil
+}
 
+// parseWorktreeList parses `git worktree list --porcelain`: one block of attribute
+// lines per worktree, separated by blank lines. Unknown attributes are ignored.
+func parseWorktreeList(output string) []Worktree {
 	var worktrees []Worktree
 	var current Worktree
 
@@ -167,14 +179,24 @@ func (r *Runner) ListWorktrees(ctx context.Context, workDir string) ([]Worktree,
 			continue
 		}
 
-		if strings.HasPrefix(line, "worktree ") {
-			current.Path = strings.TrimPrefix(line, "worktree ")
-		} else if strings.HasPrefix(line, "HEAD ") {
-			current.Commit = strings.TrimPrefix(line, "HEAD ")
-		} else if strings.HasPrefix(line, "branch ") {
-			branch := strings.TrimPrefix(line, "branch ")
-			// Remove refs/heads/ prefix if present
-			current.Branch = strings.TrimPrefix(branch, "refs/heads/")
+		attr, value, _ := strings.Cut(line, " ")
+		switch attr {
+		case "worktree":
+			current.Path = value
+		case "HEAD":
+			current.Commit = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+			current.PrunableReason = value
 		}
 	}
 
@@ -183,7 +205,7 @@ func (r *Runner) ListWorktrees(ctx context.Context, workDir string) ([]Worktree,
 		worktrees = append(worktrees, current)
 	}
 
-	return worktrees, nil
+	return worktrees
 }
 
 // RemoveWorktree removes a worktree at the given path.
@@ -217,6 +239,75 @@ func (r *Runner) IsBranchMerged(ctx context.Context, repoPath, branchName, baseB
 	return false, nil
 }
 
+// IsSquashMerged reports whether the changes of branchName reached baseBranch without
+// its commits: every commit was cherry-picked or rebased onto baseBranch (git cherry),
+// the combined diff since the merge base was applied as one squash commit, or merging
+// branchName into baseBranch would not change its tree. Branches without commits of
+// their own are not squash merged.
+func (r *Runner) IsSquashMerged(ctx context.Context, repoPath, branchName, baseBranch string) (bool, error) {
+	mergeBase, err := r.RunSimple(ctx, repoPath, "merge-base", baseBranch, branchName)
+	if err != nil {
+		return false, err
+	}
+	head, err := r.RunSimple(ctx, repoPath, "rev-parse", branchName+"^{commit}")
+	if err != nil {
+		return false, err
+	}
+	if head == mergeBase {
+		return false, nil
+	}
+
+	// A branch without net changes (only empty commits, or changes it reverted itself)
+	// would pass every check below without anything having been merged
+	trees, err := r.RunSimple(ctx, repoPath, "rev-parse", head+"^{tree}", mergeBase+"^{tree}")
+	if err != nil {
+		return false, err
+	}
+	if tree := strings.Fields(trees); len(tree) == 2 && tree[0] == tree[1] {
+		return false, nil
+	}
+
+	// Rebase merges: each commit has a patch-equivalent commit in baseBranch
+	if output, err := r.RunSimple(ctx, repoPath, "cherry", baseBranch, branchName); err == nil && allUpstream(output) {
+		return true, nil
+	}
+
+	// Squash merges: the branch as a single commit on its merge base is patch-equivalent
+	// to a commit in baseBranch. The temporary commit is unreferenced and left to gc.
+	squashed, err := r.RunSimple(ctx, repoPath, "commit-tree", branchName+"^{tree}", "-p", mergeBase, "-m", "squash")
+	if err == nil {
+		if output, err := r.RunSimple(ctx, repoPath, "cherry", baseBranch, squashed); err == nil && allUpstream(output) {
+			return true, nil
+		}
+	}
+
+	// Squash merges amended or combined with later changes to the same lines still merge
+	// cleanly to baseBranch's own tree. merge-tree --write-tree needs git 2.38.
+	merged, err := r.RunSimple(ctx, repoPath, "merge-tree", "--write-tree", baseBranch, branchName)
+	if err == nil {
+		baseTree, err := r.RunSimple(ctx, repoPath, "rev-parse", baseBranch+"^{tree}")
+		if err == nil && strings.SplitN(merged, "\n", 2)[0] == baseTree {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// allUpstream reports whether git cherry output lists commits, all of them with an
+// equivalent upstream ("-").
+func allUpstream(cherry string) bool {
+	if cherry == "" {
+		return false
+	}
+	for _, line := range strings.Split(cherry, "\n") {
+		if !strings.HasPrefix(line, "- ") {
+			return false
+		}
+	}
+	return true
+}
+
 // GetGitStatus returns the porcelain status output lines.
 // Empty slice means working tree is clean.
 func (r *Runner) GetGitStatus(ctx context.Context, workDir string) ([]string, error) {
@@ -2,7 +2,9 @@ package gitexec
 
 import (
 	"context"
-	"os/exec"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -44,21 +46,181 @@ func TestRunner_RunIgnoreError(t *testing.T) {
 }
 
 func TestRunner_GetDefaultBranch(t *testing.T) {
-	// Skip test if gh CLI is not available
-	if _, err := exec.LookPath("gh"); err != nil {
-		t.Skip("gh CLI not available, skipping test")
+	runner := New(5 * time.Second)
+	ctx := context.Background()
+
+	// Create an origin repository whose default branch is "trunk" and clone it
+	dir := t.TempDir()
+	origin := filepath.Join(dir, "origin")
+	clone := filepath.Join(dir, "clone")
+
+	mustRun(t, runner, "", "init", "--initial-branch=trunk", origin)
+	mustRun(t, runner, origin, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "initial")
+	mustRun(t, runner, "", "clone", origin, clone)
+
+	branch, err := runner.GetDefaultBranch(ctx, clone)
+	if err != nil {
+		t.Fatalf("expected to get default branch: %v", err)
+	}
+	if branch != "trunk" {
+		t.Errorf("expected default branch to be 'trunk', got %q", branch)
 	}
 
+	// Without a cached origin/HEAD the remote is queried instead
+	mustRun(t, runner, clone, "remote", "set-head", "origin", "--delete")
+	branch, err = runner.GetDefaultBranch(ctx, clone)
+	if err != nil {
+		t.Fatalf("expected to get default branch from remote: %v", err)
+	}
+	if branch != "trunk" {
+		t.Errorf("expected default branch to be 'trunk', got %q", branch)
+	}
+}
+
+func mustRun(t *testing.T, runner *Runner, workDir string, args ...string) {
+	t.Helper()
+	if _, err := runner.Run(context.Background(), workDir, args...); err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+}
+
+func TestRunner_IsSquashMerged(t *testing.T) {
 	runner := New(5 * time.Second)
 	ctx := context.Background()
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		mustRun(t, runner, repo, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", name)
+	}
+
+	git("init", "--initial-branch=main")
+	write("a.txt", "a\n")
+	git("commit", "-m", "initial")
+
+	// squashed: two commits landed on main as one
+	git("checkout", "-b", "squashed")
+	write("b.txt", "b\n")
+	git("commit", "-m", "b")
+	write("b.txt", "b\nmore\n")
+	git("commit", "-m", "more b")
+	// rebased: one commit cherry-picked onto main
+	git("checkout", "-b", "rebased", "main")
+	write("c.txt", "c\n")
+	git("commit", "-m", "c")
+	// amended: squashed together with another change, then main moved on
+	git("checkout", "-b", "amended", "main")
+	write("d.txt", "d\n")
+	git("commit", "-m", "d")
+	// open: not on main at all
+	git("checkout", "-b", "open", "main")
+	write("e.txt", "e\n")
+	git("commit", "-m", "e")
+	// empty: no commits of its own
+	git("branch", "empty", "main")
+	// unchanged: only an empty commit
+	git("checkout", "-b", "unchanged", "main")
+	git("commit", "--allow-empty", "-m", "wip")
+
+	git("checkout", "main")
+	git("merge", "--squash", "squashed")
+	git("commit", "-m", "squashed (#1)")
+	git("cherry-pick", "rebased")
+	git("merge", "--squash", "amended")
+	write("CHANGELOG", "d\n")
+	git("commit", "-m", "amended (#2)")
+	write("a.txt", "a\nfollow-up\n")
+	git("commit", "-m", "follow-up")
 
-	// Test getting default branch (should be "main" or "master")
-	branch, err := runner.GetDefaultBranch(ctx, "")
+	tests := []struct {
+		branch string
+		want   bool
+	}{
+		{branch: "squashed", want: true},
+		{branch: "rebased", want: true},
+		{branch: "amended", want: true},
+		{branch: "open", want: false},
+		{branch: "empty", want: false},
+		{branch: "unchanged", want: false},
+	}
+	for _, tt := range tests {
+		got, err := runner.IsSquashMerged(ctx, repo, tt.branch, "main")
+		if err != nil {
+			t.Fatalf("IsSquashMerged(%s) failed: %v", tt.branch, err)
+		}
+		if got != tt.want {
+			t.Errorf("IsSquashMerged(%s) = %v, want %v", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestParseWorktreeList(t *testing.T) {
+	output := `worktree /git/api/.bare
+bare
+
+worktree /git/api/main
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /git/api/usb drive
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/usb
+locked on the usb drive
+
+worktree /git/api/rebasing
+HEAD 3333333333333333333333333333333333333333
+detached
+locked
+
+worktree /git/api/gone
+HEAD 4444444444444444444444444444444444444444
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+`
+	want := []Worktree{
+		{Path: "/git/api/.bare", Bare: true},
+		{Path: "/git/api/main", Branch: "main", Commit: "1111111111111111111111111111111111111111"},
+		{Path: "/git/api/usb drive", Branch: "feature/usb", Commit: "2222222222222222222222222222222222222222", Locked: true, LockReason: "on the usb drive"},
+		{Path: "/git/api/rebasing", Commit: "3333333333333333333333333333333333333333", Detached: true, Locked: true},
+		{Path: "/git/api/gone", Branch: "gone", Commit: "4444444444444444444444444444444444444444", Prunable: true, PrunableReason: "gitdir file points to non-existent location"},
+	}
+	if got := parseWorktreeList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorktreeList() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestRunner_ListWorktrees_LockedAndPrunable(t *testing.T) {
+	runner := New(5 * time.Second)
+	ctx := context.Background()
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "main")
+
+	mustRun(t, runner, "", "init", "--initial-branch=main", repo)
+	mustRun(t, runner, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "initial")
+	mustRun(t, runner, repo, "worktree", "add", "--lock", "--reason", "usb", "-b", "locked", filepath.Join(dir, "locked"))
+	mustRun(t, runner, repo, "worktree", "add", "-b", "gone", filepath.Join(dir, "gone"))
+	if err := os.RemoveAll(filepath.Join(dir, "gone")); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees, err := runner.ListWorktrees(ctx, repo)
 	if err != nil {
-		t.Fatalf("expected to get default branch: %v", err)
+		t.Fatalf("ListWorktrees failed: %v", err)
+	}
+	byBranch := make(map[string]Worktree)
+	for _, wt := range worktrees {
+		byBranch[wt.Branch] = wt
+	}
+	if wt := byBranch["locked"]; !wt.Locked || wt.LockReason != "usb" || wt.Prunable {
+		t.Errorf("locked worktree = %+v", wt)
 	}
-	if branch != "main" && branch != "master" {
-		t.Errorf("expected default branch to be 'main' or 'master', got %q", branch)
+	if wt := byBranch["gone"]; !wt.Prunable || wt.Locked {
+		t.Errorf("removed worktree = %+v", wt)
 	}
-	t.Logf("Default branch detected: %q", branch)
 }
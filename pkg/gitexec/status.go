@@ -0,0 +1,225 @@
+package gitexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorktreeStatus summarizes the state of a working tree as reported by
+// `git status --porcelain=v2 --branch`.
+type WorktreeStatus struct {
+	Branch     string // Empty when HEAD is detached
+	Upstream   string // e.g. "origin/feature"; empty when no upstream is configured
+	Ahead      int    // Commits not on the upstream
+	Behind     int    // Upstream commits not in the branch
+	Staged     int    // Files with staged changes
+	Modified   int    // Files with unstaged changes
+	Untracked  int
+	Conflicted int
+}
+
+// IsDirty reports whether the working tree has any local changes.
+func (s WorktreeStatus) IsDirty() bool {
+	return s.Staged+s.Modified+s.Untracked+s.Conflicted > 0
+}
+
+// Status returns branch, upstream tracking and change counts for a working tree.
+func (r *Runner) Status(ctx context.Context, workDir string) (*WorktreeStatus, error) {
+	output, err := r.RunSimple(ctx, workDir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return nil, err
+	}
+	status := parseStatusV2(output)
+	return &status, nil
+}
+
+// parseStatusV2 parses `git status --porcelain=v2 --branch` output.
+func parseStatusV2(output string) WorktreeStatus {
+	var status WorktreeStatus
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); head != "(detached)" {
+				status.Branch = head
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// "# branch.ab +1 -2"
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			// "1 XY ..." where X is the index state and Y the worktree state
+			if len(line) >= 4 {
+				if line[2] != '.' {
+					status.Staged++
+				}
+				if line[3] != '.' {
+					status.Modified++
+				}
+			}
+		case strings.HasPrefix(line, "u "):
+			status.Conflicted++
+		case strings.HasPrefix(line, "? "):
+			status.Untracked++
+		}
+	}
+
+	return status
+}
+
+// AheadBehind counts the commits in head that are not in base (ahead) and the
+// commits in base that are not in head (behind).
+func (r *Runner) AheadBehind(ctx context.Context, workDir, base, head string) (ahead, behind int, err error) {
+	output, err := r.RunSimple(ctx, workDir, "rev-list", "--left-right", "--count", base+"..."+head)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Output is "<behind>\t<ahead>": left side is base, right side is head
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	if behind, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if ahead, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// LastCommitTime returns the committer date of the commit ref points to.
+func (r *Runner) LastCommitTime(ctx context.Context, workDir, ref string) (time.Time, error) {
+	output, err := r.RunSimple(ctx, workDir, "log", "-1", "--format=%ct", ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, err := strconv.ParseInt(output, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected commit time %q", output)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// UnpushedCommits counts the commits in ref that no remote-tracking branch contains.
+// Commits reachable from refs matching the published globs (e.g. "refs/pull/*")
+// count as pushed too.
+func (r *Runner) UnpushedCommits(ctx context.Context, workDir, ref string, published ...string) (int, error) {
+	args := []string{"rev-list", "--count", ref, "--not", "--remotes"}
+	for _, glob := range published {
+		args = append(args, "--glob="+glob)
+	}
+	output, err := r.RunSimple(ctx, workDir, args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(output)
+}
+
+// UnmergedCommits counts the commits in ref that would be lost with it: reachable from
+// no remote-tracking branch, no ref matching the published globs and none of the
+// merged commits (e.g. the head of a merged pull request), and without a
+// patch-equivalent commit in base (git cherry). Commits reachable from base count as
+// merged; base may be empty.
+func (r *Runner) UnmergedCommits(ctx context.Context, workDir, ref, base string, merged []string, published ...string) (int, error) {
+	args := []string{"rev-list", ref, "--not", "--remotes"}
+	for _, glob := range published {
+		args = append(args, "--glob="+glob)
+	}
+	args = append(args, merged...)
+	if base != "" {
+		args = append(args, base)
+	}
+	output, err := r.RunSimple(ctx, workDir, args...)
+	if err != nil {
+		return 0, err
+	}
+	commits := strings.Fields(output)
+	if len(commits) == 0 || base == "" {
+		return len(commits), nil
+	}
+
+	cherry, err := r.RunSimple(ctx, workDir, "cherry", base, ref)
+	if err != nil {
+		return 0, err
+	}
+	equivalent := make(map[string]bool)
+	for _, line := range strings.Split(cherry, "\n") {
+		if sha, ok := strings.CutPrefix(line, "- "); ok {
+			equivalent[sha] = true
+		}
+	}
+	n := 0
+	for _, c := range commits {
+		if !equivalent[c] {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// StashBranches counts the stash entries of a repository by the branch they were
+// made on. Entries made on a detached HEAD are counted under "".
+func (r *Runner) StashBranches(ctx context.Context, workDir string) (map[string]int, error) {
+	output, err := r.RunSimple(ctx, workDir, "stash", "list", "--format=%gs")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, line := range strings.Split(output, "\n") {
+		// "WIP on <branch>: <sha> <subject>" or "On <branch>: <message>"; branch
+		// names cannot contain ':'
+		rest, ok := strings.CutPrefix(line, "WIP on ")
+		if !ok {
+			if rest, ok = strings.CutPrefix(line, "On "); !ok {
+				continue
+			}
+		}
+		branch, _, ok := strings.Cut(rest, ":")
+		if !ok {
+			continue
+		}
+		if branch == "(no branch)" {
+			branch = ""
+		}
+		counts[branch]++
+	}
+	return counts, nil
+}
+
+// inProgressMarkers are the files git keeps in a worktree's git dir while an
+// operation waits for the user, in the order they are reported.
+var inProgressMarkers = []struct{ file, operation string }{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+	{"BISECT_LOG", "bisect"},
+}
+
+// InProgress returns the operation the worktree is in the middle of: "rebase",
+// "merge", "cherry-pick", "revert" or "bisect", or "" when there is none.
+func (r *Runner) InProgress(ctx context.Context, worktree string) (string, error) {
+	gitDir, err := r.RunSimple(ctx, worktree, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	for _, m := range inProgressMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.file)); err == nil {
+			return m.operation, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,183 @@
+package gitexec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseStatusV2(t *testing.T) {
+	output := `# branch.oid 1234567890abcdef
+# branch.head feature
+# branch.upstream origin/feature
+# branch.ab +2 -1
+1 M. N... 100644 100644 100644 abc abc staged.go
+1 .M N... 100644 100644 100644 abc abc modified.go
+1 MM N... 100644 100644 100644 abc abc both.go
+2 R. N... 100644 100644 100644 abc abc R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 abc abc abc conflict.go
+? untracked.txt
+? other.txt`
+
+	got := parseStatusV2(output)
+	want := WorktreeStatus{
+		Branch:     "feature",
+		Upstream:   "origin/feature",
+		Ahead:      2,
+		Behind:     1,
+		Staged:     3,
+		Modified:   2,
+		Untracked:  2,
+		Conflicted: 1,
+	}
+	if got != want {
+		t.Errorf("parseStatusV2() = %+v, want %+v", got, want)
+	}
+	if !got.IsDirty() {
+		t.Error("expected status to be dirty")
+	}
+}
+
+func TestParseStatusV2_DetachedClean(t *testing.T) {
+	got := parseStatusV2("# branch.oid abc\n# branch.head (detached)")
+	if got.Branch != "" || got.IsDirty() {
+		t.Errorf("unexpected status %+v", got)
+	}
+}
+
+func TestRunner_StatusAndAheadBehind(t *testing.T) {
+	runner := New(5 * time.Second)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	origin := filepath.Join(dir, "origin")
+	clone := filepath.Join(dir, "clone")
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m"}
+
+	mustRun(t, runner, "", "init", "--initial-branch=main", origin)
+	mustRun(t, runner, origin, append(commit, "initial")...)
+	mustRun(t, runner, "", "clone", origin, clone)
+
+	// One commit ahead locally, one behind after fetching a new origin commit
+	mustRun(t, runner, clone, append(commit, "local")...)
+	mustRun(t, runner, origin, append(commit, "remote")...)
+	mustRun(t, runner, clone, "fetch")
+	if err := os.WriteFile(filepath.Join(clone, "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := runner.Status(ctx, clone)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Branch != "main" || status.Upstream != "origin/main" {
+		t.Errorf("unexpected branch tracking: %+v", status)
+	}
+	if status.Ahead != 1 || status.Behind != 1 || status.Untracked != 1 {
+		t.Errorf("unexpected counts: %+v", status)
+	}
+
+	ahead, behind, err := runner.AheadBehind(ctx, clone, "origin/main", "HEAD")
+	if err != nil {
+		t.Fatalf("AheadBehind failed: %v", err)
+	}
+	if ahead != 1 || behind != 1 {
+		t.Errorf("expected 1 ahead / 1 behind, got %d / %d", ahead, behind)
+	}
+
+	when, err := runner.LastCommitTime(ctx, clone, "HEAD")
+	if err != nil {
+		t.Fatalf("LastCommitTime failed: %v", err)
+	}
+	if time.Since(when) > time.Hour {
+		t.Errorf("unexpected commit time %v", when)
+	}
+}
+
+func TestRunner_UnpushedStashesAndInProgress(t *testing.T) {
+	runner := New(5 * time.Second)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	origin := filepath.Join(dir, "origin")
+	clone := filepath.Join(dir, "clone")
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	commit := append(identity, "commit", "--allow-empty", "-m")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(clone, "a.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustRun(t, runner, "", "init", "--initial-branch=main", origin)
+	mustRun(t, runner, origin, append(commit, "initial")...)
+	mustRun(t, runner, "", "clone", origin, clone)
+	mustRun(t, runner, clone, "checkout", "-b", "feature")
+	mustRun(t, runner, clone, append(commit, "one")...)
+	mustRun(t, runner, clone, append(commit, "two")...)
+
+	if n, err := runner.UnpushedCommits(ctx, clone, "HEAD"); err != nil || n != 2 {
+		t.Errorf("UnpushedCommits = %d, %v; want 2", n, err)
+	}
+	mustRun(t, runner, clone, "update-ref", "refs/published/feature", "HEAD~1")
+	if n, err := runner.UnpushedCommits(ctx, clone, "HEAD", "refs/published/*"); err != nil || n != 1 {
+		t.Errorf("UnpushedCommits with published refs = %d, %v; want 1", n, err)
+	}
+
+	// A commit cherry-picked to origin/main is merged, one after the merged head is not
+	mustRun(t, runner, clone, append(identity, "checkout", "-b", "picked", "main")...)
+	write("picked\n")
+	mustRun(t, runner, clone, "add", "a.txt")
+	mustRun(t, runner, clone, append(identity, "commit", "-m", "picked")...)
+	mustRun(t, runner, origin, "fetch", clone, "picked")
+	mustRun(t, runner, origin, append(identity, "cherry-pick", "FETCH_HEAD")...)
+	mustRun(t, runner, clone, "fetch")
+	if n, err := runner.UnmergedCommits(ctx, clone, "HEAD", "origin/main", nil); err != nil || n != 0 {
+		t.Errorf("UnmergedCommits of a cherry-picked commit = %d, %v; want 0", n, err)
+	}
+	mergedHead, _ := runner.RunSimple(ctx, clone, "rev-parse", "feature")
+	if n, err := runner.UnmergedCommits(ctx, clone, "feature", "origin/main", []string{mergedHead + "~1"}); err != nil || n != 1 {
+		t.Errorf("UnmergedCommits after the merged head = %d, %v; want 1", n, err)
+	}
+	mustRun(t, runner, clone, "checkout", "feature")
+
+	// Two stashes on feature, one with a message, and one on main
+	for _, args := range [][]string{
+		{"stash", "push", "--include-untracked"},
+		{"stash", "push", "--include-untracked", "-m", "experiment: try it"},
+	} {
+		write("x")
+		mustRun(t, runner, clone, append(identity, args...)...)
+	}
+	mustRun(t, runner, clone, "checkout", "main")
+	write("y")
+	mustRun(t, runner, clone, append(identity, "stash", "push", "--include-untracked")...)
+
+	stashes, err := runner.StashBranches(ctx, clone)
+	if err != nil {
+		t.Fatalf("StashBranches failed: %v", err)
+	}
+	if stashes["feature"] != 2 || stashes["main"] != 1 || len(stashes) != 2 {
+		t.Errorf("StashBranches = %v", stashes)
+	}
+
+	if op, err := runner.InProgress(ctx, clone); err != nil || op != "" {
+		t.Errorf("InProgress = %q, %v; want none", op, err)
+	}
+
+	// A conflicting merge stops with MERGE_HEAD in place
+	write("main\n")
+	mustRun(t, runner, clone, "add", "a.txt")
+	mustRun(t, runner, clone, append(identity, "commit", "-m", "main side")...)
+	mustRun(t, runner, clone, "checkout", "-b", "other", "HEAD~1")
+	write("other\n")
+	mustRun(t, runner, clone, "add", "a.txt")
+	mustRun(t, runner, clone, append(identity, "commit", "-m", "other side")...)
+	runner.Run(ctx, clone, append(identity, "merge", "main")...)
+	if op, err := runner.InProgress(ctx, clone); err != nil || op != "merge" {
+		t.Errorf("InProgress = %q, %v; want merge", op, err)
+	}
+}
@@ -26,6 +26,20 @@ type ParsedURL struct {
 	Repo string
 }
 
+// Owner returns the namespace that owns the repository, i.e. everything before the last
+// path segment. For "group/subgroup/project" this is "group/subgroup".
+func (p *ParsedURL) Owner() string {
+	if i := strings.LastIndex(p.Path, "/"); i >= 0 {
+		return p.Path[:i]
+	}
+	return ""
+}
+
+// Name returns the last path segment of the repository path.
+func (p *ParsedURL) Name() string {
+	return p.Path[strings.LastIndex(p.Path, "/")+1:]
+}
+
 // Parse parses a git URL and extracts components.
 // Supports: ssh (git@...), https, http, and ssh:// formats.
 func Parse(gitURL string) (*ParsedURL, error) {
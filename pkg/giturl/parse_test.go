@@ -64,6 +64,32 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParsedURL_OwnerAndName(t *testing.T) {
+	tests := []struct {
+		url       string
+		wantOwner string
+		wantName  string
+	}{
+		{"https://github.com/user/repo.git", "user", "repo"},
+		{"git@gitlab.example.com:group/subgroup/project.git", "group/subgroup", "project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			parsed, err := Parse(tt.url)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := parsed.Owner(); got != tt.wantOwner {
+				t.Errorf("Owner() = %v, want %v", got, tt.wantOwner)
+			}
+			if got := parsed.Name(); got != tt.wantName {
+				t.Errorf("Name() = %v, want %v", got, tt.wantName)
+			}
+		})
+	}
+}
+
 func TestExtractRepoName(t *testing.T) {
 	tests := []struct {
 		url  string
@@ -0,0 +1,161 @@
+// Package output renders command results as human-readable text or as
+// machine-readable JSON/YAML documents.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Format selects how a command writes its result.
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// ParseFormat validates an --output flag value.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Text, JSON, YAML:
+		return f, nil
+	case "":
+		return Text, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (want text, json or yaml)", s)
+	}
+}
+
+// Printer writes either human-readable text or a single structured document.
+// In JSON and YAML mode all text is discarded so stdout carries exactly one
+// document that scripts can parse.
+type Printer struct {
+	format Format
+	w      io.Writer
+}
+
+// NewPrinter creates a printer writing to w.
+func NewPrinter(format Format, w io.Writer) *Printer {
+	return &Printer{format: format, w: w}
+}
+
+// Format returns the printer's output format.
+func (p *Printer) Format() Format { return p.format }
+
+// IsText reports whether the printer writes human-readable text.
+func (p *Printer) IsText() bool { return p.format == Text }
+
+// Printf writes human-readable text. It is a no-op for structured formats.
+func (p *Printer) Printf(format string, args ...any) {
+	if p.IsText() {
+		fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+// Println writes a line of human-readable text. It is a no-op for structured formats.
+func (p *Printer) Println(args ...any) {
+	if p.IsText() {
+		fmt.Fprintln(p.w, args...)
+	}
+}
+
+// Render writes v as a JSON or YAML document. It is a no-op in text mode,
+// where the command has already printed its result.
+//
+// Documents are shaped by the json struct tags of v; YAML output uses the same
+// field names and order.
+func (p *Printer) Render(v any) error {
+	switch p.format {
+	case JSON:
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case YAML:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		node, err := jsonToNode(data)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(p.w)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return nil
+	}
+}
+
+// jsonToNode converts a JSON document to a YAML node, keeping object keys in
+// document order (decoding into a map would sort them).
+func jsonToNode(data []byte) (*yaml.Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeNode(dec)
+}
+
+func decodeNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: keyTok.(string)}
+				node.Content = append(node.Content, key, value)
+			}
+			_, err := dec.Token() // closing '}'
+			return node, err
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for dec.More() {
+				value, err := decodeNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, value)
+			}
+			_, err := dec.Token() // closing ']'
+			return node, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	default:
+		return nil, fmt.Errorf("unexpected JSON token %v", tok)
+	}
+}
@@ -0,0 +1,106 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testDoc struct {
+	Repo    string   `json:"repo"`
+	Success bool     `json:"success"`
+	Count   int      `json:"count"`
+	Ratio   float64  `json:"ratio"`
+	Tags    []string `json:"tags"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{in: "", want: Text},
+		{in: "text", want: Text},
+		{in: "JSON", want: JSON},
+		{in: "yaml", want: YAML},
+		{in: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFormat(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinter_Render(t *testing.T) {
+	doc := testDoc{Repo: "api", Success: true, Count: 3, Ratio: 0.5, Tags: []string{"a", "b"}}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: JSON,
+			want: `{
+  "repo": "api",
+  "success": true,
+  "count": 3,
+  "ratio": 0.5,
+  "tags": [
+    "a",
+    "b"
+  ]
+}
+`,
+		},
+		{
+			format: YAML,
+			want: `repo: api
+success: true
+count: 3
+ratio: 0.5
+tags:
+  - a
+  - b
+`,
+		},
+		{format: Text, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewPrinter(tt.format, &buf).Render(doc); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Render() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinter_TextSuppressedForStructuredFormats(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPrinter(JSON, &buf)
+	p.Printf("✓ %s\n", "api")
+	p.Println("done")
+	if buf.Len() != 0 {
+		t.Errorf("expected no text output in json mode, got %q", buf.String())
+	}
+
+	buf.Reset()
+	p = NewPrinter(Text, &buf)
+	p.Printf("✓ %s\n", "api")
+	if buf.String() != "✓ api\n" {
+		t.Errorf("unexpected text output %q", buf.String())
+	}
+}
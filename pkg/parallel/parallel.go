@@ -0,0 +1,104 @@
+// Package parallel runs an operation on many repositories with a bounded number of
+// workers, so that 'work sync' over 150 repositories runs a few fetches at a time
+// instead of 150 at once.
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultLimit is the number of workers when none is configured.
+const DefaultLimit = 8
+
+// State is how far along an item is.
+type State int
+
+const (
+	Queued State = iota
+	Running
+	Done
+	Failed
+)
+
+// Event reports an item starting (Running) or finishing (Done or Failed).
+type Event struct {
+	Index int // Position of the item
+	State State
+	Err   error // Set when Failed
+}
+
+// Options configures Map.
+type Options struct {
+	Limit    int           // Items processed at once; DefaultLimit when 0 or less
+	Timeout  time.Duration // Per item; none when 0
+	Progress func(Event)   // Called from the workers as items start and finish; may be nil
+}
+
+// Map calls fn for each item, at most opts.Limit at a time, and returns the results
+// and errors in the order of items. The context of each call is cancelled after
+// opts.Timeout. Items not yet started when ctx is cancelled fail with its error.
+func Map[T, R any](ctx context.Context, items []T, opts Options, fn func(context.Context, T) (R, error)) ([]R, []error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	report := func(e Event) {
+		if opts.Progress != nil {
+			opts.Progress(e)
+		}
+	}
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	workers := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			for j := i; j < len(items); j++ {
+				errs[j] = ctx.Err()
+				report(Event{Index: j, State: Failed, Err: errs[j]})
+			}
+			wg.Wait()
+			return results, errs
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+			report(Event{Index: i, State: Running})
+
+			itemCtx, cancel := ctx, context.CancelFunc(func() {})
+			if opts.Timeout > 0 {
+				itemCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			}
+			defer cancel()
+
+			results[i], errs[i] = fn(itemCtx, item)
+			// A git command killed by the deadline only says it failed
+			if errors.Is(itemCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				if errs[i] == nil {
+					errs[i] = itemCtx.Err()
+				}
+				errs[i] = fmt.Errorf("timed out after %s: %w", opts.Timeout, errs[i])
+			}
+
+			if errs[i] != nil {
+				report(Event{Index: i, State: Failed, Err: errs[i]})
+			} else {
+				report(Event{Index: i, State: Done})
+			}
+		}()
+	}
+	wg.Wait()
+	return results, errs
+}
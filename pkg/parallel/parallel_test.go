@@ -0,0 +1,92 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var running, peak atomic.Int32
+	results, errs := Map(context.Background(), items, Options{Limit: 3}, func(ctx context.Context, n int) (int, error) {
+		now := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if n == 4 {
+			return 0, errors.New("four")
+		}
+		return n * n, nil
+	})
+
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d items ran at once, want at most 3", p)
+	}
+	for i, n := range items {
+		switch {
+		case n == 4 && (errs[i] == nil || errs[i].Error() != "four"):
+			t.Errorf("errs[%d] = %v, want four", i, errs[i])
+		case n != 4 && (errs[i] != nil || results[i] != n*n):
+			t.Errorf("item %d = %d, %v, want %d", n, results[i], errs[i], n*n)
+		}
+	}
+}
+
+func TestMap_Timeout(t *testing.T) {
+	items := []time.Duration{0, time.Second}
+	_, errs := Map(context.Background(), items, Options{Timeout: 20 * time.Millisecond}, func(ctx context.Context, d time.Duration) (struct{}, error) {
+		select {
+		case <-time.After(d):
+			return struct{}{}, nil
+		case <-ctx.Done():
+			return struct{}{}, ctx.Err()
+		}
+	})
+	if errs[0] != nil {
+		t.Errorf("fast item failed: %v", errs[0])
+	}
+	if !errors.Is(errs[1], context.DeadlineExceeded) || !strings.HasPrefix(errs[1].Error(), "timed out after 20ms") {
+		t.Errorf("slow item = %v, want a timeout", errs[1])
+	}
+}
+
+func TestMap_Progress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	states := map[int][]State{}
+	opts := Options{
+		Limit: 1,
+		Progress: func(e Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			states[e.Index] = append(states[e.Index], e.State)
+		},
+	}
+	// The first item cancels the run, so the others never start
+	_, errs := Map(ctx, []string{"a", "b", "c"}, opts, func(ctx context.Context, s string) (string, error) {
+		cancel()
+		return s, nil
+	})
+
+	want := map[int][]State{0: {Running, Done}, 1: {Failed}, 2: {Failed}}
+	for i, w := range want {
+		if got := states[i]; len(got) != len(w) || got[0] != w[0] || got[len(got)-1] != w[len(w)-1] {
+			t.Errorf("item %d states = %v, want %v", i, got, w)
+		}
+	}
+	if errs[0] != nil || !errors.Is(errs[1], context.Canceled) || !errors.Is(errs[2], context.Canceled) {
+		t.Errorf("errs = %v", errs)
+	}
+}
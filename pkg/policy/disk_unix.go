@@ -0,0 +1,14 @@
+//go:build unix
+
+package policy
+
+import "syscall"
+
+// FreeBytes returns the space available to unprivileged users on the disk holding path.
+func FreeBytes(path string) (int64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return int64(st.Bavail) * int64(st.Bsize), nil
+}
@@ -0,0 +1,202 @@
+// Package policy selects worktrees for removal by age, activity, count and disk space.
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+)
+
+// Rule names, as written under cleanup in the config.
+const (
+	RuleInactive    = "inactive_for"
+	RulePRClosed    = "pr_closed"
+	RuleMaxPerRepo  = "max_worktrees_per_repo"
+	RuleMinFreeDisk = "min_free_disk"
+)
+
+// Policy is a parsed set of cleanup rules. Zero fields are disabled.
+type Policy struct {
+	InactiveFor  time.Duration // Worktrees not used for this long
+	PRClosed     bool          // Branches whose pull request is closed or merged
+	MaxPerRepo   int           // Keep only the most recently used worktrees of a repository
+	MinFreeBytes int64         // Remove least recently used worktrees while free space is below this
+}
+
+// New parses the cleanup section of the config.
+func New(cfg config.CleanupPolicy) (Policy, error) {
+	p := Policy{PRClosed: cfg.PRClosed, MaxPerRepo: cfg.MaxWorktreesPerRepo}
+	var err error
+	if cfg.InactiveFor != "" {
+		if p.InactiveFor, err = ParseDuration(cfg.InactiveFor); err != nil {
+			return Policy{}, fmt.Errorf("%s: %w", RuleInactive, err)
+		}
+	}
+	if cfg.MinFreeDisk != "" {
+		if p.MinFreeBytes, err = ParseSize(cfg.MinFreeDisk); err != nil {
+			return Policy{}, fmt.Errorf("%s: %w", RuleMinFreeDisk, err)
+		}
+	}
+	if p.MaxPerRepo < 0 {
+		return Policy{}, fmt.Errorf("%s: must not be negative", RuleMaxPerRepo)
+	}
+	return p, nil
+}
+
+// Empty reports whether no rule is enabled.
+func (p Policy) Empty() bool {
+	return p == Policy{}
+}
+
+// Worktree is what the rules know about a worktree.
+type Worktree struct {
+	Key        string // Identifies the worktree in the result, e.g. its path
+	Repo       string
+	LastActive time.Time // Last opened or committed to
+	SizeBytes  int64
+	PRClosed   bool // The branch's pull request is closed or merged
+	Keep       bool // Must not be removed (e.g. uncommitted changes); still counts toward limits
+	Stale      bool // Already removed by another rule; no longer counts toward limits
+}
+
+// Match is the rule that selected a worktree, and why.
+type Match struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// Evaluate returns the worktrees p selects, keyed by Worktree.Key. Each gets the
+// first rule that matches, in the order pr_closed, inactive_for,
+// max_worktrees_per_repo, min_free_disk; the last two remove the least recently
+// used worktrees first. freeBytes is the free space on the disk holding them.
+func (p Policy) Evaluate(worktrees []Worktree, freeBytes int64, now time.Time) map[string]Match {
+	matches := make(map[string]Match)
+	removable := func(w Worktree) bool {
+		_, matched := matches[w.Key]
+		return !w.Keep && !w.Stale && !matched
+	}
+
+	for _, w := range worktrees {
+		if !removable(w) {
+			continue
+		}
+		switch {
+		case p.PRClosed && w.PRClosed:
+			matches[w.Key] = Match{Rule: RulePRClosed, Reason: "Pull request closed"}
+		case p.InactiveFor > 0 && !w.LastActive.IsZero() && now.Sub(w.LastActive) > p.InactiveFor:
+			matches[w.Key] = Match{Rule: RuleInactive, Reason: fmt.Sprintf("Not used for %s", formatIdle(now.Sub(w.LastActive)))}
+		}
+	}
+
+	// Least recently used first
+	lru := append([]Worktree(nil), worktrees...)
+	sort.SliceStable(lru, func(i, j int) bool {
+		return lru[i].LastActive.Before(lru[j].LastActive)
+	})
+
+	if p.MaxPerRepo > 0 {
+		remaining := make(map[string]int)
+		for _, w := range worktrees {
+			if _, matched := matches[w.Key]; !w.Stale && !matched {
+				remaining[w.Repo]++
+			}
+		}
+		for _, w := range lru {
+			if remaining[w.Repo] > p.MaxPerRepo && removable(w) {
+				remaining[w.Repo]--
+				matches[w.Key] = Match{Rule: RuleMaxPerRepo, Reason: fmt.Sprintf("More than %d worktrees in %s", p.MaxPerRepo, w.Repo)}
+			}
+		}
+	}
+
+	if p.MinFreeBytes > 0 && freeBytes < p.MinFreeBytes {
+		// Space freed by the worktrees already selected counts toward the goal
+		free := freeBytes
+		for _, w := range worktrees {
+			if _, matched := matches[w.Key]; w.Stale || matched {
+				free += w.SizeBytes
+			}
+		}
+		for _, w := range lru {
+			if free >= p.MinFreeBytes {
+				break
+			}
+			if removable(w) {
+				free += w.SizeBytes
+				matches[w.Key] = Match{Rule: RuleMinFreeDisk, Reason: fmt.Sprintf("%s free, below %s", formatSize(freeBytes), formatSize(p.MinFreeBytes))}
+			}
+		}
+	}
+
+	return matches
+}
+
+// ParseDuration parses a duration such as "30d", "2w" or "12h".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			days, err := strconv.Atoi(n)
+			if err != nil || days < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(days) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
+
+// ParseSize parses a size such as "20GB", "512M" or "1.5T" (powers of 1024).
+func ParseSize(s string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	number := strings.TrimRight(upper, "KMGTB")
+	multiplier := int64(1)
+	switch strings.TrimSuffix(upper[len(number):], "B") {
+	case "":
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	case "T":
+		multiplier = 1 << 40
+	default:
+		return 0, fmt.Errorf("invalid size %q (use e.g. 20GB)", s)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 20GB)", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// formatIdle renders a duration in whole days, or hours below two days, e.g. "45 days".
+func formatIdle(d time.Duration) string {
+	if d < 48*time.Hour {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(d.Hours()/24))
+}
+
+// formatSize renders bytes as e.g. "3.2 GB".
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
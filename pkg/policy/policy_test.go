@@ -0,0 +1,158 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.CleanupPolicy
+		want    Policy
+		wantErr bool
+	}{
+		{name: "empty", cfg: config.CleanupPolicy{}, want: Policy{}},
+		{
+			name: "all rules",
+			cfg:  config.CleanupPolicy{InactiveFor: "30d", PRClosed: true, MaxWorktreesPerRepo: 10, MinFreeDisk: "20GB"},
+			want: Policy{InactiveFor: 30 * 24 * time.Hour, PRClosed: true, MaxPerRepo: 10, MinFreeBytes: 20 << 30},
+		},
+		{name: "bad duration", cfg: config.CleanupPolicy{InactiveFor: "a month"}, wantErr: true},
+		{name: "bad size", cfg: config.CleanupPolicy{MinFreeDisk: "lots"}, wantErr: true},
+		{name: "negative limit", cfg: config.CleanupPolicy{MaxWorktreesPerRepo: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("New() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "2w", want: 14 * 24 * time.Hour},
+		{in: "12h", want: 12 * time.Hour},
+		{in: "d", wantErr: true},
+		{in: "-3d", wantErr: true},
+		{in: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "20GB", want: 20 << 30},
+		{in: "512m", want: 512 << 20},
+		{in: "1.5T", want: 3 << 39},
+		{in: "4096", want: 4096},
+		{in: "10 KB", want: 10 << 10},
+		{in: "GB", wantErr: true},
+		{in: "10PB", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSize(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	worktrees := []Worktree{
+		{Key: "api/a", Repo: "api", LastActive: daysAgo(1), SizeBytes: 100},
+		{Key: "api/b", Repo: "api", LastActive: daysAgo(5), SizeBytes: 100},
+		{Key: "api/c", Repo: "api", LastActive: daysAgo(10), SizeBytes: 100},
+		{Key: "api/d", Repo: "api", LastActive: daysAgo(40), SizeBytes: 100, Keep: true},
+		{Key: "api/e", Repo: "api", LastActive: daysAgo(2), SizeBytes: 100, PRClosed: true},
+		{Key: "web/a", Repo: "web", LastActive: daysAgo(60), SizeBytes: 500},
+		{Key: "web/b", Repo: "web", LastActive: daysAgo(3), SizeBytes: 500, Stale: true},
+		{Key: "web/c", Repo: "web", LastActive: daysAgo(4), SizeBytes: 500},
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		free   int64
+		want   map[string]string // key -> rule
+	}{
+		{name: "no rules", policy: Policy{}, want: map[string]string{}},
+		{
+			name:   "pr closed",
+			policy: Policy{PRClosed: true},
+			want:   map[string]string{"api/e": RulePRClosed},
+		},
+		{
+			// api/d is kept despite its age; web/b is already stale
+			name:   "inactive",
+			policy: Policy{InactiveFor: 30 * 24 * time.Hour},
+			want:   map[string]string{"web/a": RuleInactive},
+		},
+		{
+			// api counts 5 worktrees including the kept one; the two least recently used removable ones go
+			name:   "max per repo",
+			policy: Policy{MaxPerRepo: 3},
+			want:   map[string]string{"api/c": RuleMaxPerRepo, "api/b": RuleMaxPerRepo},
+		},
+		{
+			name:   "earlier rules count toward the limit",
+			policy: Policy{PRClosed: true, MaxPerRepo: 3},
+			want:   map[string]string{"api/e": RulePRClosed, "api/c": RuleMaxPerRepo},
+		},
+		{
+			// The stale web/b frees 500 of the 800 missing bytes; web/a is next in LRU order
+			name:   "min free disk",
+			policy: Policy{MinFreeBytes: 1000},
+			free:   200,
+			want:   map[string]string{"web/a": RuleMinFreeDisk},
+		},
+		{
+			name:   "enough free disk",
+			policy: Policy{MinFreeBytes: 1000},
+			free:   2000,
+			want:   map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for key, m := range tt.policy.Evaluate(worktrees, tt.free, now) {
+				got[key] = m.Rule
+				if m.Reason == "" {
+					t.Errorf("%s matched %s without a reason", key, m.Rule)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
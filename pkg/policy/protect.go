@@ -0,0 +1,15 @@
+package policy
+
+import "path"
+
+// Protected returns the first of patterns that branch matches, and whether there is
+// one. Patterns are globs where '*' does not cross '/': "release/*" protects
+// release/1.2 but not release/1.2/hotfix.
+func Protected(patterns []string, branch string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,24 @@
+package policy
+
+import "testing"
+
+func TestProtected(t *testing.T) {
+	patterns := []string{"release/*", "develop", "hotfix-*"}
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{branch: "release/1.2", want: "release/*"},
+		{branch: "release/1.2/fix", want: ""},
+		{branch: "develop", want: "develop"},
+		{branch: "hotfix-login", want: "hotfix-*"},
+		{branch: "feature/login", want: ""},
+		{branch: "releases", want: ""},
+	}
+	for _, tt := range tests {
+		got, ok := Protected(patterns, tt.branch)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Protected(%q) = %q, %v; want %q", tt.branch, got, ok, tt.want)
+		}
+	}
+}
@@ -6,14 +6,23 @@ import (
 	"time"
 
 	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
 	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+	"github.com/velvee-ai/ai-workflow/pkg/tracker"
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
 )
 
 // Services holds all application-wide singleton services.
 type Services struct {
-	Config    *config.Config
-	GitRunner *gitexec.Runner
-	// Future: WorktreeManager, CacheService, IDEOpener, GitHubClient, etc.
+	Config       *config.Config
+	GitRunner    *gitexec.Runner
+	GitHubClient forge.Forge
+	Forges       *forge.Registry
+	// Trackers resolves Jira and Linear ticket keys
+	Trackers *tracker.Registry
+	// WorktreeManager maps repositories to container folders under default_git_folder
+	WorktreeManager *workspace.Workspace
+	// Future: CacheService, IDEOpener, etc.
 }
 
 var (
@@ -34,9 +43,59 @@ func Init() error {
 		// Initialize git runner with timeout
 		gitRunner := gitexec.New(30 * time.Second)
 
+		// Initialize GitHub API client, reusing gh CLI credentials when available.
+		// The token is resolved on the first request, as 'gh auth token' takes a moment.
+		var githubClient forge.Forge = forge.NewGitHubLazy("github.com", "", func() string { return forge.GitHubToken("github.com") })
+
+		// Register forge clients per host, github.com first so config can override it.
+		// Hosts without a configured type are detected from their API on first use.
+		forges := forge.NewRegistry()
+		forges.Register(githubClient)
+		forges.EnableDetection(forge.DetectKind)
+		for _, fc := range cfg.Forges {
+			if fc.Type == "" {
+				forges.SetHostOptions(fc.Host, forge.HostOptions{APIURL: fc.APIURL, TokenEnv: fc.TokenEnv})
+				continue
+			}
+			client, err := forge.New(fc.Type, fc.Host, fc.APIURL, forge.TokenFunc(fc.Type, fc.Host, fc.TokenEnv))
+			if err != nil {
+				// Keep going with the valid entries; the error is reported once init completes
+				initErr = fmt.Errorf("invalid forge config for %s: %w", fc.Host, err)
+				continue
+			}
+			forges.Register(client)
+		}
+		if client, err := forges.ForHost("github.com"); err == nil {
+			githubClient = client
+		}
+
+		trackers := tracker.NewRegistry()
+		for _, tc := range cfg.Trackers {
+			t, err := tracker.New(tc.Type, tc.URL, tc.Email, tracker.Token(tc.Type, tc.TokenEnv), tc.InProgress)
+			if err != nil {
+				initErr = fmt.Errorf("invalid tracker config: %w", err)
+				continue
+			}
+			trackers.Register(t, tc.Projects)
+		}
+
+		// An unset or unexpandable git folder leaves the workspace unconfigured;
+		// commands that need it report that when they run
+		gitFolder, _ := config.ExpandPath(cfg.DefaultGitFolder)
+		worktreeManager := workspace.New(workspace.Options{
+			Root:                gitFolder,
+			Layout:              cfg.RepoLayout,
+			WorktreeDirTemplate: cfg.WorktreeDirTemplate,
+			ContainerLayout:     cfg.ContainerLayout,
+		}, gitRunner)
+
 		instance = &Services{
-			Config:    cfg,
-			GitRunner: gitRunner,
+			Config:          cfg,
+			GitRunner:       gitRunner,
+			GitHubClient:    githubClient,
+			Forges:          forges,
+			Trackers:        trackers,
+			WorktreeManager: worktreeManager,
 		}
 	})
 
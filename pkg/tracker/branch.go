@@ -0,0 +1,80 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/velvee-ai/ai-workflow/pkg/workspace"
+)
+
+// ErrInvalidBranchTemplate is returned when issue_branch_template does not parse or
+// renders an empty branch name.
+var ErrInvalidBranchTemplate = errors.New("invalid issue_branch_template")
+
+// BranchTemplateData is the data issue_branch_template is rendered with.
+type BranchTemplateData struct {
+	Number string // Issue number or ticket key, e.g. "42" or "PROJ-123"
+	Title  string // Issue title
+}
+
+// branchTemplateFuncs are the functions available to issue_branch_template.
+var branchTemplateFuncs = template.FuncMap{
+	"slug":  workspace.Slug,
+	"lower": strings.ToLower,
+	"trunc": trunc,
+}
+
+// trunc returns the first n characters of s.
+func trunc(n int, s string) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// BranchName renders tmpl for issue. Separators left dangling by trunc are trimmed.
+func BranchName(tmpl string, issue Issue) (string, error) {
+	t, err := template.New("issue_branch_template").Funcs(branchTemplateFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidBranchTemplate, err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, BranchTemplateData{Number: issue.Key, Title: issue.Title}); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidBranchTemplate, err)
+	}
+	name := strings.TrimSpace(b.String())
+	for strings.Contains(name, "..") {
+		name = strings.ReplaceAll(name, "..", ".")
+	}
+	name = strings.Trim(name, "-./")
+	if name == "" {
+		return "", fmt.Errorf("%w: renders an empty branch name for issue %s", ErrInvalidBranchTemplate, issue.Key)
+	}
+	return name, nil
+}
+
+// ValidateBranchTemplate checks that tmpl parses and renders a branch name.
+func ValidateBranchTemplate(tmpl string) error {
+	_, err := BranchName(tmpl, Issue{Key: "42", Title: "Example issue title"})
+	return err
+}
+
+// MatchBranch returns the branch among branches that was created for issue key:
+// name itself, or else a branch named "<key>-..." as made by earlier templates or
+// 'gh issue develop'. Keys must match exactly, so 142-fix is not a branch of issue 42.
+func MatchBranch(branches []string, name, key string) (string, bool) {
+	for _, b := range branches {
+		if b == name {
+			return b, true
+		}
+	}
+	for _, b := range branches {
+		if strings.HasPrefix(b, key+"-") {
+			return b, true
+		}
+	}
+	return "", false
+}
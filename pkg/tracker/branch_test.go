@@ -0,0 +1,69 @@
+package tracker
+
+import (
+	"errors"
+	"testing"
+)
+
+const defaultTemplate = "{{.Number}}-{{.Title | slug | trunc 40}}"
+
+func TestBranchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		issue    Issue
+		want     string
+		wantErr  bool
+	}{
+		{name: "default", template: defaultTemplate, issue: Issue{Key: "42", Title: "Crash on login!"}, want: "42-Crash-on-login"},
+		{name: "trailing separator after trunc", template: defaultTemplate, issue: Issue{Key: "7", Title: "Add the export button to the settings x page"}, want: "7-Add-the-export-button-to-the-settings-x"},
+		{name: "dots", template: "{{.Number}}-{{.Title | slug}}", issue: Issue{Key: "3", Title: "Upgrade v1...v2"}, want: "3-Upgrade-v1.v2"},
+		{name: "prefix and lower", template: "fix/{{.Number}}-{{.Title | slug | lower}}", issue: Issue{Key: "9", Title: "Fix Typo"}, want: "fix/9-fix-typo"},
+		{name: "unknown field", template: "{{.Owner}}", issue: Issue{Key: "1"}, wantErr: true},
+		{name: "empty", template: "{{if false}}x{{end}}", issue: Issue{Key: "1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BranchName(tt.template, tt.issue)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidBranchTemplate) {
+					t.Errorf("expected ErrInvalidBranchTemplate, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("BranchName() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if err := ValidateBranchTemplate(defaultTemplate); err != nil {
+		t.Errorf("ValidateBranchTemplate() = %v", err)
+	}
+}
+
+func TestMatchBranch(t *testing.T) {
+	branches := []string{"main", "142-other-issue", "42-old-title", "42-crash-on-login"}
+	tests := []struct {
+		name   string
+		branch string
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{name: "exact name first", branch: "42-crash-on-login", key: "42", want: "42-crash-on-login", wantOK: true},
+		{name: "renamed issue", branch: "42-new-title", key: "42", want: "42-old-title", wantOK: true},
+		{name: "no substring match", branch: "14-x", key: "14", wantOK: false},
+		{name: "none", branch: "7-x", key: "7", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MatchBranch(branches, tt.branch, tt.key)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("MatchBranch() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+)
+
+// Jira is a Tracker for Jira Cloud and Jira Data Center using the REST API v2.
+type Jira struct {
+	baseURL    string
+	inProgress string
+	client     *apiClient
+}
+
+// NewJira creates a Jira client for the site at baseURL, e.g. https://acme.atlassian.net.
+// With an email the token is an Atlassian API token (basic auth, Jira Cloud); without
+// one it is sent as a bearer personal access token (Jira Data Center).
+func NewJira(baseURL, email, token, inProgress string) *Jira {
+	return &Jira{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		inProgress: inProgress,
+		client: newAPIClient(baseURL, func(req *http.Request) {
+			switch {
+			case token == "":
+			case email != "":
+				req.SetBasicAuth(email, token)
+			default:
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+		}),
+	}
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// GetIssue returns the issue with key ref.Key.
+func (j *Jira) GetIssue(ctx context.Context, ref Ref) (*Issue, error) {
+	var issue jiraIssue
+	if err := j.client.do(ctx, http.MethodGet, jiraIssuePath(ref.Key)+"?fields=summary", nil, &issue); err != nil {
+		if errors.Is(err, forge.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &Issue{Key: issue.Key, Title: issue.Fields.Summary, URL: j.baseURL + "/browse/" + issue.Key}, nil
+}
+
+// Start moves the issue to the in-progress status through the first transition
+// (by name or target status) that matches it. Issues already there are left alone.
+func (j *Jira) Start(ctx context.Context, ref Ref) error {
+	var issue jiraIssue
+	if err := j.client.do(ctx, http.MethodGet, jiraIssuePath(ref.Key)+"?fields=status", nil, &issue); err != nil {
+		return err
+	}
+	if strings.EqualFold(issue.Fields.Status.Name, j.inProgress) {
+		return nil
+	}
+
+	var transitions struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.client.do(ctx, http.MethodGet, jiraIssuePath(ref.Key)+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+	for _, t := range transitions.Transitions {
+		if strings.EqualFold(t.To.Name, j.inProgress) || strings.EqualFold(t.Name, j.inProgress) {
+			body := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+			return j.client.do(ctx, http.MethodPost, jiraIssuePath(ref.Key)+"/transitions", body, nil)
+		}
+	}
+	return fmt.Errorf("%s has no transition to %q", ref.Key, j.inProgress)
+}
+
+func jiraIssuePath(key string) string {
+	return "/rest/api/2/issue/" + url.PathEscape(key)
+}
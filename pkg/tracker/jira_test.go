@@ -0,0 +1,88 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestJira(t *testing.T, handler http.HandlerFunc) *Jira {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "test-token" {
+			t.Errorf("expected basic auth, got %q", r.Header.Get("Authorization"))
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewJira(server.URL, "me@example.com", "test-token", DefaultInProgress)
+}
+
+func TestJira_GetIssue(t *testing.T) {
+	j := newTestJira(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/PROJ-123":
+			w.Write([]byte(`{"key":"PROJ-123","fields":{"summary":"Crash on login"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	issue, err := j.GetIssue(context.Background(), Ref{Key: "PROJ-123"})
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Key != "PROJ-123" || issue.Title != "Crash on login" || issue.URL != j.baseURL+"/browse/PROJ-123" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if _, err := j.GetIssue(context.Background(), Ref{Key: "PROJ-9"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestJira_Start(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		wantID  string
+		wantErr bool
+	}{
+		{name: "transitions", status: "To Do", wantID: "21"},
+		{name: "already in progress", status: "In Progress"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var posted string
+			j := newTestJira(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/rest/api/2/issue/PROJ-1":
+					w.Write([]byte(`{"key":"PROJ-1","fields":{"status":{"name":"` + tt.status + `"}}}`))
+				case r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions" && r.Method == http.MethodGet:
+					w.Write([]byte(`{"transitions":[{"id":"11","name":"Close","to":{"name":"Done"}},{"id":"21","name":"Start work","to":{"name":"In Progress"}}]}`))
+				case r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions" && r.Method == http.MethodPost:
+					var body struct {
+						Transition struct {
+							ID string `json:"id"`
+						} `json:"transition"`
+					}
+					json.NewDecoder(r.Body).Decode(&body)
+					posted = body.Transition.ID
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL)
+				}
+			})
+
+			if err := j.Start(context.Background(), Ref{Key: "PROJ-1"}); (err != nil) != tt.wantErr {
+				t.Fatalf("Start() error = %v", err)
+			}
+			if posted != tt.wantID {
+				t.Errorf("posted transition %q, want %q", posted, tt.wantID)
+			}
+		})
+	}
+}
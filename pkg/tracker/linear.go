@@ -0,0 +1,133 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// LinearAPIURL is the Linear GraphQL endpoint.
+const LinearAPIURL = "https://api.linear.app/graphql"
+
+// Linear is a Tracker for Linear using its GraphQL API.
+type Linear struct {
+	inProgress string
+	client     *apiClient
+}
+
+// NewLinear creates a Linear client. apiURL defaults to LinearAPIURL; token is a
+// personal API key.
+func NewLinear(apiURL, token, inProgress string) *Linear {
+	if apiURL == "" {
+		apiURL = LinearAPIURL
+	}
+	return &Linear{
+		inProgress: inProgress,
+		client: newAPIClient(apiURL, func(req *http.Request) {
+			if token != "" {
+				req.Header.Set("Authorization", token)
+			}
+		}),
+	}
+}
+
+// query runs a GraphQL request and decodes its data into out.
+func (l *Linear) query(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	var resp struct {
+		Data   interface{} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp.Data = out
+	body := map[string]interface{}{"query": query, "variables": variables}
+	if err := l.client.do(ctx, http.MethodPost, "", body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		if strings.Contains(strings.ToLower(resp.Errors[0].Message), "not found") {
+			return ErrNotFound
+		}
+		return fmt.Errorf("linear: %s", resp.Errors[0].Message)
+	}
+	return nil
+}
+
+// GetIssue returns the issue with identifier ref.Key, e.g. ENG-123.
+func (l *Linear) GetIssue(ctx context.Context, ref Ref) (*Issue, error) {
+	var data struct {
+		Issue *struct {
+			Identifier string `json:"identifier"`
+			Title      string `json:"title"`
+			URL        string `json:"url"`
+		} `json:"issue"`
+	}
+	q := `query($id: String!) { issue(id: $id) { identifier title url } }`
+	if err := l.query(ctx, q, map[string]interface{}{"id": ref.Key}, &data); err != nil {
+		return nil, err
+	}
+	if data.Issue == nil {
+		return nil, ErrNotFound
+	}
+	return &Issue{Key: data.Issue.Identifier, Title: data.Issue.Title, URL: data.Issue.URL}, nil
+}
+
+// Start moves the issue to the team's workflow state named like the in-progress
+// status, or else to its first "started" state. Issues already started are left alone.
+func (l *Linear) Start(ctx context.Context, ref Ref) error {
+	type state struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	var data struct {
+		Issue *struct {
+			ID    string `json:"id"`
+			State state  `json:"state"`
+			Team  struct {
+				States struct {
+					Nodes []state `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		} `json:"issue"`
+	}
+	q := `query($id: String!) { issue(id: $id) { id state { id name type } team { states { nodes { id name type } } } } }`
+	if err := l.query(ctx, q, map[string]interface{}{"id": ref.Key}, &data); err != nil {
+		return err
+	}
+	if data.Issue == nil {
+		return ErrNotFound
+	}
+	if data.Issue.State.Type == "started" || strings.EqualFold(data.Issue.State.Name, l.inProgress) {
+		return nil
+	}
+
+	var target *state
+	for i, s := range data.Issue.Team.States.Nodes {
+		if strings.EqualFold(s.Name, l.inProgress) {
+			target = &data.Issue.Team.States.Nodes[i]
+			break
+		}
+		if target == nil && s.Type == "started" {
+			target = &data.Issue.Team.States.Nodes[i]
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("%s: the team has no %q state", ref.Key, l.inProgress)
+	}
+
+	var result struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	}
+	m := `mutation($id: String!, $stateId: String!) { issueUpdate(id: $id, input: { stateId: $stateId }) { success } }`
+	if err := l.query(ctx, m, map[string]interface{}{"id": data.Issue.ID, "stateId": target.ID}, &result); err != nil {
+		return err
+	}
+	if !result.IssueUpdate.Success {
+		return fmt.Errorf("%s: could not move to %q", ref.Key, target.Name)
+	}
+	return nil
+}
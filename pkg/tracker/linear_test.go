@@ -0,0 +1,70 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestLinear starts a GraphQL stand-in; respond maps the request to a response body.
+func newTestLinear(t *testing.T, respond func(query string, variables map[string]string) string) *Linear {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "lin_api_test" {
+			t.Errorf("expected API key header, got %q", got)
+		}
+		var req struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		w.Write([]byte(respond(req.Query, req.Variables)))
+	}))
+	t.Cleanup(server.Close)
+	return NewLinear(server.URL, "lin_api_test", DefaultInProgress)
+}
+
+func TestLinear_GetIssue(t *testing.T) {
+	l := newTestLinear(t, func(query string, variables map[string]string) string {
+		if variables["id"] != "ENG-7" {
+			return `{"data":null,"errors":[{"message":"Entity not found: Issue"}]}`
+		}
+		return `{"data":{"issue":{"identifier":"ENG-7","title":"Fix the thing","url":"https://linear.app/acme/issue/ENG-7/fix-the-thing"}}}`
+	})
+
+	issue, err := l.GetIssue(context.Background(), Ref{Key: "ENG-7"})
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Key != "ENG-7" || issue.Title != "Fix the thing" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if _, err := l.GetIssue(context.Background(), Ref{Key: "ENG-8"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLinear_Start(t *testing.T) {
+	var updated map[string]string
+	l := newTestLinear(t, func(query string, variables map[string]string) string {
+		if strings.HasPrefix(query, "mutation") {
+			updated = variables
+			return `{"data":{"issueUpdate":{"success":true}}}`
+		}
+		return `{"data":{"issue":{"id":"uuid-7","state":{"id":"s1","name":"Todo","type":"unstarted"},
+			"team":{"states":{"nodes":[{"id":"s1","name":"Todo","type":"unstarted"},{"id":"s2","name":"In Review","type":"started"},{"id":"s3","name":"In Progress","type":"started"}]}}}}}`
+	})
+
+	if err := l.Start(context.Background(), Ref{Key: "ENG-7"}); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if updated["id"] != "uuid-7" || updated["stateId"] != "s3" {
+		t.Errorf("unexpected update: %v", updated)
+	}
+}
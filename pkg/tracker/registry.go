@@ -0,0 +1,92 @@
+package tracker
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Backend names accepted by New.
+const (
+	KindJira   = "jira"
+	KindLinear = "linear"
+)
+
+// DefaultInProgress is the status tickets are moved to on checkout.
+const DefaultInProgress = "In Progress"
+
+// New creates a Tracker of the given kind. url is the Jira site or the Linear API
+// endpoint (empty for the default); email is only used by Jira Cloud.
+func New(kind, url, email, token, inProgress string) (Tracker, error) {
+	if inProgress == "" {
+		inProgress = DefaultInProgress
+	}
+	switch kind {
+	case KindJira:
+		if url == "" {
+			return nil, fmt.Errorf("jira tracker needs a url")
+		}
+		return NewJira(url, email, token, inProgress), nil
+	case KindLinear:
+		return NewLinear(url, token, inProgress), nil
+	default:
+		return nil, fmt.Errorf("unknown tracker type %q (supported: %s, %s)", kind, KindJira, KindLinear)
+	}
+}
+
+// Token resolves the API token for a tracker. An explicit tokenEnv takes precedence,
+// otherwise JIRA_API_TOKEN or LINEAR_API_KEY is used.
+func Token(kind, tokenEnv string) string {
+	if tokenEnv != "" {
+		return os.Getenv(tokenEnv)
+	}
+	switch kind {
+	case KindJira:
+		return os.Getenv("JIRA_API_TOKEN")
+	case KindLinear:
+		return os.Getenv("LINEAR_API_KEY")
+	}
+	return ""
+}
+
+// Registry maps ticket keys such as PROJ-123 to the tracker that owns them.
+type Registry struct {
+	entries []registryEntry
+}
+
+type registryEntry struct {
+	tracker  Tracker
+	projects []string
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a tracker for the given Jira project or Linear team keys. A tracker
+// registered without projects handles every key no other tracker claims.
+func (r *Registry) Register(t Tracker, projects []string) {
+	r.entries = append(r.entries, registryEntry{tracker: t, projects: projects})
+}
+
+// ForKey returns the tracker for a ticket key: the one listing its project, or
+// else the first one registered without projects.
+func (r *Registry) ForKey(key string) (Tracker, bool) {
+	project, _, _ := strings.Cut(key, "-")
+	var fallback Tracker
+	for _, e := range r.entries {
+		if len(e.projects) == 0 {
+			if fallback == nil {
+				fallback = e.tracker
+			}
+			continue
+		}
+		for _, p := range e.projects {
+			if strings.EqualFold(p, project) {
+				return e.tracker, true
+			}
+		}
+	}
+	return fallback, fallback != nil
+}
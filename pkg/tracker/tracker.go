@@ -0,0 +1,158 @@
+// Package tracker resolves issue references to issues and names branches after them.
+package tracker
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+)
+
+// ErrNotFound is returned when the referenced issue does not exist.
+var ErrNotFound = errors.New("issue not found")
+
+// Issue is an issue (or ticket) a branch is created for.
+type Issue struct {
+	Key   string `json:"key"` // Issue number ("42") or ticket key ("PROJ-123")
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Ref identifies an issue in a forge repository or a ticket in Jira or Linear.
+// Host, Owner and Repo are empty when the reference does not name them, e.g.
+// "#42", "acme/api#42" or "PROJ-123".
+type Ref struct {
+	Host  string
+	Owner string
+	Repo  string
+	Key   string
+}
+
+// IsTicket reports whether ref is a Jira or Linear ticket rather than a forge issue.
+func (r Ref) IsTicket() bool {
+	return !isNumber(r.Key)
+}
+
+// String returns the ticket key or owner/repo#number.
+func (r Ref) String() string {
+	if r.IsTicket() || r.Owner == "" {
+		return r.Key
+	}
+	return r.Owner + "/" + r.Repo + "#" + r.Key
+}
+
+// Tracker looks up issues.
+type Tracker interface {
+	// GetIssue returns the issue ref points to. Returns ErrNotFound if there is none.
+	GetIssue(ctx context.Context, ref Ref) (*Issue, error)
+}
+
+// Starter is implemented by trackers that can move an issue to an in-progress state.
+type Starter interface {
+	Start(ctx context.Context, ref Ref) error
+}
+
+// ticketKey matches Jira and Linear keys such as PROJ-123.
+var ticketKey = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[1-9][0-9]*$`)
+
+// ParseRef parses an issue reference: an issue URL (.../owner/repo/issues/42, or
+// .../-/issues/42 on GitLab), "owner/repo#42", "host/owner/repo#42" or "#42", and
+// tickets: a key like PROJ-123, a Jira .../browse/PROJ-123 URL or a Linear
+// .../issue/ENG-123/... URL. ok is false when arg is none of these, e.g. a plain
+// branch name. Bare keys look like branch names too; callers decide which they are.
+func ParseRef(arg string) (ref Ref, ok bool) {
+	if ticketKey.MatchString(arg) {
+		return Ref{Key: arg}, true
+	}
+
+	if prefix, number, found := strings.Cut(arg, "#"); found {
+		if !isNumber(number) {
+			return Ref{}, false
+		}
+		if prefix == "" {
+			return Ref{Key: number}, true
+		}
+		segments := strings.Split(prefix, "/")
+		if forge.IsHostname(segments[0]) {
+			ref.Host, segments = segments[0], segments[1:]
+		}
+		if len(segments) < 2 || contains(segments, "") {
+			return Ref{}, false
+		}
+		ref.Owner = strings.Join(segments[:len(segments)-1], "/")
+		ref.Repo = segments[len(segments)-1]
+		ref.Key = number
+		return ref, true
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(arg, "https://"), "http://")
+	segments := strings.Split(strings.Trim(rest, "/"), "/")
+	if !forge.IsHostname(segments[0]) {
+		return Ref{}, false
+	}
+	for i := 1; i+1 < len(segments); i++ {
+		if (segments[i] == "browse" || segments[i] == "issue") && ticketKey.MatchString(segments[i+1]) {
+			return Ref{Host: segments[0], Key: segments[i+1]}, true
+		}
+	}
+	n := len(segments)
+	if n < 5 || segments[n-2] != "issues" || !isNumber(segments[n-1]) || !forge.IsHostname(segments[0]) {
+		return Ref{}, false
+	}
+	path := segments[1 : n-2]
+	if path[len(path)-1] == "-" {
+		path = path[:len(path)-1]
+	}
+	if len(path) < 2 {
+		return Ref{}, false
+	}
+	return Ref{
+		Host:  segments[0],
+		Owner: strings.Join(path[:len(path)-1], "/"),
+		Repo:  path[len(path)-1],
+		Key:   segments[n-1],
+	}, true
+}
+
+// Forge is a Tracker for the issues of forge repositories.
+type Forge struct {
+	client forge.Forge
+}
+
+// NewForge returns a Tracker backed by the issues of client's repositories.
+func NewForge(client forge.Forge) *Forge {
+	return &Forge{client: client}
+}
+
+// GetIssue returns the issue ref.Key of ref.Owner/ref.Repo.
+func (f *Forge) GetIssue(ctx context.Context, ref Ref) (*Issue, error) {
+	number, err := strconv.Atoi(ref.Key)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := f.client.GetIssue(ctx, ref.Owner, ref.Repo, number)
+	if errors.Is(err, forge.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Issue{Key: ref.Key, Title: issue.Title, URL: issue.URL}, nil
+}
+
+func isNumber(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && !strings.HasPrefix(s, "+")
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		arg    string
+		want   Ref
+		wantOK bool
+	}{
+		{arg: "https://github.com/acme/api/issues/42", want: Ref{Host: "github.com", Owner: "acme", Repo: "api", Key: "42"}, wantOK: true},
+		{arg: "https://gitlab.example.com/group/sub/api/-/issues/7", want: Ref{Host: "gitlab.example.com", Owner: "group/sub", Repo: "api", Key: "7"}, wantOK: true},
+		{arg: "acme/api#42", want: Ref{Owner: "acme", Repo: "api", Key: "42"}, wantOK: true},
+		{arg: "gitlab.example.com/group/api#3", want: Ref{Host: "gitlab.example.com", Owner: "group", Repo: "api", Key: "3"}, wantOK: true},
+		{arg: "#42", want: Ref{Key: "42"}, wantOK: true},
+		{arg: "PROJ-123", want: Ref{Key: "PROJ-123"}, wantOK: true},
+		{arg: "https://acme.atlassian.net/browse/PROJ-123", want: Ref{Host: "acme.atlassian.net", Key: "PROJ-123"}, wantOK: true},
+		{arg: "https://linear.app/acme/issue/ENG-7/fix-the-thing", want: Ref{Host: "linear.app", Key: "ENG-7"}, wantOK: true},
+		{arg: "proj-123"},
+		{arg: "PROJ-123-fix"},
+		{arg: "feature-42"},
+		{arg: "42-fix-login"},
+		{arg: "api#42"},
+		{arg: "acme/api#x"},
+		{arg: "https://github.com/acme/api/pull/42"},
+		{arg: "https://github.com/acme/api/issues/new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, ok := ParseRef(tt.arg)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseRef(%q) = %+v, %v; want %+v, %v", tt.arg, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestForge_GetIssue(t *testing.T) {
+	fake := forge.NewFake("github.com")
+	fake.Issues["acme/api"] = []forge.Issue{{Number: 42, Title: "Crash on login", URL: "https://github.com/acme/api/issues/42"}}
+	tr := NewForge(fake)
+
+	issue, err := tr.GetIssue(context.Background(), Ref{Owner: "acme", Repo: "api", Key: "42"})
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Key != "42" || issue.Title != "Crash on login" {
+		t.Errorf("unexpected issue: %+v", issue)
+	}
+	if _, err := tr.GetIssue(context.Background(), Ref{Owner: "acme", Repo: "api", Key: "43"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRegistry_ForKey(t *testing.T) {
+	jira := NewJira("https://acme.atlassian.net", "", "", DefaultInProgress)
+	linear := NewLinear("", "", DefaultInProgress)
+	r := NewRegistry()
+	r.Register(linear, []string{"ENG", "OPS"})
+	r.Register(jira, nil)
+
+	tests := []struct {
+		key  string
+		want Tracker
+	}{
+		{key: "ENG-1", want: linear},
+		{key: "ops-2", want: linear},
+		{key: "PROJ-3", want: jira},
+	}
+	for _, tt := range tests {
+		if got, ok := r.ForKey(tt.key); !ok || got != tt.want {
+			t.Errorf("ForKey(%q) = %T, %v", tt.key, got, ok)
+		}
+	}
+
+	if _, ok := NewRegistry().ForKey("PROJ-3"); ok {
+		t.Error("expected no tracker in an empty registry")
+	}
+}
@@ -0,0 +1,370 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrUncommittedChanges is returned when a layout conversion would have to move
+	// a working copy with uncommitted changes.
+	ErrUncommittedChanges = errors.New("uncommitted changes")
+	// ErrNoDefaultWorktree is returned when a bare container has no worktree for its
+	// default branch.
+	ErrNoDefaultWorktree = errors.New("no worktree for the default branch")
+)
+
+// gitPointer is the .git file of a bare container.
+const gitPointer = "gitdir: ./" + BareDir + "\n"
+
+// DefaultWorktree returns the working copy of the default branch: main/ in a main
+// container, the worktree that has defaultBranch checked out in a bare one.
+func (w *Workspace) DefaultWorktree(ctx context.Context, container, defaultBranch string) (string, error) {
+	if !IsBare(container) {
+		return MainPath(container), nil
+	}
+	if path, ok := w.FindWorktree(ctx, container, defaultBranch); ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("%w '%s' in %s", ErrNoDefaultWorktree, defaultBranch, container)
+}
+
+// SetupBare finishes a bare clone in <container>/.bare (made with git clone --bare):
+// it points <container>/.git at it, fetches remote-tracking branches and adds a
+// worktree for the default branch, whose path is returned.
+func (w *Workspace) SetupBare(ctx context.Context, container string) (string, error) {
+	if err := os.WriteFile(filepath.Join(container, ".git"), []byte(gitPointer), 0644); err != nil {
+		return "", err
+	}
+
+	// A bare clone maps remote branches onto local ones; track them as origin/* instead
+	steps := [][]string{
+		{"config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"},
+		{"fetch", "origin"},
+	}
+	for _, args := range steps {
+		if _, err := w.runner.Run(ctx, container, args...); err != nil {
+			return "", err
+		}
+	}
+
+	// HEAD of a fresh bare clone is the remote's default branch
+	defaultBranch, err := w.runner.RunSimple(ctx, container, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.runner.Run(ctx, container, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/"+defaultBranch); err != nil {
+		return "", err
+	}
+
+	path, err := w.NewWorktreePath(ctx, container, defaultBranch)
+	if err != nil {
+		return "", err
+	}
+	for _, args := range [][]string{
+		{"worktree", "add", path, defaultBranch},
+		{"branch", "--set-upstream-to=origin/" + defaultBranch, defaultBranch},
+	} {
+		if _, err := w.runner.Run(ctx, container, args...); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// ConvertToBare turns a main container into a bare one. The repository moves from
+// main/.git to .bare and main/ stays in place as an ordinary worktree of the branch
+// it had checked out, keeping untracked and ignored files. Tracked files in main/
+// must not have uncommitted changes. If a step fails, the steps before it are
+// undone and the container is left as it was.
+func (w *Workspace) ConvertToBare(ctx context.Context, container string) (err error) {
+	if IsBare(container) {
+		return nil
+	}
+	mainPath := MainPath(container)
+	if err := w.requireClean(ctx, mainPath); err != nil {
+		return err
+	}
+
+	branch, err := w.runner.GetCurrentBranch(ctx, mainPath)
+	if err != nil || branch == "" || branch == "HEAD" {
+		return fmt.Errorf("%s is not on a branch", mainPath)
+	}
+	worktrees, err := w.Worktrees(ctx, container)
+	if err != nil {
+		return err
+	}
+	var others []string
+	for _, wt := range worktrees[1:] {
+		if wt.Branch == branch {
+			return fmt.Errorf("branch '%s' of %s is also checked out in '%s'", branch, mainPath, wt.Path)
+		}
+		others = append(others, wt.Path)
+	}
+
+	gitDir := filepath.Join(mainPath, ".git")
+	bareDir := filepath.Join(container, BareDir)
+	pointerFile := filepath.Join(container, ".git")
+	for _, path := range []string{bareDir, pointerFile} {
+		if _, err := os.Lstat(path); err == nil {
+			return fmt.Errorf("%w: '%s' already exists", ErrDirInUse, path)
+		}
+	}
+	// Left over from an interrupted conversion
+	scratchDir := filepath.Join(container, ".work-convert")
+	if err := os.RemoveAll(scratchDir); err != nil {
+		return err
+	}
+
+	undo := w.undoOnError(ctx, &err)
+	defer undo.run()
+	undo.git(mainPath, append([]string{"worktree", "repair"}, others...)...)
+
+	undo.git(gitDir, "config", "core.bare", "false")
+	if _, err = w.runner.Run(ctx, mainPath, "config", "core.bare", "true"); err != nil {
+		return err
+	}
+	if err = os.Rename(gitDir, bareDir); err != nil {
+		return err
+	}
+	undo.add(func() { os.Rename(bareDir, gitDir) })
+	if err = os.WriteFile(pointerFile, []byte(gitPointer), 0644); err != nil {
+		return err
+	}
+	undo.add(func() { os.Remove(pointerFile) })
+
+	// Register main/ as a worktree: create the worktree metadata in a scratch folder
+	// named main (so the worktree id is "main") and move its .git file over
+	scratch := filepath.Join(scratchDir, MainDir)
+	if err = os.MkdirAll(scratchDir, 0755); err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratchDir)
+	if _, err = w.runner.Run(ctx, container, "worktree", "add", "--no-checkout", scratch, branch); err != nil {
+		return err
+	}
+	adminDir, err := worktreeAdminDir(scratch)
+	if err != nil {
+		return err
+	}
+	undo.add(func() { os.RemoveAll(adminDir) })
+	if err = os.Rename(filepath.Join(scratch, ".git"), gitDir); err != nil {
+		return err
+	}
+	undo.add(func() { os.Remove(gitDir) })
+
+	// Point the moved worktree and the existing ones at .bare, then fill the index
+	// of main/ (left empty by --no-checkout) from HEAD without touching its files
+	if _, err = w.runner.Run(ctx, container, append([]string{"worktree", "repair", mainPath}, others...)...); err != nil {
+		return err
+	}
+	_, err = w.runner.Run(ctx, mainPath, "reset", "--quiet")
+	return err
+}
+
+// ConvertToMain turns a bare container into a main one. The default branch worktree
+// becomes main/ (it is moved there if needed) and the repository moves into main/.git.
+// A worktree for the default branch is created when there is none. If a step fails,
+// the steps before it are undone and the container is left as it was.
+func (w *Workspace) ConvertToMain(ctx context.Context, container string) (err error) {
+	if !IsBare(container) {
+		return nil
+	}
+
+	defaultBranch, err := w.runner.GetDefaultBranch(ctx, container)
+	if err != nil {
+		if defaultBranch, err = w.runner.RunSimple(ctx, container, "symbolic-ref", "--short", "HEAD"); err != nil {
+			return fmt.Errorf("could not determine default branch: %w", err)
+		}
+	}
+
+	mainPath := MainPath(container)
+	current, err := w.DefaultWorktree(ctx, container, defaultBranch)
+	switch {
+	case errors.Is(err, ErrNoDefaultWorktree):
+		current = ""
+	case err != nil:
+		return err
+	default:
+		if err := w.requireClean(ctx, current); err != nil {
+			return err
+		}
+	}
+	if current == "" || !samePath(current, mainPath) {
+		if _, err := os.Stat(mainPath); err == nil {
+			return fmt.Errorf("%w: cannot move the %s worktree to '%s'", ErrDirInUse, defaultBranch, mainPath)
+		}
+	}
+
+	// What the steps below change, to put it back on failure
+	pointerFile := filepath.Join(container, ".git")
+	pointer, err := os.ReadFile(pointerFile)
+	if err != nil {
+		return err
+	}
+	head, err := w.runner.RunSimple(ctx, container, "symbolic-ref", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	undo := w.undoOnError(ctx, &err)
+	defer undo.run()
+
+	switch {
+	case current == "":
+		if _, err = w.runner.Run(ctx, container, "worktree", "add", mainPath, defaultBranch); err != nil {
+			// git keeps the worktree when only its post-checkout hook failed
+			w.runner.RunIgnoreError(context.WithoutCancel(ctx), container, "worktree", "remove", "--force", mainPath)
+			return err
+		}
+		undo.git(container, "worktree", "remove", "--force", mainPath)
+	case !samePath(current, mainPath):
+		if _, err = w.runner.Run(ctx, container, "worktree", "move", current, mainPath); err != nil {
+			return err
+		}
+		undo.git(container, "worktree", "move", mainPath, current)
+	}
+
+	worktrees, err := w.Worktrees(ctx, container)
+	if err != nil {
+		return err
+	}
+	var others []string
+	for _, wt := range worktrees {
+		if !wt.Bare && !samePath(wt.Path, mainPath) {
+			others = append(others, wt.Path)
+		}
+	}
+	if len(others) > 0 {
+		undo.git(container, append([]string{"worktree", "repair"}, others...)...)
+	}
+
+	// main/.git is a file naming its worktree metadata folder .bare/worktrees/<id>
+	gitDir := filepath.Join(mainPath, ".git")
+	mainPointer, err := os.ReadFile(gitDir)
+	if err != nil {
+		return err
+	}
+	adminDir, err := worktreeAdminDir(mainPath)
+	if err != nil {
+		return err
+	}
+	id := filepath.Base(adminDir)
+
+	bareDir := filepath.Join(container, BareDir)
+	if err = os.Remove(gitDir); err != nil {
+		return err
+	}
+	undo.add(func() { os.WriteFile(gitDir, mainPointer, 0644) })
+	if err = os.Rename(bareDir, gitDir); err != nil {
+		return err
+	}
+	undo.add(func() { os.Rename(gitDir, bareDir) })
+	if err = os.Remove(pointerFile); err != nil {
+		return err
+	}
+	undo.add(func() { os.WriteFile(pointerFile, pointer, 0644) })
+
+	// The worktree's index becomes the repository's
+	adminDir = filepath.Join(gitDir, "worktrees", id)
+	if err = os.Rename(filepath.Join(adminDir, "index"), filepath.Join(gitDir, "index")); err != nil {
+		return err
+	}
+	undo.add(func() { os.Rename(filepath.Join(gitDir, "index"), filepath.Join(adminDir, "index")) })
+
+	// Its metadata is no longer needed; it is set aside until the conversion is done,
+	// since git would take it for a worktree at main/
+	scratchDir := filepath.Join(container, ".work-convert")
+	if err = os.RemoveAll(scratchDir); err != nil {
+		return err
+	}
+	if err = os.Mkdir(scratchDir, 0755); err != nil {
+		return err
+	}
+	// Removed only after the metadata is moved back when undoing
+	undo.add(func() { os.RemoveAll(scratchDir) })
+	if err = os.Rename(adminDir, filepath.Join(scratchDir, id)); err != nil {
+		return err
+	}
+	undo.add(func() { os.Rename(filepath.Join(scratchDir, id), adminDir) })
+
+	undo.git(gitDir, "config", "core.bare", "true")
+	undo.git(gitDir, "symbolic-ref", "HEAD", head)
+	steps := [][]string{
+		{"config", "core.bare", "false"},
+		{"symbolic-ref", "HEAD", "refs/heads/" + defaultBranch},
+	}
+	if len(others) > 0 {
+		steps = append(steps, append([]string{"worktree", "repair"}, others...))
+	}
+	for _, args := range steps {
+		if _, err = w.runner.Run(ctx, mainPath, args...); err != nil {
+			return err
+		}
+	}
+	os.RemoveAll(scratchDir)
+	return nil
+}
+
+// undoStack holds how to undo the steps of a layout conversion done so far.
+type undoStack struct {
+	w     *Workspace
+	ctx   context.Context
+	err   *error
+	steps []func()
+}
+
+// undoOnError returns an undo stack that is run by its run method when *err is set.
+// Undo steps run even when ctx is cancelled.
+func (w *Workspace) undoOnError(ctx context.Context, err *error) *undoStack {
+	return &undoStack{w: w, ctx: context.WithoutCancel(ctx), err: err}
+}
+
+// add records how to undo the step just done.
+func (u *undoStack) add(step func()) { u.steps = append(u.steps, step) }
+
+// git records a git command undoing the step just done.
+func (u *undoStack) git(dir string, args ...string) {
+	u.add(func() { u.w.runner.RunIgnoreError(u.ctx, dir, args...) })
+}
+
+// run undoes the recorded steps in reverse order if the conversion failed.
+func (u *undoStack) run() {
+	if *u.err == nil {
+		return
+	}
+	for i := len(u.steps) - 1; i >= 0; i-- {
+		u.steps[i]()
+	}
+}
+
+// worktreeAdminDir returns the metadata folder under the repository's worktrees/
+// that the .git file of the worktree at path points to.
+func worktreeAdminDir(path string) (string, error) {
+	pointer, err := os.ReadFile(filepath.Join(path, ".git"))
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(strings.TrimPrefix(string(pointer), "gitdir:"))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(path, dir)
+	}
+	return dir, nil
+}
+
+// requireClean fails with ErrUncommittedChanges when tracked files in path have
+// uncommitted changes. Untracked files stay where they are during a conversion.
+func (w *Workspace) requireClean(ctx context.Context, path string) error {
+	status, err := w.runner.RunSimple(ctx, path, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return err
+	}
+	if status != "" {
+		return fmt.Errorf("%w in %s", ErrUncommittedChanges, path)
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+)
+
+// layoutFixture is an origin repository with main and feat branches.
+type layoutFixture struct {
+	runner *gitexec.Runner
+	root   string
+	origin string
+}
+
+func newLayoutFixture(t *testing.T) *layoutFixture {
+	t.Helper()
+	f := &layoutFixture{runner: gitexec.New(10 * time.Second), root: t.TempDir()}
+	f.origin = filepath.Join(f.root, "origin")
+	f.git(t, "", "init", "--initial-branch=main", f.origin)
+	if err := os.WriteFile(filepath.Join(f.origin, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f.git(t, f.origin, "add", "README")
+	f.git(t, f.origin, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "initial")
+	f.git(t, f.origin, "branch", "feat")
+	return f
+}
+
+func (f *layoutFixture) git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := f.runner.RunSimple(context.Background(), dir, args...)
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return out
+}
+
+func (f *layoutFixture) workspace(layout string) *Workspace {
+	return New(Options{Root: f.root, ContainerLayout: layout}, f.runner)
+}
+
+func TestSetupBare(t *testing.T) {
+	f := newLayoutFixture(t)
+	ctx := context.Background()
+	ws := f.workspace(config.ContainerLayoutBare)
+	container := filepath.Join(f.root, "api")
+
+	if err := os.MkdirAll(container, 0755); err != nil {
+		t.Fatal(err)
+	}
+	f.git(t, "", "clone", "--bare", "--single-branch", f.origin, filepath.Join(container, BareDir))
+	path, err := ws.SetupBare(ctx, container)
+	if err != nil {
+		t.Fatalf("SetupBare failed: %v", err)
+	}
+
+	if !ws.Bare() || !IsBare(container) || !IsContainer(container) {
+		t.Fatal("expected a bare container")
+	}
+	if GitDir(container) != container {
+		t.Errorf("GitDir() = %q, want the container", GitDir(container))
+	}
+	if path != filepath.Join(container, "main") {
+		t.Errorf("default worktree at %q", path)
+	}
+	if got := f.git(t, path, "rev-parse", "--abbrev-ref", "@{upstream}"); got != "origin/main" {
+		t.Errorf("upstream = %q, want origin/main", got)
+	}
+	if branch, err := f.runner.GetDefaultBranch(ctx, container); err != nil || branch != "main" {
+		t.Errorf("GetDefaultBranch() = %q, %v", branch, err)
+	}
+	if got, err := ws.DefaultWorktree(ctx, container, "main"); err != nil || got != path {
+		t.Errorf("DefaultWorktree() = %q, %v", got, err)
+	}
+	if _, err := ws.DefaultWorktree(ctx, container, "feat"); !errors.Is(err, ErrNoDefaultWorktree) {
+		t.Errorf("expected ErrNoDefaultWorktree, got %v", err)
+	}
+
+	// Remote branches check out as peer worktrees; the bare repository is listed first
+	feat, err := ws.NewWorktreePath(ctx, container, "feat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.git(t, container, "worktree", "add", feat, "feat")
+	worktrees, err := ws.Worktrees(ctx, container)
+	if err != nil || len(worktrees) != 3 || !worktrees[0].Bare {
+		t.Fatalf("Worktrees() = %+v, %v", worktrees, err)
+	}
+	if found, err := FindContainer(feat); err != nil || found != container {
+		t.Errorf("FindContainer(feat) = %q, %v", found, err)
+	}
+}
+
+func TestConvertLayout_RoundTrip(t *testing.T) {
+	f := newLayoutFixture(t)
+	ctx := context.Background()
+	ws := f.workspace(config.ContainerLayoutMain)
+	container := filepath.Join(f.root, "api")
+	mainPath := MainPath(container)
+
+	f.git(t, "", "clone", f.origin, mainPath)
+	f.git(t, mainPath, "worktree", "add", filepath.Join(container, "feat"), "feat")
+	// Untracked files survive the conversion
+	if err := os.WriteFile(filepath.Join(mainPath, ".env"), []byte("SECRET=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ws.ConvertToBare(ctx, container); err != nil {
+		t.Fatalf("ConvertToBare failed: %v", err)
+	}
+	if !IsBare(container) {
+		t.Fatal("expected a bare container")
+	}
+	if status := f.git(t, mainPath, "status", "--porcelain"); status != "?? .env" {
+		t.Errorf("main/ status after conversion = %q", status)
+	}
+	if got := f.git(t, filepath.Join(container, "feat"), "rev-parse", "--abbrev-ref", "HEAD"); got != "feat" {
+		t.Errorf("feat worktree on %q", got)
+	}
+	if path, ok := ws.FindWorktree(ctx, container, "main"); !ok || path != mainPath {
+		t.Errorf("FindWorktree(main) = %q, %v", path, ok)
+	}
+
+	if err := ws.ConvertToMain(ctx, container); err != nil {
+		t.Fatalf("ConvertToMain failed: %v", err)
+	}
+	if IsBare(container) || !IsContainer(container) {
+		t.Fatal("expected a main container")
+	}
+	if _, err := os.Stat(filepath.Join(container, ".git")); !os.IsNotExist(err) {
+		t.Errorf("expected the .git pointer to be removed, got %v", err)
+	}
+	if status := f.git(t, mainPath, "status", "--porcelain"); status != "?? .env" {
+		t.Errorf("main/ status after converting back = %q", status)
+	}
+	worktrees, err := ws.Worktrees(ctx, container)
+	if err != nil || len(worktrees) != 2 || worktrees[1].Branch != "feat" {
+		t.Fatalf("Worktrees() = %+v, %v", worktrees, err)
+	}
+	if got := f.git(t, worktrees[1].Path, "status", "--porcelain", "--branch"); got != "## feat...origin/feat" {
+		t.Errorf("feat worktree status = %q", got)
+	}
+}
+
+func TestConvertToBare_Dirty(t *testing.T) {
+	f := newLayoutFixture(t)
+	ctx := context.Background()
+	container := filepath.Join(f.root, "api")
+	mainPath := MainPath(container)
+
+	f.git(t, "", "clone", f.origin, mainPath)
+	if err := os.WriteFile(filepath.Join(mainPath, "README"), []byte("changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := f.workspace(config.ContainerLayoutMain).ConvertToBare(ctx, container)
+	if !errors.Is(err, ErrUncommittedChanges) {
+		t.Fatalf("expected ErrUncommittedChanges, got %v", err)
+	}
+	if IsBare(container) {
+		t.Error("expected the container to be left untouched")
+	}
+}
+
+func TestConvertToBare_UndoneOnFailure(t *testing.T) {
+	f := newLayoutFixture(t)
+	ctx := context.Background()
+	ws := f.workspace(config.ContainerLayoutMain)
+	container := filepath.Join(f.root, "api")
+	mainPath := MainPath(container)
+
+	f.git(t, "", "clone", f.origin, mainPath)
+	f.git(t, mainPath, "worktree", "add", filepath.Join(container, "feat"), "feat")
+	// A worktree whose folder was deleted by hand makes 'git worktree repair' fail
+	// after the repository has been moved
+	gone := filepath.Join(container, "gone")
+	f.git(t, mainPath, "worktree", "add", "-b", "gone", gone)
+	if err := os.RemoveAll(gone); err != nil {
+		t.Fatal(err)
+	}
+	before := f.git(t, mainPath, "worktree", "list", "--porcelain")
+
+	if err := ws.ConvertToBare(ctx, container); err == nil {
+		t.Fatal("expected ConvertToBare to fail")
+	}
+	if IsBare(container) {
+		t.Fatal("container left bare")
+	}
+	for _, name := range []string{BareDir, ".git", ".work-convert"} {
+		if _, err := os.Lstat(filepath.Join(container, name)); !os.IsNotExist(err) {
+			t.Errorf("%s left behind: %v", name, err)
+		}
+	}
+	if got := f.git(t, mainPath, "rev-parse", "--is-bare-repository"); got != "false" {
+		t.Errorf("core.bare = %s after undo", got)
+	}
+	if status := f.git(t, mainPath, "status", "--porcelain"); status != "" {
+		t.Errorf("main/ status after undo = %q", status)
+	}
+	if after := f.git(t, mainPath, "worktree", "list", "--porcelain"); after != before {
+		t.Errorf("worktrees after undo:\n%s\nwant:\n%s", after, before)
+	}
+	if got := f.git(t, filepath.Join(container, "feat"), "rev-parse", "--abbrev-ref", "HEAD"); got != "feat" {
+		t.Errorf("feat worktree on %q", got)
+	}
+}
+
+func TestConvertToMain_UndoneOnFailure(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, f *layoutFixture, container string)
+	}{
+		{
+			// A worktree whose folder was deleted by hand makes 'git worktree repair'
+			// fail after the repository has been moved into main/
+			name: "repair fails",
+			setup: func(t *testing.T, f *layoutFixture, container string) {
+				gone := filepath.Join(container, "gone")
+				f.git(t, container, "worktree", "add", gone, "-b", "gone")
+				if err := os.RemoveAll(gone); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			// Without a worktree for main, one is added at main/; a failing
+			// post-checkout hook makes that fail
+			name: "adding main fails",
+			setup: func(t *testing.T, f *layoutFixture, container string) {
+				f.git(t, container, "worktree", "move", MainPath(container), filepath.Join(container, "trunk"))
+				f.git(t, filepath.Join(container, "trunk"), "checkout", "--quiet", "feat")
+				hook := filepath.Join(container, BareDir, "hooks", "post-checkout")
+				if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLayoutFixture(t)
+			ctx := context.Background()
+			ws := f.workspace(config.ContainerLayoutBare)
+			container := filepath.Join(f.root, "api")
+
+			f.git(t, "", "clone", "--bare", f.origin, filepath.Join(container, BareDir))
+			if _, err := ws.SetupBare(ctx, container); err != nil {
+				t.Fatalf("SetupBare failed: %v", err)
+			}
+			f.git(t, container, "worktree", "add", filepath.Join(container, "other"), "-b", "other")
+			tt.setup(t, f, container)
+			before := f.git(t, container, "worktree", "list", "--porcelain")
+			pointer, _ := os.ReadFile(filepath.Join(container, ".git"))
+
+			if err := ws.ConvertToMain(ctx, container); err == nil {
+				t.Fatal("expected ConvertToMain to fail")
+			}
+			if !IsBare(container) {
+				t.Fatal("container no longer bare")
+			}
+			if got, _ := os.ReadFile(filepath.Join(container, ".git")); string(got) != string(pointer) {
+				t.Errorf(".git pointer after undo = %q, want %q", got, pointer)
+			}
+			if _, err := os.Stat(filepath.Join(container, ".work-convert")); !os.IsNotExist(err) {
+				t.Errorf(".work-convert left behind: %v", err)
+			}
+			if got := f.git(t, container, "rev-parse", "--is-bare-repository"); got != "true" {
+				t.Errorf("core.bare = %s after undo", got)
+			}
+			if after := f.git(t, container, "worktree", "list", "--porcelain"); after != before {
+				t.Errorf("worktrees after undo:\n%s\nwant:\n%s", after, before)
+			}
+			if status := f.git(t, filepath.Join(container, "other"), "status", "--porcelain", "--branch"); status != "## other" {
+				t.Errorf("other worktree status = %q", status)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package workspace
+
+import (
+	"context"
+	"strconv"
+)
+
+// pullRequestKey is the git config variable, under branch.<name>, recording the pull
+// request a branch was checked out for. Git drops it when the branch is deleted.
+const pullRequestKey = "work-pr"
+
+// PullHeadRefs holds the pull request heads fetched by 'checkout pr', one ref per
+// number. Their commits are on the forge although no remote-tracking branch has them.
+const PullHeadRefs = "refs/work/pull/"
+
+// issueKey and issueURLKey record the issue or ticket a branch was checked out for.
+const (
+	issueKey    = "work-issue"
+	issueURLKey = "work-issue-url"
+)
+
+// SetPullRequest records url as the pull request that branch was checked out for.
+func (w *Workspace) SetPullRequest(ctx context.Context, container, branch, url string) error {
+	_, err := w.runner.Run(ctx, GitDir(container), "config", "branch."+branch+"."+pullRequestKey, url)
+	return err
+}
+
+// PullRequest returns the pull request URL recorded for branch, or "" if there is none.
+func (w *Workspace) PullRequest(ctx context.Context, container, branch string) string {
+	return w.runner.RunIgnoreError(ctx, GitDir(container), "config", "--get", "branch."+branch+"."+pullRequestKey)
+}
+
+// SetPullRequestHead records head as the fetched head of pull request number.
+func (w *Workspace) SetPullRequestHead(ctx context.Context, container string, number int, head string) error {
+	_, err := w.runner.Run(ctx, GitDir(container), "update-ref", PullHeadRefs+strconv.Itoa(number), head)
+	return err
+}
+
+// SetIssue records the issue or ticket key (42, PROJ-123) and URL that branch was
+// checked out for.
+func (w *Workspace) SetIssue(ctx context.Context, container, branch, key, url string) error {
+	gitDir := GitDir(container)
+	if _, err := w.runner.Run(ctx, gitDir, "config", "branch."+branch+"."+issueKey, key); err != nil {
+		return err
+	}
+	_, err := w.runner.Run(ctx, gitDir, "config", "branch."+branch+"."+issueURLKey, url)
+	return err
+}
+
+// Issue returns the issue key and URL recorded for branch, or empty strings.
+func (w *Workspace) Issue(ctx context.Context, container, branch string) (key, url string) {
+	gitDir := GitDir(container)
+	key = w.runner.RunIgnoreError(ctx, gitDir, "config", "--get", "branch."+branch+"."+issueKey)
+	url = w.runner.RunIgnoreError(ctx, gitDir, "config", "--get", "branch."+branch+"."+issueURLKey)
+	return key, url
+}
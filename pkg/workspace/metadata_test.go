@@ -0,0 +1,62 @@
+package workspace
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+)
+
+func TestPullRequestMetadata(t *testing.T) {
+	f := newLayoutFixture(t)
+	ctx := context.Background()
+	ws := f.workspace(config.ContainerLayoutMain)
+	container := filepath.Join(f.root, "api")
+	f.git(t, "", "clone", f.origin, MainPath(container))
+	f.git(t, MainPath(container), "branch", "pr-42")
+
+	if got := ws.PullRequest(ctx, container, "pr-42"); got != "" {
+		t.Errorf("PullRequest() before recording = %q", got)
+	}
+	url := "https://github.com/acme/api/pull/42"
+	if err := ws.SetPullRequest(ctx, container, "pr-42", url); err != nil {
+		t.Fatalf("SetPullRequest failed: %v", err)
+	}
+	if got := ws.PullRequest(ctx, container, "pr-42"); got != url {
+		t.Errorf("PullRequest() = %q, want %q", got, url)
+	}
+
+	if err := ws.SetPullRequestHead(ctx, container, 42, "HEAD"); err != nil {
+		t.Fatalf("SetPullRequestHead failed: %v", err)
+	}
+	if _, err := ws.runner.Run(ctx, MainPath(container), "rev-parse", "--verify", PullHeadRefs+"42"); err != nil {
+		t.Errorf("%s42 was not created: %v", PullHeadRefs, err)
+	}
+
+	// The record goes away with the branch
+	f.git(t, MainPath(container), "branch", "-D", "pr-42")
+	if got := ws.PullRequest(ctx, container, "pr-42"); got != "" {
+		t.Errorf("PullRequest() after deleting the branch = %q", got)
+	}
+}
+
+func TestIssueMetadata(t *testing.T) {
+	f := newLayoutFixture(t)
+	ctx := context.Background()
+	ws := f.workspace(config.ContainerLayoutMain)
+	container := filepath.Join(f.root, "api")
+	f.git(t, "", "clone", f.origin, MainPath(container))
+	f.git(t, MainPath(container), "branch", "PROJ-7-login")
+
+	if key, url := ws.Issue(ctx, container, "PROJ-7-login"); key != "" || url != "" {
+		t.Errorf("Issue() before recording = %q, %q", key, url)
+	}
+	url := "https://acme.atlassian.net/browse/PROJ-7"
+	if err := ws.SetIssue(ctx, container, "PROJ-7-login", "PROJ-7", url); err != nil {
+		t.Fatalf("SetIssue failed: %v", err)
+	}
+	if key, got := ws.Issue(ctx, container, "PROJ-7-login"); key != "PROJ-7" || got != url {
+		t.Errorf("Issue() = %q, %q", key, got)
+	}
+}
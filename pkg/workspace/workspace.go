@@ -0,0 +1,323 @@
+// Package workspace defines the on-disk layout of repositories under the git folder.
+//
+// Every repository lives in a container folder holding the primary clone in main/
+// and one sibling folder per branch worktree:
+//
+//	<git_folder>/<repo>/main                     github.com, flat layout
+//	<git_folder>/<owner>/<repo>/main             github.com, org layout or name collision
+//	<git_folder>/<host>/<owner>/<repo>/main      other hosts
+//	<git_folder>/<repo>/<branch>                 worktrees next to main
+//
+// Bare containers hold the repository in .bare/ with a .git file pointing at it, and
+// every branch, the default one included, is a worktree:
+//
+//	<git_folder>/<repo>/.bare                    bare repository
+//	<git_folder>/<repo>/<branch>                 worktrees
+//
+// Worktree folder names come from worktree_dir_template; existing worktrees are always
+// found through git worktree list, never by folder name.
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/forge"
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+	"github.com/velvee-ai/ai-workflow/pkg/giturl"
+)
+
+// MainDir is the folder inside a container that holds the primary clone.
+const MainDir = "main"
+
+// BareDir is the folder inside a bare container that holds the repository.
+const BareDir = ".bare"
+
+// maxDepth bounds how deep discovery descends below the git folder.
+// host/group/subgroup/repo layouts need a few levels; anything deeper is not ours.
+const maxDepth = 6
+
+var (
+	// ErrNotConfigured is returned when no git folder is configured.
+	ErrNotConfigured = errors.New("default_git_folder not configured")
+	// ErrNotInContainer is returned when a directory is not inside a repository container.
+	ErrNotInContainer = errors.New("not in a git repo or container folder with main subfolder")
+)
+
+// Options configures a Workspace.
+type Options struct {
+	Root                string // Expanded git folder, empty when not configured
+	Layout              string // config.RepoLayoutFlat or config.RepoLayoutOrg
+	WorktreeDirTemplate string // Folder name of new worktrees; config.DefaultWorktreeDirTemplate when empty
+	ContainerLayout     string // Layout of new clones: config.ContainerLayoutMain (default) or config.ContainerLayoutBare
+}
+
+// Workspace resolves repositories to container folders under the git folder.
+type Workspace struct {
+	root        string // Expanded git folder, empty when not configured
+	layout      string // config.RepoLayoutFlat or config.RepoLayoutOrg
+	dirTemplate string
+	bare        bool // New clones use the bare layout
+	runner      *gitexec.Runner
+}
+
+// New creates a workspace from opts. runner is used to read origin remotes when
+// telling same-named repositories apart and to list worktrees.
+func New(opts Options, runner *gitexec.Runner) *Workspace {
+	dirTemplate := opts.WorktreeDirTemplate
+	if dirTemplate == "" {
+		dirTemplate = config.DefaultWorktreeDirTemplate
+	}
+	return &Workspace{
+		root:        opts.Root,
+		layout:      opts.Layout,
+		dirTemplate: dirTemplate,
+		bare:        opts.ContainerLayout == config.ContainerLayoutBare,
+		runner:      runner,
+	}
+}
+
+// Bare reports whether new clones use the bare layout.
+func (w *Workspace) Bare() bool {
+	return w.bare
+}
+
+// Root returns the git folder.
+func (w *Workspace) Root() (string, error) {
+	if w.root == "" {
+		return "", ErrNotConfigured
+	}
+	return w.root, nil
+}
+
+// MainPath returns the primary clone of a container.
+func MainPath(container string) string {
+	return filepath.Join(container, MainDir)
+}
+
+// IsContainer reports whether dir is a container with a main/ clone or a bare repository.
+func IsContainer(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, MainDir, ".git"))
+	return err == nil || IsBare(dir)
+}
+
+// IsBare reports whether dir is a bare container.
+func IsBare(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, BareDir, "HEAD"))
+	return err == nil
+}
+
+// GitDir returns the folder to run repository-wide git commands (fetch, worktree
+// list, config) in: main/ for main containers, the container itself for bare ones.
+func GitDir(container string) string {
+	if IsBare(container) {
+		return container
+	}
+	return MainPath(container)
+}
+
+// Key returns the folder (relative to the git folder) and completion name of a
+// repository. Repositories on github.com keep their bare name in the flat layout and
+// use owner/name in the org layout; other hosts are qualified as host/owner/name so
+// same-named repos never share a folder.
+func (w *Workspace) Key(host, owner, name string) string {
+	if host == "" || host == forge.DefaultHost {
+		if owner == "" || w.layout != config.RepoLayoutOrg {
+			return name
+		}
+		return owner + "/" + name
+	}
+	return filepath.ToSlash(filepath.Join(host, owner, name))
+}
+
+// QualifiedKey returns a repo argument that identifies a repository unambiguously:
+// owner/name on github.com, host/owner/name elsewhere.
+func QualifiedKey(host, owner, name string) string {
+	if host == "" || host == forge.DefaultHost {
+		return owner + "/" + name
+	}
+	return host + "/" + owner + "/" + name
+}
+
+// ParseRepoArg splits a repo argument into its parts. Accepted forms are a bare name
+// ("api"), owner/name on github.com ("acme-labs/api") and host-qualified keys
+// ("gitlab.example.com/group/sub/api"). Host and owner are empty for bare names.
+func ParseRepoArg(arg string) (host, owner, name string) {
+	arg = strings.Trim(arg, "/")
+	idx := strings.LastIndex(arg, "/")
+	if idx < 0 {
+		return "", "", arg
+	}
+	prefix, name := arg[:idx], arg[idx+1:]
+
+	first, rest, hasSlash := strings.Cut(prefix, "/")
+	if hasSlash && forge.IsHostname(first) {
+		return first, rest, name
+	}
+	return forge.DefaultHost, prefix, name
+}
+
+// KeyForPath returns the key of a container folder: its path relative to the git
+// folder, or its base name when it lies outside.
+func (w *Workspace) KeyForPath(container string) string {
+	if w.root == "" {
+		return filepath.Base(container)
+	}
+	rel, err := filepath.Rel(w.root, container)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(container)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Discover walks the git folder and returns every container. Containers are never
+// descended into, so worktree folders are not mistaken for repositories.
+func (w *Workspace) Discover() ([]string, error) {
+	root, err := w.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []string
+
+	var walk func(dir string, depth int) error
+	walk = func(dir string, depth int) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if IsContainer(path) {
+				containers = append(containers, path)
+				continue
+			}
+			if depth < maxDepth {
+				// Unreadable subfolders are skipped rather than failing discovery
+				walk(path, depth+1)
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, 1); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// Origin parses the origin remote of a container's main clone.
+func (w *Workspace) Origin(container string) (*giturl.ParsedURL, error) {
+	originURL, err := w.runner.RunSimple(context.Background(), GitDir(container), "remote", "get-url", "origin")
+	if err != nil {
+		return nil, err
+	}
+	return giturl.Parse(originURL)
+}
+
+// originMatches reports whether the origin remote of a container points at host/owner/name.
+func (w *Workspace) originMatches(container, host, owner, name string) bool {
+	origin, err := w.Origin(container)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(origin.Host, host) &&
+		strings.EqualFold(origin.Owner(), owner) &&
+		strings.EqualFold(origin.Name(), name)
+}
+
+// Resolve returns the container folder for a repo argument. The argument is first
+// taken as a path under the git folder. Otherwise a bare name matches the only
+// container with that name, and an owner-qualified name matches the container whose
+// origin is that repository (e.g. acme-labs/api cloned flat as api/).
+func (w *Workspace) Resolve(arg string) (string, bool) {
+	if w.root == "" {
+		return "", false
+	}
+
+	container := filepath.Join(w.root, filepath.FromSlash(arg))
+	if IsContainer(container) {
+		return container, true
+	}
+
+	containers, err := w.Discover()
+	if err != nil {
+		return "", false
+	}
+
+	host, owner, name := ParseRepoArg(arg)
+	var match string
+	for _, c := range containers {
+		if filepath.Base(c) != name {
+			continue
+		}
+		if owner != "" {
+			if w.originMatches(c, host, owner, name) {
+				return c, true
+			}
+			continue
+		}
+		if match != "" {
+			// Ambiguous bare name
+			return "", false
+		}
+		match = c
+	}
+	return match, match != ""
+}
+
+// ContainerFor returns the folder a repository is cloned into. In the flat layout
+// a same-named github.com repo from another org may already own <repo>/; the clone
+// then goes to <owner>/<repo>/ instead.
+func (w *Workspace) ContainerFor(host, owner, name string) string {
+	container := filepath.Join(w.root, filepath.FromSlash(w.Key(host, owner, name)))
+	if IsContainer(container) && owner != "" && !w.originMatches(container, host, owner, name) {
+		container = filepath.Join(w.root, filepath.FromSlash(QualifiedKey(host, owner, name)))
+	}
+	return container
+}
+
+// ContainerForURL returns the container folder a clone URL is checked out into.
+func (w *Workspace) ContainerForURL(gitURL string) (string, error) {
+	parsed, err := giturl.Parse(gitURL)
+	if err != nil {
+		return "", err
+	}
+	return w.ContainerFor(parsed.Host, parsed.Owner(), parsed.Name()), nil
+}
+
+// FindContainerFromCwd returns the container of the current directory.
+func (w *Workspace) FindContainerFromCwd() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return FindContainer(cwd)
+}
+
+// FindContainer returns the container holding dir: dir itself, or the nearest parent
+// that is a container. This covers the container folder, main/ and worktrees of
+// branches with slashes (<container>/feature/x) in both layouts.
+func FindContainer(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if IsContainer(dir) {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNotInContainer
+		}
+		dir = parent
+	}
+}
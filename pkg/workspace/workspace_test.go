@@ -0,0 +1,219 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/velvee-ai/ai-workflow/pkg/config"
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+)
+
+// makeContainer creates a container folder with a main/.git marker below root.
+func makeContainer(t *testing.T, root, key string) string {
+	t.Helper()
+	container := filepath.Join(root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Join(container, MainDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return container
+}
+
+func TestParseRepoArg(t *testing.T) {
+	tests := []struct {
+		arg                           string
+		wantHost, wantOwner, wantName string
+	}{
+		{arg: "api", wantName: "api"},
+		{arg: "acme-labs/api", wantHost: "github.com", wantOwner: "acme-labs", wantName: "api"},
+		{arg: "gitlab.example.com/group/sub/api", wantHost: "gitlab.example.com", wantOwner: "group/sub", wantName: "api"},
+		{arg: "group/sub/api", wantHost: "github.com", wantOwner: "group/sub", wantName: "api"},
+		{arg: "/acme-labs/api/", wantHost: "github.com", wantOwner: "acme-labs", wantName: "api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			host, owner, name := ParseRepoArg(tt.arg)
+			if host != tt.wantHost || owner != tt.wantOwner || name != tt.wantName {
+				t.Errorf("ParseRepoArg(%q) = %q, %q, %q", tt.arg, host, owner, name)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		host   string
+		owner  string
+		repo   string
+		want   string
+	}{
+		{name: "flat github", layout: config.RepoLayoutFlat, host: "github.com", owner: "acme", repo: "api", want: "api"},
+		{name: "org github", layout: config.RepoLayoutOrg, host: "github.com", owner: "acme", repo: "api", want: "acme/api"},
+		{name: "org without owner", layout: config.RepoLayoutOrg, repo: "api", want: "api"},
+		{name: "other host", layout: config.RepoLayoutFlat, host: "gitlab.example.com", owner: "g/sub", repo: "api", want: "gitlab.example.com/g/sub/api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := New(Options{Root: "/git", Layout: tt.layout}, nil)
+			if got := ws.Key(tt.host, tt.owner, tt.repo); got != tt.want {
+				t.Errorf("Key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := QualifiedKey("github.com", "acme", "api"); got != "acme/api" {
+		t.Errorf("QualifiedKey() = %q", got)
+	}
+	if got := QualifiedKey("gitlab.example.com", "g", "api"); got != "gitlab.example.com/g/api" {
+		t.Errorf("QualifiedKey() = %q", got)
+	}
+}
+
+func TestKeyForPath(t *testing.T) {
+	ws := New(Options{Root: "/git", Layout: config.RepoLayoutFlat}, nil)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/git/api", want: "api"},
+		{path: "/git/gitlab.example.com/g/api", want: "gitlab.example.com/g/api"},
+		{path: "/elsewhere/web", want: "web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ws.KeyForPath(tt.path); got != tt.want {
+				t.Errorf("KeyForPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	makeContainer(t, root, "api")
+	makeContainer(t, root, "acme/api")
+	makeContainer(t, root, "gitlab.example.com/g/sub/web")
+	makeContainer(t, root, ".hidden/tool")
+	// A worktree that looks like a container must not be reported
+	makeContainer(t, root, "api/feature")
+
+	got, err := New(Options{Root: root, Layout: config.RepoLayoutFlat}, nil).Discover()
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	var keys []string
+	for _, c := range got {
+		rel, _ := filepath.Rel(root, c)
+		keys = append(keys, filepath.ToSlash(rel))
+	}
+	sort.Strings(keys)
+	want := []string{"acme/api", "api", "gitlab.example.com/g/sub/web"}
+	if len(keys) != len(want) {
+		t.Fatalf("Discover() = %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("Discover() = %v, want %v", keys, want)
+			break
+		}
+	}
+
+	if _, err := New(Options{Layout: config.RepoLayoutFlat}, nil).Discover(); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	web := makeContainer(t, root, "web")
+	nested := makeContainer(t, root, "gitlab.example.com/g/api")
+	makeContainer(t, root, "tools/cli")
+	makeContainer(t, root, "other/cli")
+	ws := New(Options{Root: root, Layout: config.RepoLayoutFlat}, nil)
+
+	tests := []struct {
+		arg    string
+		want   string
+		wantOK bool
+	}{
+		{arg: "web", want: web, wantOK: true},
+		{arg: "gitlab.example.com/g/api", want: nested, wantOK: true},
+		{arg: "api", want: nested, wantOK: true},
+		{arg: "cli", wantOK: false},
+		{arg: "missing", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, ok := ws.Resolve(tt.arg)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Resolve(%q) = %q, %v; want %q, %v", tt.arg, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestContainerFor_NameCollision(t *testing.T) {
+	runner := gitexec.New(5 * time.Second)
+	ctx := context.Background()
+	root := t.TempDir()
+
+	// api/ is already a clone of acme/api
+	api := makeContainer(t, root, "api")
+	if err := os.RemoveAll(filepath.Join(api, MainDir, ".git")); err != nil {
+		t.Fatal(err)
+	}
+	mainPath := MainPath(api)
+	for _, args := range [][]string{
+		{"init", mainPath},
+		{"-C", mainPath, "remote", "add", "origin", "git@github.com:acme/api.git"},
+	} {
+		if _, err := runner.Run(ctx, "", args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+
+	ws := New(Options{Root: root, Layout: config.RepoLayoutFlat}, runner)
+	if got := ws.ContainerFor("github.com", "acme", "api"); got != api {
+		t.Errorf("ContainerFor(acme/api) = %q, want %q", got, api)
+	}
+	if got, want := ws.ContainerFor("github.com", "other", "api"), filepath.Join(root, "other", "api"); got != want {
+		t.Errorf("ContainerFor(other/api) = %q, want %q", got, want)
+	}
+	if got, ok := ws.Resolve("acme/api"); !ok || got != api {
+		t.Errorf("Resolve(acme/api) = %q, %v", got, ok)
+	}
+	if _, ok := ws.Resolve("other/api"); ok {
+		t.Error("expected other/api not to resolve to the acme clone")
+	}
+}
+
+func TestFindContainer(t *testing.T) {
+	root := t.TempDir()
+	container := makeContainer(t, root, "api")
+	worktree := filepath.Join(container, "feature", "x", "pkg")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{container, MainPath(container), filepath.Join(MainPath(container), ".git"), worktree} {
+		got, err := FindContainer(dir)
+		if err != nil || got != container {
+			t.Errorf("FindContainer(%q) = %q, %v; want %q", dir, got, err, container)
+		}
+	}
+
+	if _, err := FindContainer(root); !errors.Is(err, ErrNotInContainer) {
+		t.Errorf("expected ErrNotInContainer, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+)
+
+var (
+	// ErrInvalidDirTemplate is returned when worktree_dir_template does not parse or
+	// renders a folder outside the container or inside its git metadata.
+	ErrInvalidDirTemplate = errors.New("invalid worktree_dir_template")
+	// ErrDirInUse is returned when the folder for a new worktree already holds
+	// another worktree or other files.
+	ErrDirInUse = errors.New("worktree folder already in use")
+)
+
+// DirTemplateData is the data available to worktree_dir_template.
+type DirTemplateData struct {
+	Branch string // Branch name, e.g. feature/login
+	Repo   string // Repository (container folder) name
+}
+
+// dirTemplateFuncs are the functions available to worktree_dir_template.
+var dirTemplateFuncs = template.FuncMap{
+	"slug":  Slug,
+	"lower": strings.ToLower,
+}
+
+// Slug turns a branch name into a single folder name: every character other than
+// letters, digits, '.', '_' and '-' becomes '-', e.g. feature/login -> feature-login.
+func Slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if r < 0x80 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			b.WriteRune(r)
+			dash = r == '-'
+			continue
+		}
+		if !dash {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.Trim(b.String(), "-.")
+}
+
+// ValidateDirTemplate checks that tmpl parses and renders a folder inside the container.
+func ValidateDirTemplate(tmpl string) error {
+	_, err := renderDirTemplate(tmpl, DirTemplateData{Branch: "feature/example", Repo: "repo"})
+	return err
+}
+
+// renderDirTemplate renders tmpl to a clean relative path.
+func renderDirTemplate(tmpl string, data DirTemplateData) (string, error) {
+	t, err := template.New("worktree_dir_template").Funcs(dirTemplateFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDirTemplate, err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDirTemplate, err)
+	}
+
+	dir := filepath.Clean(filepath.FromSlash(strings.TrimSpace(b.String())))
+	first := strings.SplitN(dir, string(filepath.Separator), 2)[0]
+	if dir == "." || filepath.IsAbs(dir) || first == ".." || first == ".git" || first == BareDir {
+		return "", fmt.Errorf("%w: %q renders %q for branch %s", ErrInvalidDirTemplate, tmpl, b.String(), data.Branch)
+	}
+	return dir, nil
+}
+
+// WorktreePath returns the folder a new worktree of branch is created in, named by
+// worktree_dir_template.
+func (w *Workspace) WorktreePath(container, branch string) (string, error) {
+	dir, err := renderDirTemplate(w.dirTemplate, DirTemplateData{Branch: branch, Repo: filepath.Base(container)})
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(container, dir), nil
+}
+
+// WorktreeName returns the folder of a worktree relative to its container, e.g.
+// feature/login or feature-login depending on the template it was created with.
+func WorktreeName(container, path string) string {
+	rel, err := filepath.Rel(resolvePath(container), resolvePath(path))
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Worktrees lists the worktrees of a container as reported by git worktree list.
+// The main clone, or the bare repository of a bare container, comes first.
+func (w *Workspace) Worktrees(ctx context.Context, container string) ([]gitexec.Worktree, error) {
+	return w.runner.ListWorktrees(ctx, GitDir(container))
+}
+
+// FindWorktree returns the folder of the worktree that has branch checked out,
+// wherever it lives in the container.
+func (w *Workspace) FindWorktree(ctx context.Context, container, branch string) (string, bool) {
+	worktrees, err := w.Worktrees(ctx, container)
+	if err != nil {
+		return "", false
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == branch {
+			return wt.Path, true
+		}
+	}
+	return "", false
+}
+
+// NewWorktreePath returns the folder for a new worktree of branch. It fails with
+// ErrDirInUse when the folder is another branch's worktree, lies inside one, or
+// already exists with other content. The path is returned with that error so
+// callers can point at it.
+func (w *Workspace) NewWorktreePath(ctx context.Context, container, branch string) (string, error) {
+	path, err := w.WorktreePath(container, branch)
+	if err != nil {
+		return "", err
+	}
+
+	worktrees, err := w.Worktrees(ctx, container)
+	if err != nil {
+		return path, fmt.Errorf("listing worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		other := wt.Branch
+		if other == "" {
+			other = "(detached)"
+		}
+		switch {
+		case samePath(wt.Path, path):
+			return path, fmt.Errorf("%w: '%s' is the worktree of branch '%s'", ErrDirInUse, path, other)
+		case isInside(path, wt.Path):
+			return path, fmt.Errorf("%w: '%s' is inside the worktree of branch '%s'", ErrDirInUse, path, other)
+		}
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return path, nil
+	case err != nil:
+		return path, err
+	case !info.IsDir():
+		return path, fmt.Errorf("%w: '%s' is a file", ErrDirInUse, path)
+	}
+	if entries, _ := os.ReadDir(path); len(entries) > 0 {
+		return path, fmt.Errorf("%w: '%s' already exists and is not empty", ErrDirInUse, path)
+	}
+	return path, nil
+}
+
+// OrphanedFolders returns the folders in a container that are neither a registered
+// worktree, nor inside one, nor on the way to one: leftovers of worktrees removed by
+// hand or whose git metadata was pruned. Hidden folders such as .vscode are skipped.
+func (w *Workspace) OrphanedFolders(ctx context.Context, container string) ([]string, error) {
+	worktrees, err := w.Worktrees(ctx, container)
+	if err != nil {
+		return nil, fmt.Errorf("listing worktrees: %w", err)
+	}
+	registered := make([]string, 0, len(worktrees)+1)
+	registered = append(registered, resolvePath(GitDir(container)))
+	for _, wt := range worktrees {
+		registered = append(registered, resolvePath(wt.Path))
+	}
+
+	var orphans []string
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			resolved := resolvePath(path)
+			used, parent := false, false
+			for _, r := range registered {
+				used = used || r == resolved
+				parent = parent || isInside(r, resolved)
+			}
+			switch {
+			case used:
+			case parent:
+				// e.g. release/ holding the worktree release/1.2
+				if err := walk(path); err != nil {
+					return err
+				}
+			default:
+				orphans = append(orphans, path)
+			}
+		}
+		return nil
+	}
+	if err := walk(container); err != nil {
+		return nil, err
+	}
+	return orphans, nil
+}
+
+// RemoveEmptyParents deletes the folders between a removed worktree at path and its
+// container that are now empty, e.g. feature/ after feature/login is removed, so
+// they do not show up as orphaned folders. The container itself is kept.
+func RemoveEmptyParents(container, path string) {
+	for dir := filepath.Dir(path); isInside(dir, container); dir = filepath.Dir(dir) {
+		// os.Remove fails on folders that still hold anything
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+// samePath reports whether a and b name the same folder, resolving symlinks
+// (git reports worktree paths with symlinks resolved).
+func samePath(a, b string) bool {
+	return resolvePath(a) == resolvePath(b)
+}
+
+// isInside reports whether path lies below dir.
+func isInside(path, dir string) bool {
+	rel, err := filepath.Rel(resolvePath(dir), resolvePath(path))
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvePath returns path with symlinks in its longest existing prefix resolved.
+func resolvePath(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(resolvePath(parent), filepath.Base(path))
+}
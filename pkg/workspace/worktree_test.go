@@ -0,0 +1,216 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/velvee-ai/ai-workflow/pkg/gitexec"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "main", want: "main"},
+		{in: "feature/login", want: "feature-login"},
+		{in: "user/JIRA-12 fix: crash", want: "user-JIRA-12-fix-crash"},
+		{in: "release/v1.2", want: "release-v1.2"},
+		{in: "a//b", want: "a-b"},
+		{in: "/leading/", want: "leading"},
+		{in: "héllo", want: "h-llo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Slug(tt.in); got != tt.want {
+				t.Errorf("Slug(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorktreePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		branch   string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default nests slashes", branch: "feature/login", want: "/git/api/feature/login"},
+		{name: "slug", template: "{{.Branch | slug}}", branch: "feature/login", want: "/git/api/feature-login"},
+		{name: "repo prefix", template: "{{.Repo}}-{{.Branch | slug | lower}}", branch: "Feature/Login", want: "/git/api/api-feature-login"},
+		{name: "escapes container", template: "../{{.Branch}}", branch: "x", wantErr: true},
+		{name: "renders empty", template: "{{if false}}x{{end}}", branch: "x", wantErr: true},
+		{name: "unknown field", template: "{{.Owner}}", branch: "x", wantErr: true},
+		{name: "parse error", template: "{{.Branch", branch: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := New(Options{Root: "/git", WorktreeDirTemplate: tt.template}, nil)
+			got, err := ws.WorktreePath("/git/api", tt.branch)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDirTemplate) {
+					t.Errorf("expected ErrInvalidDirTemplate, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil || got != filepath.FromSlash(tt.want) {
+				t.Errorf("WorktreePath() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if err := ValidateDirTemplate("{{.Branch | slug}}"); err != nil {
+		t.Errorf("ValidateDirTemplate() = %v", err)
+	}
+}
+
+func TestNewWorktreePath_Collisions(t *testing.T) {
+	runner := gitexec.New(5 * time.Second)
+	ctx := context.Background()
+	container := filepath.Join(t.TempDir(), "api")
+	mainPath := MainPath(container)
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "initial"}
+
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main", mainPath},
+		append([]string{"-C", mainPath}, commit...),
+		{"-C", mainPath, "branch", "feature/login"},
+		{"-C", mainPath, "branch", "feature-login"},
+		{"-C", mainPath, "branch", "bugfix"},
+		{"-C", mainPath, "worktree", "add", filepath.Join(container, "feature-login"), "feature/login"},
+	} {
+		if _, err := runner.Run(ctx, "", args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(container, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws := New(Options{Root: filepath.Dir(container), WorktreeDirTemplate: "{{.Branch | slug}}"}, runner)
+
+	// feature/login is found through git, not its folder name
+	if path, ok := ws.FindWorktree(ctx, container, "feature/login"); !ok || WorktreeName(container, path) != "feature-login" {
+		t.Errorf("FindWorktree(feature/login) = %q, %v", path, ok)
+	}
+	if _, ok := ws.FindWorktree(ctx, container, "bugfix"); ok {
+		t.Error("expected no worktree for branch bugfix")
+	}
+
+	// feature-login slugs to the folder already used by feature/login
+	if _, err := ws.NewWorktreePath(ctx, container, "feature-login"); !errors.Is(err, ErrDirInUse) {
+		t.Errorf("expected ErrDirInUse for feature-login, got %v", err)
+	}
+	if path, err := ws.NewWorktreePath(ctx, container, "bugfix"); err != nil || path != filepath.Join(container, "bugfix") {
+		t.Errorf("NewWorktreePath(bugfix) = %q, %v", path, err)
+	}
+
+	nested := New(Options{Root: filepath.Dir(container)}, runner)
+	tests := []struct {
+		branch string
+		want   error
+	}{
+		{branch: "main/x", want: ErrDirInUse},          // inside the main clone
+		{branch: "feature-login/x", want: ErrDirInUse}, // inside another worktree
+		{branch: "notes.txt", want: ErrDirInUse},       // a file
+		{branch: "bugfix", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			if _, err := nested.NewWorktreePath(ctx, container, tt.branch); !errors.Is(err, tt.want) {
+				t.Errorf("NewWorktreePath(%q) error = %v, want %v", tt.branch, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrphanedFolders(t *testing.T) {
+	runner := gitexec.New(5 * time.Second)
+	ctx := context.Background()
+	container := filepath.Join(t.TempDir(), "api")
+	mainPath := MainPath(container)
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "initial"}
+
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main", mainPath},
+		append([]string{"-C", mainPath}, commit...),
+		{"-C", mainPath, "worktree", "add", "-b", "feat", filepath.Join(container, "feat")},
+		{"-C", mainPath, "worktree", "add", "-b", "release/1.2", filepath.Join(container, "release", "1.2")},
+	} {
+		if _, err := runner.Run(ctx, "", args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	for _, dir := range []string{"old/src", "release/1.1", ".vscode", "feat/build"} {
+		if err := os.MkdirAll(filepath.Join(container, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(container, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws := New(Options{Root: filepath.Dir(container)}, runner)
+	got, err := ws.OrphanedFolders(ctx, container)
+	if err != nil {
+		t.Fatalf("OrphanedFolders failed: %v", err)
+	}
+	want := []string{filepath.Join(container, "old"), filepath.Join(container, "release", "1.1")}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("OrphanedFolders() = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveEmptyParents(t *testing.T) {
+	runner := gitexec.New(5 * time.Second)
+	ctx := context.Background()
+	container := filepath.Join(t.TempDir(), "api")
+	mainPath := MainPath(container)
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "initial"}
+	login := filepath.Join(container, "feature", "auth", "login")
+	signup := filepath.Join(container, "feature", "signup")
+
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main", mainPath},
+		append([]string{"-C", mainPath}, commit...),
+		{"-C", mainPath, "worktree", "add", "-b", "feature/auth/login", login},
+		{"-C", mainPath, "worktree", "add", "-b", "feature/signup", signup},
+		{"-C", mainPath, "worktree", "remove", login},
+	} {
+		if _, err := runner.Run(ctx, "", args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	RemoveEmptyParents(container, login)
+
+	if _, err := os.Stat(filepath.Join(container, "feature", "auth")); !os.IsNotExist(err) {
+		t.Errorf("feature/auth was not removed: %v", err)
+	}
+	if _, err := os.Stat(signup); err != nil {
+		t.Errorf("feature/signup is gone: %v", err)
+	}
+	ws := New(Options{Root: filepath.Dir(container)}, runner)
+	if got, err := ws.OrphanedFolders(ctx, container); err != nil || len(got) != 0 {
+		t.Errorf("OrphanedFolders() = %v, %v; want none", got, err)
+	}
+
+	// Once the last worktree below feature/ is gone, so is feature/, but not the container
+	if _, err := runner.Run(ctx, mainPath, "worktree", "remove", signup); err != nil {
+		t.Fatal(err)
+	}
+	RemoveEmptyParents(container, signup)
+	if _, err := os.Stat(filepath.Join(container, "feature")); !os.IsNotExist(err) {
+		t.Errorf("feature was not removed: %v", err)
+	}
+	if _, err := os.Stat(container); err != nil {
+		t.Errorf("container is gone: %v", err)
+	}
+}